@@ -23,6 +23,7 @@ const (
 	instanceIDHeaderKey
 	localDevicesHeaderKey
 	authTokenHeaderKey
+	requestIDHeaderKey
 )
 
 var (
@@ -39,6 +40,8 @@ func (k headerKey) String() string {
 		return types.LocalDevicesHeader
 	case authTokenHeaderKey:
 		return types.AuthorizationHeader
+	case requestIDHeaderKey:
+		return types.RequestIDHeader
 	}
 	panic("invalid header key")
 }
@@ -57,6 +60,8 @@ func (c *client) httpDo(
 			ctx, localDevicesHeaderKey, context.CustomHeaderKey)
 		context.RegisterCustomKeyWithContext(
 			ctx, authTokenHeaderKey, context.CustomHeaderKey)
+		context.RegisterCustomKeyWithContext(
+			ctx, requestIDHeaderKey, context.CustomHeaderKey)
 	})
 
 	reqBody, err := encPayload(payload)
@@ -74,6 +79,10 @@ func (c *client) httpDo(
 	tx := context.MustTransaction(ctx)
 	ctx = ctx.WithValue(transactionHeaderKey, tx)
 
+	if rid, ok := context.RequestID(ctx); ok {
+		ctx = ctx.WithValue(requestIDHeaderKey, rid)
+	}
+
 	if iid, ok := context.InstanceID(ctx); ok {
 		ctx = ctx.WithValue(instanceIDHeaderKey, iid)
 	} else if iidMap, ok := ctx.Value(
@@ -9,6 +9,8 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/akutz/gotil"
+
+	"github.com/codedellemc/libstorage/api/utils"
 )
 
 func (c *client) logRequest(req *http.Request) {
@@ -24,7 +26,7 @@ func (c *client) logRequest(req *http.Request) {
 	fmt.Fprint(w, "HTTP REQUEST (CLIENT)")
 	fmt.Fprintln(w, " -------------------------")
 
-	buf, err := httputil.DumpRequest(req, true)
+	buf, err := utils.DumpRequest(req, true)
 	if err != nil {
 		return
 	}
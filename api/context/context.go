@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	gcontext "github.com/gorilla/context"
@@ -41,10 +42,14 @@ func newContext(
 		parent = context.Background()
 	}
 
-	// figure out who the parent logger instance is. if there is none,
-	// reference the log.StandardLogger as the parent.
+	// use the logger being set, otherwise figure out who the parent logger
+	// instance is. if there is none, reference the log.StandardLogger as the
+	// parent.
 	var logger *log.Logger
-	if ctx, ok := parent.(*lsc); ok {
+	if key == LoggerKey {
+		logger, _ = val.(*log.Logger)
+	}
+	if ctx, ok := parent.(*lsc); ok && logger == nil {
 		logger = ctx.logger
 	}
 	if logger == nil {
@@ -128,6 +133,13 @@ func WithStorageService(
 
 	parent = newContext(parent, DriverKey, driver, nil, nil)
 
+	// log at the service's level if it has one
+	if ls, ok := service.(types.StorageServiceWithLogLevel); ok {
+		if lvl, ok := ls.LogLevel(); ok {
+			parent = WithLogLevel(parent, lvl)
+		}
+	}
+
 	// set the service's InstanceID if present
 	if iidm, ok := parent.Value(AllInstanceIDsKey).(types.InstanceIDMap); ok {
 		if iid, ok := iidm[serviceName]; ok {
@@ -151,7 +163,8 @@ func WithStorageSession(parent context.Context) (types.Context, error) {
 
 	pctx := New(parent)
 
-	d, ok := MustDriver(parent).(types.StorageDriverWithLogin)
+	d, ok := types.UnwrapStorageDriver(
+		MustDriver(parent)).(types.StorageDriverWithLogin)
 	if !ok {
 		pctx.Debug("driver is not StorageDriverWithLogin")
 		return pctx, nil
@@ -360,6 +373,17 @@ func InstanceID(ctx context.Context) (*types.InstanceID, bool) {
 	return v, ok
 }
 
+// WithInstanceID returns a new context with the InstanceID of the instance
+// upon which operations act. A client's storage driver uses this instance ID
+// in place of the local instance's ID when inspecting the instance,
+// attaching and detaching volumes, and determining which of a volume's
+// attachments belong to the instance. This allows a controller to attach
+// volumes to remote instances.
+func WithInstanceID(
+	parent context.Context, iid *types.InstanceID) types.Context {
+	return newContext(parent, InstanceIDKey, iid, nil, nil)
+}
+
 // MustInstanceID returns the context's InstanceID and panics if it does not
 // exist and/or cannot be type cast.
 func MustInstanceID(ctx context.Context) *types.InstanceID {
@@ -386,6 +410,106 @@ func MustTransaction(ctx context.Context) *types.Transaction {
 	return ctx.Value(TransactionKey).(*types.Transaction)
 }
 
+// WithRequestID returns a new context with the request ID used to correlate
+// log entries. The request ID is included with every log entry emitted by
+// the context's structured logger and is sent to the server along with the
+// client's API requests.
+func WithRequestID(parent context.Context, id string) types.Context {
+	return newContext(parent, RequestIDKey, id, nil, nil)
+}
+
+// RequestID returns the context's request ID. This value is valid on both
+// the client and the server.
+func RequestID(ctx context.Context) (string, bool) {
+	return stringValue(ctx, RequestIDKey)
+}
+
+// WithVolumeObserver returns a new context with the VolumeObserver notified
+// of the changes to volumes made by the client's storage driver.
+func WithVolumeObserver(
+	parent context.Context, observer types.VolumeObserver) types.Context {
+	return newContext(parent, VolumeObserverKey, observer, nil, nil)
+}
+
+// VolumeObserver returns the context's VolumeObserver.
+func VolumeObserver(ctx context.Context) (types.VolumeObserver, bool) {
+	v, ok := ctx.Value(VolumeObserverKey).(types.VolumeObserver)
+	return v, ok
+}
+
+// WithDryRun returns a new context that requests the client's storage driver
+// validate mutating operations and return their would-be results without
+// executing them.
+func WithDryRun(parent context.Context) types.Context {
+	return newContext(parent, DryRunKey, true, nil, nil)
+}
+
+// DryRun returns a flag indicating whether or not the context requests a dry
+// run of mutating operations.
+func DryRun(ctx context.Context) bool {
+	v, ok := ctx.Value(DryRunKey).(bool)
+	return ok && v
+}
+
+// WithDefaultTimeout returns a copy of parent that is canceled when the
+// timeout elapses. If parent already has a deadline, or the timeout is less
+// than or equal to zero, parent is returned along with a cancel function
+// that does nothing.
+func WithDefaultTimeout(
+	parent types.Context,
+	timeout time.Duration) (types.Context, context.CancelFunc) {
+
+	if _, ok := parent.Deadline(); ok || timeout <= 0 {
+		return parent, func() {}
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+
+	// the new context would otherwise have the default logger instead of
+	// the parent's logger
+	return newContext(ctx, LoggerKey, parent.Value(LoggerKey), nil, nil), cancel
+}
+
+// WithoutCancel returns a copy of parent that has parent's values but is
+// never canceled and has no deadline. It is used to clean up after an
+// operation whose context may already be canceled or timed out, usually
+// along with WithDefaultTimeout so the cleanup cannot block forever.
+func WithoutCancel(parent types.Context) types.Context {
+	return newContext(
+		detachedContext{parent}, LoggerKey, parent.Value(LoggerKey), nil, nil)
+}
+
+type detachedContext struct {
+	context.Context
+}
+
+func (ctx detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (ctx detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (ctx detachedContext) Err() error {
+	return nil
+}
+
+// WithLogLevel returns a new context that logs at the provided level. The
+// new context's logger writes to the same output as the parent's logger, and
+// the parent's level is unchanged.
+func WithLogLevel(parent context.Context, lvl log.Level) types.Context {
+	pctx := newContext(parent, nil, nil, nil, nil)
+	if lvl == pctx.logger.Level {
+		return pctx
+	}
+	return newContext(pctx, LoggerKey, &log.Logger{
+		Formatter: pctx.logger.Formatter,
+		Out:       pctx.logger.Out,
+		Hooks:     pctx.logger.Hooks,
+		Level:     lvl,
+	}, nil, nil)
+}
+
 // RequireTX ensures a context has a transaction, and if it doesn't creates a
 // new one.
 func RequireTX(ctx context.Context) types.Context {
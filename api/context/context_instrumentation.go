@@ -0,0 +1,128 @@
+package context
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// WithTracer returns a new context with the Tracer used to record spans
+// around driver operations.
+func WithTracer(parent context.Context, tracer types.Tracer) types.Context {
+	return newContext(parent, TracerKey, tracer, nil, nil)
+}
+
+// Tracer returns the context's Tracer. A no-op Tracer is returned if the
+// context does not have one.
+func Tracer(ctx context.Context) types.Tracer {
+	if t, ok := ctx.Value(TracerKey).(types.Tracer); ok {
+		return t
+	}
+	return noopTracer{}
+}
+
+// WithMetrics returns a new context with the Metrics used to record the
+// counts and latencies of driver operations.
+func WithMetrics(parent context.Context, metrics types.Metrics) types.Context {
+	return newContext(parent, MetricsKey, metrics, nil, nil)
+}
+
+// Metrics returns the context's Metrics. A no-op Metrics is returned if the
+// context does not have one.
+func Metrics(ctx context.Context) types.Metrics {
+	if m, ok := ctx.Value(MetricsKey).(types.Metrics); ok {
+		return m
+	}
+	return noopMetrics{}
+}
+
+// StartSpan starts a span for the named operation with the context's Tracer
+// and logs the entry into the operation at the debug level. The returned
+// function ends the span with the operation's error, records the
+// operation's status and duration with the context's Metrics, and logs the
+// exit from the operation along with its duration. The metrics are labeled
+// with the operation as the "method" as well as the "driver" and "service"
+// if they are known. The log entries include the context's fields, such as
+// the request ID, so that the operations performed on behalf of a single
+// request may be correlated:
+//
+//	ctx, end := context.StartSpan(ctx, "VolumeAttach", log.Fields{
+//		"volumeID": volumeID,
+//	})
+//	defer func() { end(err) }()
+func StartSpan(
+	ctx types.Context,
+	operation string,
+	fields log.Fields) (types.Context, func(err error)) {
+
+	var (
+		start  = time.Now()
+		tracer = Tracer(ctx)
+		sctx   = tracer.StartSpan(ctx, operation, fields)
+	)
+
+	sctx.WithFields(fields).WithField("method", operation).Debug("enter")
+
+	return sctx, func(err error) {
+		dur := time.Since(start)
+		tracer.EndSpan(sctx, err)
+		recordMetrics(sctx, operation, dur, err)
+		entry := sctx.WithFields(fields).WithFields(log.Fields{
+			"method":   operation,
+			"duration": dur,
+		})
+		if err != nil {
+			entry = entry.WithError(err)
+		}
+		entry.Debug("exit")
+	}
+}
+
+func recordMetrics(
+	ctx types.Context, operation string, dur time.Duration, err error) {
+
+	metrics := Metrics(ctx)
+	if _, ok := metrics.(noopMetrics); ok {
+		return
+	}
+
+	labels := map[string]string{"method": operation}
+	if d, ok := Driver(ctx); ok {
+		labels["driver"] = d.Name()
+	}
+	if s, ok := ServiceName(ctx); ok {
+		labels["service"] = s
+	}
+	metrics.ObserveLatency(types.MetricOperationLatency, dur, labels)
+
+	counterLabels := map[string]string{"status": "success"}
+	if err != nil {
+		counterLabels["status"] = "failure"
+	}
+	for k, v := range labels {
+		counterLabels[k] = v
+	}
+	metrics.IncrCounter(types.MetricOperations, counterLabels)
+}
+
+type noopTracer struct{}
+
+func (t noopTracer) StartSpan(
+	ctx types.Context,
+	operation string,
+	fields map[string]interface{}) types.Context {
+	return ctx
+}
+
+func (t noopTracer) EndSpan(ctx types.Context, err error) {}
+
+type noopMetrics struct{}
+
+func (m noopMetrics) IncrCounter(name string, labels map[string]string) {}
+
+func (m noopMetrics) ObserveLatency(
+	name string, d time.Duration, labels map[string]string) {
+}
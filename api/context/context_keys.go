@@ -43,6 +43,18 @@ const (
 	// EncodedAuthTokenKey is the key for an encoded authentication token.
 	EncodedAuthTokenKey
 
+	// TracerKey is the key for the types.Tracer used to record spans around
+	// driver operations.
+	TracerKey
+
+	// MetricsKey is the key for the types.Metrics used to record the counts
+	// and latencies of driver operations.
+	MetricsKey
+
+	// VolumeObserverKey is the key for the types.VolumeObserver notified of
+	// the changes to volumes.
+	VolumeObserverKey
+
 	// keyLoggable is the minimum value from which the succeeding keys should
 	// be checked when logging.
 	keyLoggable
@@ -89,6 +101,14 @@ const (
 	// TLSKey is a context key.
 	TLSKey
 
+	// RequestIDKey is the key for the ID used to correlate the log entries
+	// of a request that spans multiple operations.
+	RequestIDKey
+
+	// DryRunKey is the key for the flag that requests mutating operations
+	// be validated but not executed.
+	DryRunKey
+
 	// keyEOF should always be the final key
 	keyEOF
 )
@@ -116,6 +136,8 @@ var (
 		UserKey:           "user",
 		HostKey:           "host",
 		TLSKey:            "tls",
+		RequestIDKey:      "requestID",
+		DryRunKey:         "dryRun",
 	}
 )
 
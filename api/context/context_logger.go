@@ -125,67 +125,67 @@ func (ctx *lsc) Panicf(format string, args ...interface{}) {
 }
 
 func (ctx *lsc) Debug(args ...interface{}) {
-	ctx.logger.WithFields(ctx.ctxFields()).Debug(args...)
+	ctx.newEntry().Debug(args...)
 }
 
 func (ctx *lsc) Info(args ...interface{}) {
-	ctx.logger.WithFields(ctx.ctxFields()).Info(args...)
+	ctx.newEntry().Info(args...)
 }
 
 func (ctx *lsc) Print(args ...interface{}) {
-	ctx.logger.WithFields(ctx.ctxFields()).Print(args...)
+	ctx.newEntry().Print(args...)
 }
 
 func (ctx *lsc) Warn(args ...interface{}) {
-	ctx.logger.WithFields(ctx.ctxFields()).Warn(args...)
+	ctx.newEntry().Warn(args...)
 }
 
 func (ctx *lsc) Warning(args ...interface{}) {
-	ctx.logger.WithFields(ctx.ctxFields()).Warning(args...)
+	ctx.newEntry().Warning(args...)
 }
 
 func (ctx *lsc) Error(args ...interface{}) {
-	ctx.logger.WithFields(ctx.ctxFields()).Error(args...)
+	ctx.newEntry().Error(args...)
 }
 
 func (ctx *lsc) Fatal(args ...interface{}) {
-	ctx.logger.WithFields(ctx.ctxFields()).Fatal(args...)
+	ctx.newEntry().Fatal(args...)
 }
 
 func (ctx *lsc) Panic(args ...interface{}) {
-	ctx.logger.WithFields(ctx.ctxFields()).Panic(args...)
+	ctx.newEntry().Panic(args...)
 }
 
 func (ctx *lsc) Debugln(args ...interface{}) {
-	ctx.logger.Debug(args...)
+	ctx.logger.Debug(redactArgs(args)...)
 }
 
 func (ctx *lsc) Infoln(args ...interface{}) {
-	ctx.logger.Info(args...)
+	ctx.logger.Info(redactArgs(args)...)
 }
 
 func (ctx *lsc) Println(args ...interface{}) {
-	ctx.logger.Print(args...)
+	ctx.logger.Print(redactArgs(args)...)
 }
 
 func (ctx *lsc) Warnln(args ...interface{}) {
-	ctx.logger.Warn(args...)
+	ctx.logger.Warn(redactArgs(args)...)
 }
 
 func (ctx *lsc) Warningln(args ...interface{}) {
-	ctx.logger.Warning(args...)
+	ctx.logger.Warning(redactArgs(args)...)
 }
 
 func (ctx *lsc) Errorln(args ...interface{}) {
-	ctx.logger.Error(args...)
+	ctx.logger.Error(redactArgs(args)...)
 }
 
 func (ctx *lsc) Fatalln(args ...interface{}) {
-	ctx.logger.Fatal(args...)
+	ctx.logger.Fatal(redactArgs(args)...)
 }
 
 func (ctx *lsc) Panicln(args ...interface{}) {
-	ctx.logger.Panic(args...)
+	ctx.logger.Panic(redactArgs(args)...)
 }
 
 type entry struct {
@@ -193,10 +193,65 @@ type entry struct {
 	ctx *lsc
 }
 
+func (ctx *lsc) newEntry() *entry {
+	return &entry{Entry: log.NewEntry(ctx.logger), ctx: ctx}
+}
+
+// prepare adds the context's fields to the entry and redacts any registered
+// secrets from the entry's fields.
+func (e *entry) prepare() {
+	e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
+	for k, v := range e.Data {
+		if rv, ok := redactValue(v); ok {
+			e.Data[k] = rv
+		}
+	}
+}
+
+// redactArgs returns the log arguments with any registered secrets redacted.
+func redactArgs(args []interface{}) []interface{} {
+	var redacted []interface{}
+	for i, v := range args {
+		rv, ok := redactValue(v)
+		if !ok {
+			continue
+		}
+		if redacted == nil {
+			redacted = make([]interface{}, len(args))
+			copy(redacted, args)
+		}
+		redacted[i] = rv
+	}
+	if redacted == nil {
+		return args
+	}
+	return redacted
+}
+
+// redactValue returns the string form of the value with any registered
+// secrets redacted. The flag is false if the value contains no secrets.
+func redactValue(v interface{}) (interface{}, bool) {
+	var s string
+	switch tv := v.(type) {
+	case string:
+		s = tv
+	case error:
+		s = tv.Error()
+	case fmt.Stringer:
+		s = tv.String()
+	default:
+		return nil, false
+	}
+	if r := types.RedactSecrets(s); r != s {
+		return r, true
+	}
+	return nil, false
+}
+
 func (e *entry) Debug(args ...interface{}) {
 	if e.Logger.Level >= log.DebugLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Debug(args...)
+		e.prepare()
+		e.Entry.Debug(redactArgs(args)...)
 	}
 }
 
@@ -206,15 +261,15 @@ func (e *entry) Print(args ...interface{}) {
 
 func (e *entry) Info(args ...interface{}) {
 	if e.Logger.Level >= log.InfoLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Info(args...)
+		e.prepare()
+		e.Entry.Info(redactArgs(args)...)
 	}
 }
 
 func (e *entry) Warn(args ...interface{}) {
 	if e.Logger.Level >= log.WarnLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Warn(args...)
+		e.prepare()
+		e.Entry.Warn(redactArgs(args)...)
 	}
 }
 
@@ -224,23 +279,23 @@ func (e *entry) Warning(args ...interface{}) {
 
 func (e *entry) Error(args ...interface{}) {
 	if e.Logger.Level >= log.ErrorLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Error(args...)
+		e.prepare()
+		e.Entry.Error(redactArgs(args)...)
 	}
 }
 
 func (e *entry) Fatal(args ...interface{}) {
 	if e.Logger.Level >= log.FatalLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Fatal(args...)
+		e.prepare()
+		e.Entry.Fatal(redactArgs(args)...)
 	}
 	os.Exit(1)
 }
 
 func (e *entry) Panic(args ...interface{}) {
 	if e.Logger.Level >= log.PanicLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Panic(args...)
+		e.prepare()
+		e.Entry.Panic(redactArgs(args)...)
 	}
 	panic(fmt.Sprint(args...))
 }
@@ -249,15 +304,15 @@ func (e *entry) Panic(args ...interface{}) {
 
 func (e *entry) Debugf(format string, args ...interface{}) {
 	if e.Logger.Level >= log.DebugLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Debugf(format, args...)
+		e.prepare()
+		e.Entry.Debugf(format, redactArgs(args)...)
 	}
 }
 
 func (e *entry) Infof(format string, args ...interface{}) {
 	if e.Logger.Level >= log.InfoLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Infof(format, args...)
+		e.prepare()
+		e.Entry.Infof(format, redactArgs(args)...)
 	}
 }
 
@@ -267,8 +322,8 @@ func (e *entry) Printf(format string, args ...interface{}) {
 
 func (e *entry) Warnf(format string, args ...interface{}) {
 	if e.Logger.Level >= log.WarnLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Warnf(format, args...)
+		e.prepare()
+		e.Entry.Warnf(format, redactArgs(args)...)
 	}
 }
 
@@ -278,23 +333,23 @@ func (e *entry) Warningf(format string, args ...interface{}) {
 
 func (e *entry) Errorf(format string, args ...interface{}) {
 	if e.Logger.Level >= log.ErrorLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Errorf(format, args...)
+		e.prepare()
+		e.Entry.Errorf(format, redactArgs(args)...)
 	}
 }
 
 func (e *entry) Fatalf(format string, args ...interface{}) {
 	if e.Logger.Level >= log.FatalLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Fatalf(format, args...)
+		e.prepare()
+		e.Entry.Fatalf(format, redactArgs(args)...)
 	}
 	os.Exit(1)
 }
 
 func (e *entry) Panicf(format string, args ...interface{}) {
 	if e.Logger.Level >= log.PanicLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Panicf(format, args...)
+		e.prepare()
+		e.Entry.Panicf(format, redactArgs(args)...)
 	}
 }
 
@@ -302,15 +357,15 @@ func (e *entry) Panicf(format string, args ...interface{}) {
 
 func (e *entry) Debugln(args ...interface{}) {
 	if e.Logger.Level >= log.DebugLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Debugln(args...)
+		e.prepare()
+		e.Entry.Debugln(redactArgs(args)...)
 	}
 }
 
 func (e *entry) Infoln(args ...interface{}) {
 	if e.Logger.Level >= log.InfoLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Infoln(args...)
+		e.prepare()
+		e.Entry.Infoln(redactArgs(args)...)
 	}
 }
 
@@ -320,8 +375,8 @@ func (e *entry) Println(args ...interface{}) {
 
 func (e *entry) Warnln(args ...interface{}) {
 	if e.Logger.Level >= log.WarnLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Warnln(args...)
+		e.prepare()
+		e.Entry.Warnln(redactArgs(args)...)
 	}
 }
 
@@ -331,23 +386,23 @@ func (e *entry) Warningln(args ...interface{}) {
 
 func (e *entry) Errorln(args ...interface{}) {
 	if e.Logger.Level >= log.ErrorLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Errorln(args...)
+		e.prepare()
+		e.Entry.Errorln(redactArgs(args)...)
 	}
 }
 
 func (e *entry) Fatalln(args ...interface{}) {
 	if e.Logger.Level >= log.FatalLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Fatalln(args...)
+		e.prepare()
+		e.Entry.Fatalln(redactArgs(args)...)
 	}
 	os.Exit(1)
 }
 
 func (e *entry) Panicln(args ...interface{}) {
 	if e.Logger.Level >= log.PanicLevel {
-		e.ctx.addkeyFieldOffsetFieldsToEntry(e.Entry)
-		e.Entry.Panicln(args...)
+		e.prepare()
+		e.Entry.Panicln(redactArgs(args)...)
 	}
 }
 
@@ -1,11 +1,16 @@
 package context
 
 import (
+	"bytes"
 	"os"
 	"testing"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/akutz/goof"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/types"
 )
 
 func TestMain(m *testing.M) {
@@ -110,3 +115,217 @@ func TestContextIDLogCustomLoggerKey(t *testing.T) {
 	ctx = ctx.WithValue(testLogKeyHello, "world")
 	ctx.Info("testing custom log keys")
 }
+
+func TestRequestID(t *testing.T) {
+	ctx := Background()
+	_, ok := RequestID(ctx)
+	assert.False(t, ok)
+
+	ctx = WithRequestID(ctx, "req-123")
+	rid, ok := RequestID(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "req-123", rid)
+	assert.Equal(t, "req-123", ctx.(*lsc).ctxFields()["requestID"])
+}
+
+func TestWithInstanceID(t *testing.T) {
+	ctx := Background()
+	_, ok := InstanceID(ctx)
+	assert.False(t, ok)
+
+	iid := &types.InstanceID{ID: "i-1234", Driver: "vfs"}
+	ctx = WithInstanceID(ctx, iid)
+	v, ok := InstanceID(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, iid, v)
+	assert.Equal(t, iid, MustInstanceID(ctx))
+}
+
+func TestDryRun(t *testing.T) {
+	ctx := Background()
+	assert.False(t, DryRun(ctx))
+
+	ctx = WithDryRun(ctx)
+	assert.True(t, DryRun(ctx))
+	assert.Equal(t, "true", ctx.(*lsc).ctxFields()["dryRun"])
+}
+
+func TestWithLogger(t *testing.T) {
+	logger := log.New()
+	ctx := WithValue(Background(), LoggerKey, logger)
+	assert.Equal(t, logger, ctx.Value(LoggerKey))
+
+	ctx = WithRequestID(ctx, "req-123")
+	assert.Equal(t, logger, ctx.Value(LoggerKey))
+}
+
+func TestWithDefaultTimeout(t *testing.T) {
+	logger := log.New()
+	parent := WithValue(Background(), LoggerKey, logger)
+
+	ctx, cancel := WithDefaultTimeout(parent, time.Minute)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	assert.Equal(t, logger, ctx.Value(LoggerKey))
+
+	// an existing deadline is respected
+	ctx2, cancel2 := WithDefaultTimeout(ctx, time.Hour)
+	defer cancel2()
+	deadline2, _ := ctx2.Deadline()
+	assert.Equal(t, deadline, deadline2)
+
+	ctx, cancel = WithDefaultTimeout(parent, 0)
+	defer cancel()
+	_, ok = ctx.Deadline()
+	assert.False(t, ok)
+}
+
+func TestWithoutCancel(t *testing.T) {
+	logger := log.New()
+	parent := WithValue(Background(), LoggerKey, logger)
+	parent = WithValue(parent, ServerKey, serverName)
+	parent, cancel := WithDefaultTimeout(parent, time.Minute)
+	cancel()
+	assert.Error(t, parent.Err())
+
+	ctx := WithoutCancel(parent)
+	assert.NoError(t, ctx.Err())
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	v, _ := Server(ctx)
+	assert.Equal(t, serverName, v)
+	assert.Equal(t, logger, ctx.Value(LoggerKey))
+
+	ctx, cancel = WithDefaultTimeout(ctx, time.Minute)
+	defer cancel()
+	_, ok = ctx.Deadline()
+	assert.True(t, ok)
+}
+
+func TestWithLogLevel(t *testing.T) {
+	logger := log.New()
+	logger.Level = log.WarnLevel
+	parent := WithValue(Background(), LoggerKey, logger)
+
+	ctx := WithLogLevel(parent, log.DebugLevel)
+	lvl, _ := GetLogLevel(ctx)
+	assert.Equal(t, log.DebugLevel, lvl)
+	assert.Equal(t, logger.Out, ctx.(*lsc).logger.Out)
+
+	lvl, _ = GetLogLevel(parent)
+	assert.Equal(t, log.WarnLevel, lvl)
+	assert.Equal(t, log.WarnLevel, logger.Level)
+}
+
+func TestLogRedactsSecrets(t *testing.T) {
+	types.RegisterSecret("wJalrXUtnFEMI/K7MDENG")
+
+	buf := &bytes.Buffer{}
+	logger := log.New()
+	logger.Out = buf
+	logger.Level = log.DebugLevel
+	ctx := WithValue(Background(), LoggerKey, logger)
+
+	err := goof.New("auth failed for secret wJalrXUtnFEMI/K7MDENG")
+	ctx.WithError(err).Error("error creating volume")
+	ctx.WithField("secretKey", "wJalrXUtnFEMI/K7MDENG").Debug("configured")
+	ctx.Debugf("using key %s", "wJalrXUtnFEMI/K7MDENG")
+
+	assert.NotContains(t, buf.String(), "wJalrXUtnFEMI/K7MDENG")
+	assert.Contains(t, buf.String(), types.Redacted)
+	assert.Contains(t, buf.String(), "error creating volume")
+}
+
+type testTracer struct {
+	started []string
+	fields  map[string]interface{}
+	ended   []error
+}
+
+func (t *testTracer) StartSpan(
+	ctx types.Context,
+	operation string,
+	fields map[string]interface{}) types.Context {
+	t.started = append(t.started, operation)
+	t.fields = fields
+	return ctx.WithValue(testLogKeyHello, operation)
+}
+
+func (t *testTracer) EndSpan(ctx types.Context, err error) {
+	t.ended = append(t.ended, err)
+}
+
+func TestStartSpan(t *testing.T) {
+	ctx, end := StartSpan(Background(), "VolumeInspect", nil)
+	assert.NotNil(t, ctx)
+	end(nil)
+
+	tracer := &testTracer{}
+	ctx = WithTracer(Background(), tracer)
+	sctx, end := StartSpan(ctx, "VolumeAttach", log.Fields{"volumeID": "vol-000"})
+	assert.Equal(t, "VolumeAttach", sctx.Value(testLogKeyHello))
+	assert.Equal(t, []string{"VolumeAttach"}, tracer.started)
+	assert.Equal(t, "vol-000", tracer.fields["volumeID"])
+	assert.Empty(t, tracer.ended)
+
+	err := goof.New("attach failed")
+	end(err)
+	assert.Equal(t, []error{err}, tracer.ended)
+}
+
+type testMetrics struct {
+	counters  map[string][]map[string]string
+	latencies map[string][]map[string]string
+}
+
+func (m *testMetrics) IncrCounter(name string, labels map[string]string) {
+	m.counters[name] = append(m.counters[name], labels)
+}
+
+func (m *testMetrics) ObserveLatency(
+	name string, d time.Duration, labels map[string]string) {
+	m.latencies[name] = append(m.latencies[name], labels)
+}
+
+func TestStartSpanMetrics(t *testing.T) {
+	metrics := &testMetrics{
+		counters:  map[string][]map[string]string{},
+		latencies: map[string][]map[string]string{},
+	}
+	ctx := WithMetrics(Background(), metrics)
+	ctx = ctx.WithValue(ServiceKey, "ebs")
+
+	_, end := StartSpan(ctx, "VolumeAttach", nil)
+	end(nil)
+	_, end = StartSpan(ctx, "VolumeAttach", nil)
+	end(goof.New("attach failed"))
+
+	assert.Equal(t, []map[string]string{
+		{"method": "VolumeAttach", "service": "ebs", "status": "success"},
+		{"method": "VolumeAttach", "service": "ebs", "status": "failure"},
+	}, metrics.counters[types.MetricOperations])
+	assert.Len(t, metrics.latencies[types.MetricOperationLatency], 2)
+	assert.Equal(t,
+		map[string]string{"method": "VolumeAttach", "service": "ebs"},
+		metrics.latencies[types.MetricOperationLatency][0])
+}
+
+type testVolumeObserver struct{}
+
+func (o *testVolumeObserver) OnCreate(ctx types.Context, v *types.Volume) {}
+func (o *testVolumeObserver) OnAttach(ctx types.Context, v *types.Volume) {}
+func (o *testVolumeObserver) OnDetach(ctx types.Context, v *types.Volume) {}
+func (o *testVolumeObserver) OnRemove(ctx types.Context, id string)       {}
+
+func TestVolumeObserver(t *testing.T) {
+	_, ok := VolumeObserver(Background())
+	assert.False(t, ok)
+
+	o := &testVolumeObserver{}
+	ctx := WithVolumeObserver(Background(), o)
+	v, ok := VolumeObserver(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, o, v)
+}
@@ -3,6 +3,7 @@
 package registry
 
 import (
+	"sort"
 	"strings"
 	"sync"
 
@@ -30,6 +31,9 @@ var (
 
 	routers    = []types.Router{}
 	routersRWL = &sync.RWMutex{}
+
+	lockerCtors    = map[string]types.NewLocker{}
+	lockerCtorsRWL = &sync.RWMutex{}
 )
 
 type cregW struct {
@@ -80,6 +84,13 @@ func RegisterIntegrationDriver(name string, ctor types.NewIntegrationDriver) {
 	intDriverCtors[strings.ToLower(name)] = ctor
 }
 
+// RegisterLocker registers a Locker.
+func RegisterLocker(name string, ctor types.NewLocker) {
+	lockerCtorsRWL.Lock()
+	defer lockerCtorsRWL.Unlock()
+	lockerCtors[strings.ToLower(name)] = ctor
+}
+
 // NewStorageExecutor returns a new instance of the executor specified by the
 // executor name.
 func NewStorageExecutor(name string) (types.StorageExecutor, error) {
@@ -114,12 +125,45 @@ func NewStorageDriver(name string) (types.StorageDriver, error) {
 	}()
 
 	if !ok {
-		return nil, goof.WithField("driver", name, "invalid driver name")
+		return nil, &types.ErrUnknownDriver{
+			Goof: goof.WithField("driver", name, "invalid driver name"),
+		}
 	}
 
 	return ctor(), nil
 }
 
+// GetStorageDriver returns a new, initialized instance of the driver
+// specified by the driver name. An ErrUnknownDriver error is returned if no
+// driver is registered with the specified name.
+func GetStorageDriver(
+	ctx types.Context,
+	name string,
+	config gofig.Config) (types.StorageDriver, error) {
+
+	d, err := NewStorageDriver(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Init(ctx, config); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// StorageDriverNames returns the sorted names of the registered storage
+// drivers.
+func StorageDriverNames() []string {
+	storDriverCtorsRWL.RLock()
+	defer storDriverCtorsRWL.RUnlock()
+	names := make([]string, 0, len(storDriverCtors))
+	for name := range storDriverCtors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // NewOSDriver returns a new instance of the driver specified by the
 // driver name.
 func NewOSDriver(name string) (types.OSDriver, error) {
@@ -160,6 +204,26 @@ func NewIntegrationDriver(name string) (types.IntegrationDriver, error) {
 	return NewIntegrationDriverManager(ctor()), nil
 }
 
+// NewLocker returns a new instance of the locker specified by the locker
+// name.
+func NewLocker(name string) (types.Locker, error) {
+
+	var ok bool
+	var ctor types.NewLocker
+
+	func() {
+		lockerCtorsRWL.RLock()
+		defer lockerCtorsRWL.RUnlock()
+		ctor, ok = lockerCtors[strings.ToLower(name)]
+	}()
+
+	if !ok {
+		return nil, goof.WithField("locker", name, "invalid locker name")
+	}
+
+	return ctor(), nil
+}
+
 // ConfigRegs returns a channel on which all registered configuration
 // registrations are returned.
 func ConfigRegs(ctx types.Context) <-chan gofig.ConfigRegistration {
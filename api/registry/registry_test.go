@@ -0,0 +1,32 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestNewStorageDriverUnknown(t *testing.T) {
+	d, err := NewStorageDriver("notARealDriver")
+	assert.Nil(t, d)
+	assert.IsType(t, &types.ErrUnknownDriver{}, err)
+}
+
+func TestStorageDriverNames(t *testing.T) {
+	RegisterStorageDriver("ZZTestDriver", func() types.StorageDriver {
+		return nil
+	})
+	names := StorageDriverNames()
+	assert.Contains(t, names, "zztestdriver")
+	for i := 1; i < len(names); i++ {
+		assert.True(t, names[i-1] < names[i])
+	}
+}
+
+func TestNewLocker(t *testing.T) {
+	l, err := NewLocker("notARealLocker")
+	assert.Nil(t, l)
+	assert.Error(t, err)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/akutz/goof"
 
 	"github.com/codedellemc/libstorage/api/context"
-	"github.com/codedellemc/libstorage/api/server/httputils"
 	"github.com/codedellemc/libstorage/api/types"
 )
 
@@ -56,10 +55,30 @@ func (h *errorHandler) Handle(
 		}
 	}
 
-	httputils.WriteJSON(w, httpErr.Status(), httpErr)
+	writeRedactedJSON(ctx, w, httpErr.Status(), httpErr)
 	return nil
 }
 
+// writeRedactedJSON writes the value to the response as JSON with any
+// registered secrets redacted, since an error from a storage platform may
+// include the credentials that were used to access it.
+func writeRedactedJSON(
+	ctx types.Context,
+	w http.ResponseWriter,
+	code int,
+	v interface{}) {
+
+	buf, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		ctx.WithError(err).Error("error marshalling api call err to json")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write([]byte(types.RedactSecrets(string(buf))))
+}
+
 func getStatus(err error) int {
 	if err == types.ErrMissingStorageService {
 		return http.StatusInternalServerError
@@ -68,11 +87,29 @@ func getStatus(err error) int {
 	case *types.ErrBadAdminToken,
 		*types.ErrSecTokInvalid:
 		return http.StatusUnauthorized
-	case *types.ErrNotFound:
+	case *types.ErrNotFound,
+		*types.ErrVolumeNotFound,
+		*types.ErrSnapshotNotFound:
 		return http.StatusNotFound
+	case *types.ErrAlreadyExists,
+		*types.ErrConflict,
+		*types.ErrLockTimeout,
+		*types.ErrLimitExceeded:
+		return http.StatusConflict
 	case *types.ErrMissingInstanceID,
-		*types.ErrMissingLocalDevices:
+		*types.ErrMissingLocalDevices,
+		*types.ErrVolumeAttached,
+		*types.ErrVolumeNotAttached,
+		*types.ErrVolumeAlreadyAttached,
+		*types.ErrInvalidVolumeType,
+		*types.ErrInvalidArtifact:
 		return http.StatusBadRequest
+	case *types.ErrUnsupported:
+		return http.StatusNotImplemented
+	case *types.ErrClosed:
+		return http.StatusServiceUnavailable
+	case *types.ErrRateLimited:
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
@@ -7,7 +7,6 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
-	"net/http/httputil"
 	"net/url"
 	"strconv"
 	"strings"
@@ -17,6 +16,7 @@ import (
 	"github.com/akutz/gotil"
 
 	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
 )
 
 const (
@@ -67,7 +67,7 @@ func (h *loggingHandler) Handle(
 	var err error
 	var reqDump []byte
 	if h.logRequests {
-		if reqDump, err = httputil.DumpRequest(req, true); err != nil {
+		if reqDump, err = utils.DumpRequest(req, true); err != nil {
 			return err
 		}
 	}
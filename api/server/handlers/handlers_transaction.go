@@ -47,5 +47,9 @@ func (h *transactionHandler) Handle(
 		ctx = ctx.WithValue(context.TransactionKey, tx)
 	}
 
+	if rid := req.Header.Get(types.RequestIDHeader); rid != "" {
+		ctx = context.WithRequestID(ctx, rid)
+	}
+
 	return h.handler(ctx, w, req, store)
 }
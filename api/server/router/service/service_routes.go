@@ -60,10 +60,13 @@ func toServiceInfo(
 		}
 
 		var err error
-		instance, err = d.InstanceInspect(ctx, store)
+		instance, err = service.InstanceInspect(ctx, store)
 		if err != nil {
 			return nil, err
 		}
+		if err = instance.Validate(); err != nil {
+			return nil, err
+		}
 	}
 
 	st, err := d.Type(ctx)
@@ -74,14 +77,19 @@ func toServiceInfo(
 	if err != nil {
 		return nil, err
 	}
+	caps, err := utils.DriverCapabilities(ctx, d)
+	if err != nil {
+		return nil, err
+	}
 
 	return &types.ServiceInfo{
 		Name:     service.Name(),
 		Instance: instance,
 		Driver: &types.DriverInfo{
-			Name:       d.Name(),
-			Type:       st,
-			NextDevice: nd,
+			Name:         d.Name(),
+			Type:         st,
+			NextDevice:   nd,
+			Capabilities: caps,
 		},
 	}, nil
 }
@@ -37,8 +37,12 @@ func (r *router) snapshots(
 				return nil, err
 			}
 
-			objs, err := svc.Driver().Snapshots(ctx, store)
-			if err != nil {
+			var objs []*types.Snapshot
+			if err := svc.Retry(
+				ctx, utils.RetryOpSnapshots, func() (err error) {
+					objs, err = svc.Driver().Snapshots(ctx, store)
+					return
+				}); err != nil {
 				return nil, err
 			}
 
@@ -97,8 +101,12 @@ func (r *router) snapshotsForService(
 
 		var reply types.SnapshotMap = map[string]*types.Snapshot{}
 
-		objs, err := svc.Driver().Snapshots(ctx, store)
-		if err != nil {
+		var objs []*types.Snapshot
+		if err := svc.Retry(
+			ctx, utils.RetryOpSnapshots, func() (err error) {
+				objs, err = svc.Driver().Snapshots(ctx, store)
+				return
+			}); err != nil {
 			return nil, err
 		}
 
@@ -129,10 +137,18 @@ func (r *router) snapshotInspect(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
-		return svc.Driver().SnapshotInspect(
-			ctx,
-			store.GetString("snapshotID"),
-			store)
+		var snap *types.Snapshot
+		if err := svc.Retry(
+			ctx, utils.RetryOpSnapshotInspect, func() (err error) {
+				snap, err = svc.Driver().SnapshotInspect(
+					ctx,
+					store.GetString("snapshotID"),
+					store)
+				return
+			}); err != nil {
+			return nil, err
+		}
+		return snap, nil
 	}
 
 	return httputils.WriteTask(
@@ -183,17 +199,28 @@ func (r *router) volumeCreate(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
-		v, err := svc.Driver().VolumeCreateFromSnapshot(
-			ctx,
-			store.GetString("snapshotID"),
-			store.GetString("name"),
-			&types.VolumeCreateOpts{
+		var (
+			v          *types.Volume
+			err        error
+			snapshotID = store.GetString("snapshotID")
+			name       = store.GetString("name")
+			createOpts = &types.VolumeCreateOpts{
 				AvailabilityZone: store.GetStringPtr("availabilityZone"),
 				IOPS:             store.GetInt64Ptr("iops"),
 				Size:             store.GetInt64Ptr("size"),
 				Type:             store.GetStringPtr("type"),
 				Opts:             store,
-			})
+			}
+		)
+
+		if store.IsSet("size") {
+			v, err = utils.VolumeCreateFromSnapshot(
+				ctx, svc.Driver(), snapshotID, name,
+				store.GetInt64("size"), createOpts)
+		} else {
+			v, err = svc.Driver().VolumeCreateFromSnapshot(
+				ctx, snapshotID, name, createOpts)
+		}
 
 		if err != nil {
 			return nil, err
@@ -233,6 +260,17 @@ func (r *router) snapshotCopy(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
+		if store.IsSet("baseSnapshotID") {
+			return utils.SnapshotCopyIncremental(
+				ctx,
+				svc.Driver(),
+				store.GetString("snapshotID"),
+				store.GetString("snapshotName"),
+				store.GetString("destinationID"),
+				store.GetString("baseSnapshotID"),
+				store)
+		}
+
 		return svc.Driver().SnapshotCopy(
 			ctx,
 			store.GetString("snapshotID"),
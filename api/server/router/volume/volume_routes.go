@@ -230,8 +230,11 @@ func getFilteredVolumes(
 
 	ctx.WithField("attachments", opts.Attachments).Debug("querying volumes")
 
-	objs, err := storSvc.Driver().Volumes(ctx, opts)
-	if err != nil {
+	var objs []*types.Volume
+	if err := storSvc.Retry(ctx, utils.RetryOpVolumes, func() (err error) {
+		objs, err = storSvc.Driver().Volumes(ctx, opts)
+		return
+	}); err != nil {
 		return nil, err
 	}
 
@@ -309,7 +312,9 @@ func (r *router) volumeInspect(
 			)
 			volID := store.GetString("volumeID")
 
-			if sd, ok := svc.Driver().(types.StorageDriverVolInspectByName); ok {
+			sd, ok := types.UnwrapStorageDriver(
+				svc.Driver()).(types.StorageDriverVolInspectByName)
+			if ok {
 				ctx.Debug("driver is StorageDriverVolInspectByName")
 				vol, err = sd.VolumeInspectByName(
 					ctx, volID, opts)
@@ -363,10 +368,13 @@ func (r *router) volumeInspect(
 			ctx types.Context,
 			svc types.StorageService) (interface{}, error) {
 
-			v, err := svc.Driver().VolumeInspect(
-				ctx, store.GetString("volumeID"), opts)
-
-			if err != nil {
+			var v *types.Volume
+			if err := svc.Retry(
+				ctx, utils.RetryOpVolumeInspect, func() (err error) {
+					v, err = svc.Driver().VolumeInspect(
+						ctx, store.GetString("volumeID"), opts)
+					return
+				}); err != nil {
 				return nil, err
 			}
 
@@ -413,12 +421,17 @@ func (r *router) volumeCreate(
 		opts := &types.VolumeCreateOpts{
 			AvailabilityZone: store.GetStringPtr("availabilityZone"),
 			IOPS:             store.GetInt64Ptr("iops"),
+			Throughput:       store.GetInt64Ptr("throughput"),
 			Size:             store.GetInt64Ptr("size"),
 			Type:             store.GetStringPtr("type"),
 			Encrypted:        store.GetBoolPtr("encrypted"),
 			EncryptionKey:    store.GetStringPtr("encryptionKey"),
+			IdempotentByName: store.GetBool("idempotentByName"),
 			Opts:             store,
 		}
+		if tags, ok := store.Get("tags").(map[string]string); ok {
+			opts.Tags = tags
+		}
 		fields := map[string]interface{}{
 			"volumeName": store.GetString("name"),
 		}
@@ -429,11 +442,14 @@ func (r *router) volumeCreate(
 			fields["encrypted"] = &opts.Encrypted
 		}
 		if opts.EncryptionKey != nil {
-			fields["encryptionKey"] = &opts.EncryptionKey
+			fields["encryptionKey"] = utils.Redacted
 		}
 		if opts.IOPS != nil {
 			fields["iops"] = &opts.IOPS
 		}
+		if opts.Throughput != nil {
+			fields["throughput"] = &opts.Throughput
+		}
 		if opts.Size != nil {
 			fields["size"] = &opts.Size
 		}
@@ -442,7 +458,23 @@ func (r *router) volumeCreate(
 		}
 		ctx.WithFields(fields).Debug("creating volume")
 
-		v, err := svc.Driver().VolumeCreate(ctx, volumeName, opts)
+		if (opts.Encrypted != nil && *opts.Encrypted) || opts.Throughput != nil {
+			caps, err := utils.DriverCapabilities(ctx, svc.Driver())
+			if err != nil {
+				return nil, err
+			}
+			if opts.Encrypted != nil && *opts.Encrypted &&
+				!caps.SupportsEncryption {
+				return nil, utils.NewUnsupportedErr(
+					svc.Driver().Name(), "encryption")
+			}
+			if opts.Throughput != nil && !caps.SupportsThroughput {
+				return nil, utils.NewUnsupportedErr(
+					svc.Driver().Name(), "throughput")
+			}
+		}
+
+		v, err := utils.VolumeCreate(ctx, svc.Driver(), volumeName, opts)
 		if err != nil {
 			ctx.WithFields(fields).WithError(err).Error("error creating volume")
 			return nil, err
@@ -567,19 +599,46 @@ func (r *router) volumeAttach(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
+		unlock, err := svc.LockVolume(ctx, store.GetString("volumeID"))
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+
+		err = utils.CheckAttachLimit(
+			ctx, svc.Driver(), store.GetString("volumeID"))
+		if err != nil {
+			return nil, err
+		}
+
 		v, attTokn, err := svc.Driver().VolumeAttach(
 			ctx,
 			store.GetString("volumeID"),
 			&types.VolumeAttachOpts{
-				NextDevice: store.GetStringPtr("nextDeviceName"),
-				Force:      store.GetBool("force"),
-				Opts:       store,
+				NextDevice:  store.GetStringPtr("nextDeviceName"),
+				Force:       store.GetBool("force"),
+				MultiAttach: store.GetBool("multiAttach"),
+				ReadOnly:    store.GetBool("readOnly"),
+				Opts:        store,
 			})
 
 		if err != nil {
 			return nil, err
 		}
 
+		// record a read-only request on the instance's attachment if the
+		// driver did not so the volume is mounted read-only. The request
+		// may not include an instance ID, in which case the instance's
+		// attachment is unknown.
+		iid, ok := context.InstanceID(ctx)
+		if store.GetBool("readOnly") && ok && iid != nil {
+			for _, a := range v.Attachments {
+				if a.InstanceID != nil && a.InstanceID.ID == iid.ID {
+					a.ReadOnly = true
+				}
+			}
+		}
+
 		if OnVolume != nil {
 			ok, err := OnVolume(ctx, req, store, v)
 			if err != nil {
@@ -624,6 +683,12 @@ func (r *router) volumeDetach(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
+		unlock, err := svc.LockVolume(ctx, store.GetString("volumeID"))
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+
 		v, err := svc.Driver().VolumeDetach(
 			ctx,
 			store.GetString("volumeID"),
@@ -715,13 +780,7 @@ func (r *router) volumeDetachAll(
 			}()
 
 			for _, volume := range volumes {
-				v, err := driver.VolumeDetach(
-					ctx,
-					volume.ID,
-					&types.VolumeDetachOpts{
-						Force: store.GetBool("force"),
-						Opts:  store,
-					})
+				v, err := detachLocked(ctx, svc, volume.ID, store)
 				if err != nil {
 					return nil, err
 				}
@@ -803,13 +862,7 @@ func (r *router) volumeDetachAllForService(
 		}
 
 		for _, volume := range volumes {
-			v, err := driver.VolumeDetach(
-				ctx,
-				volume.ID,
-				&types.VolumeDetachOpts{
-					Force: store.GetBool("force"),
-					Opts:  store,
-				})
+			v, err := detachLocked(ctx, svc, volume.ID, store)
 			if err != nil {
 				return nil, utils.NewBatchProcessErr(reply, err)
 			}
@@ -851,6 +904,28 @@ func (r *router) volumeDetachAllForService(
 		http.StatusResetContent)
 }
 
+// detachLocked detaches the volume while holding the volume's lock.
+func detachLocked(
+	ctx types.Context,
+	svc types.StorageService,
+	volumeID string,
+	store types.Store) (*types.Volume, error) {
+
+	unlock, err := svc.LockVolume(ctx, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return svc.Driver().VolumeDetach(
+		ctx,
+		volumeID,
+		&types.VolumeDetachOpts{
+			Force: store.GetBool("force"),
+			Opts:  store,
+		})
+}
+
 func (r *router) volumeRemove(
 	ctx types.Context,
 	w http.ResponseWriter,
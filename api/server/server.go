@@ -118,6 +118,7 @@ func newServer(goCtx gocontext.Context, config gofig.Config) (*server, error) {
 		}
 	}
 	config = config.Scope(types.ConfigServer)
+	utils.RegisterConfigSecrets(config)
 
 	s := &server{
 		ctx:          ctx,
@@ -303,6 +304,10 @@ func (s *server) close() error {
 		srv.ctx.Debug("shutdown endpoint complete")
 	}
 
+	if err := services.Close(s.ctx); err != nil {
+		s.ctx.Error(err)
+	}
+
 	if s.stdOut != nil {
 		if err := s.stdOut.Close(); err != nil {
 			log.Error(err)
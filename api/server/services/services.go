@@ -10,6 +10,7 @@ import (
 
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
 )
 
 var (
@@ -63,6 +64,37 @@ func (sc *serviceContainer) Init(ctx types.Context, config gofig.Config) error {
 	return nil
 }
 
+// Close closes the storage drivers of the server's storage services. The
+// services may not be used once they are closed.
+func Close(ctx types.Context) error {
+
+	serverName, ok := context.Server(ctx)
+	if !ok {
+		panic("ctx is missing ServerName")
+	}
+
+	servicesByServerRWL.Lock()
+	defer servicesByServerRWL.Unlock()
+
+	sc, ok := servicesByServer[serverName]
+	if !ok {
+		return nil
+	}
+	delete(servicesByServer, serverName)
+
+	var err error
+	for _, svc := range sc.storageServices {
+		if cerr := utils.CloseDriver(svc.Driver()); cerr != nil {
+			ctx.WithField("service", svc.Name()).WithError(cerr).Error(
+				"error closing storage driver")
+			if err == nil {
+				err = cerr
+			}
+		}
+	}
+	return err
+}
+
 func getStorageServices(
 	ctx types.Context) map[string]types.StorageService {
 
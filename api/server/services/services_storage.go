@@ -2,7 +2,9 @@ package services
 
 import (
 	"fmt"
+	"time"
 
+	log "github.com/Sirupsen/logrus"
 	gofig "github.com/akutz/gofig/types"
 	"github.com/akutz/goof"
 
@@ -17,15 +19,67 @@ type storageService struct {
 	driver        types.StorageDriver
 	config        gofig.Config
 	authConfig    *types.AuthConfig
+	instanceCache *utils.InstanceCache
+	retryOpts     *utils.RetryOpts
 	taskExecQueue chan *task
+
+	// operationTimeout is applied to operations whose contexts do not have
+	// a deadline. A value of zero disables the timeout.
+	operationTimeout time.Duration
+
+	// logLevel is the level at which the service and its driver log if
+	// logLevelSet is true. Otherwise they log at the server's level.
+	logLevel    log.Level
+	logLevelSet bool
+
+	// locker coordinates attaching and detaching volumes with other hosts.
+	// It is a NoopLocker unless libstorage.server.locker.type is set.
+	locker  types.Locker
+	lockTTL time.Duration
 }
 
 func (s *storageService) Init(ctx types.Context, config gofig.Config) error {
 	s.config = config
 
+	if err := s.initLogLevel(ctx); err != nil {
+		return err
+	}
+	if s.logLevelSet {
+		ctx = context.WithLogLevel(ctx, s.logLevel)
+	}
+
 	if err := s.initStorageDriver(ctx); err != nil {
 		return err
 	}
+	if err := s.initDriverCache(ctx); err != nil {
+		return err
+	}
+
+	ttl := utils.InstanceCacheTTL(
+		config.GetString(types.ConfigServerInstanceCacheTTL))
+	s.instanceCache = utils.NewInstanceCache(ttl)
+	ctx.WithField("ttl", ttl).Debug("configured instance cache")
+
+	maxAttempts := -1
+	if config.IsSet(types.ConfigServerRetryMaxAttempts) {
+		maxAttempts = config.GetInt(types.ConfigServerRetryMaxAttempts)
+	}
+	s.retryOpts = utils.NewRetryOpts(
+		maxAttempts, config.GetString(types.ConfigServerRetryBaseDelay))
+	retryOps, err := utils.ParseRetryOps(
+		config.GetStringSlice(types.ConfigServerRetryOps))
+	if err != nil {
+		return err
+	}
+	s.retryOpts.Ops = retryOps
+
+	if err := s.initOperationTimeout(ctx); err != nil {
+		return err
+	}
+
+	if err := s.initLocker(ctx); err != nil {
+		return err
+	}
 
 	s.taskExecQueue = make(chan *task)
 	go func() {
@@ -74,10 +128,159 @@ func (s *storageService) initStorageDriver(ctx types.Context) error {
 		return err
 	}
 
-	s.driver = driver
+	// validate the next device info now rather than at the first attach
+	nd, err := driver.NextDeviceInfo(ctx)
+	if err != nil {
+		return err
+	}
+	if err := utils.ValidateNextDeviceInfo(nd); err != nil {
+		return goof.WithFieldE(
+			"driverName", driverName, "invalid next device info", err)
+	}
+
+	reads, mutations, err := utils.ParseRateLimits(s.config)
+	if err != nil {
+		return err
+	}
+	if reads.Rate > 0 || mutations.Rate > 0 {
+		ctx.WithFields(log.Fields{
+			"reads":     reads.Rate,
+			"mutations": mutations.Rate,
+		}).Debug("configured rate limits")
+	}
+	driver = utils.NewRateLimitedStorageDriver(driver, reads, mutations)
+
+	// serialize the operations that modify the same volume
+	s.driver = utils.NewLockedStorageDriver(driver)
+	return nil
+}
+
+// initDriverCache wraps the service's driver with a CachedStorageDriver if
+// libstorage.server.driverCache.ttl is set.
+func (s *storageService) initDriverCache(ctx types.Context) error {
+	val := s.config.GetString(types.ConfigServerDriverCacheTTL)
+	if val == "" {
+		return nil
+	}
+	ttl, err := time.ParseDuration(val)
+	if err != nil {
+		return goof.WithFieldsE(goof.Fields{
+			"service": s.name,
+			"ttl":     val,
+		}, "invalid driver cache ttl", err)
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	size := s.config.GetInt(types.ConfigServerDriverCacheSize)
+	s.driver = utils.NewCachedStorageDriver(s.driver, ttl, size)
+	ctx.WithFields(log.Fields{
+		"ttl":  ttl,
+		"size": size,
+	}).Debug("configured driver cache")
+	return nil
+}
+
+// initOperationTimeout parses the service's operation timeout. The service's
+// own operationTimeout property takes precedence over the server's.
+func (s *storageService) initOperationTimeout(ctx types.Context) error {
+	val := s.config.GetString("operationTimeout")
+	if val == "" {
+		val = s.config.GetString(types.ConfigServerOperationTimeout)
+	}
+	if val == "" {
+		return nil
+	}
+	timeout, err := time.ParseDuration(val)
+	if err != nil {
+		return goof.WithFieldsE(goof.Fields{
+			"service":          s.name,
+			"operationTimeout": val,
+		}, "invalid operation timeout", err)
+	}
+	s.operationTimeout = timeout
+	ctx.WithField("timeout", timeout).Debug("configured operation timeout")
 	return nil
 }
 
+// initLocker initializes the locker specified by the locker type. The
+// service's own locker properties take precedence over the server's.
+func (s *storageService) initLocker(ctx types.Context) error {
+	s.locker = utils.NoopLocker{}
+	s.lockTTL = utils.DefaultLockTTL
+
+	lockerType := s.config.GetString("locker.type")
+	if lockerType == "" {
+		lockerType = s.config.GetString(types.ConfigServerLockerType)
+	}
+	if lockerType == "" {
+		return nil
+	}
+
+	ttl := s.config.GetString("locker.ttl")
+	if ttl == "" {
+		ttl = s.config.GetString(types.ConfigServerLockerTTL)
+	}
+	if ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil || d <= 0 {
+			return goof.WithFieldsE(goof.Fields{
+				"service": s.name,
+				"ttl":     ttl,
+			}, "invalid locker ttl", err)
+		}
+		s.lockTTL = d
+	}
+
+	locker, err := registry.NewLocker(lockerType)
+	if err != nil {
+		return err
+	}
+	if err := locker.Init(ctx, s.config); err != nil {
+		return err
+	}
+	s.locker = locker
+	ctx.WithFields(log.Fields{
+		"locker": locker.Name(),
+		"ttl":    s.lockTTL,
+	}).Info("configured volume locker")
+	return nil
+}
+
+// LockVolume acquires the distributed lock for the volume.
+func (s *storageService) LockVolume(
+	ctx types.Context,
+	volumeID string) (func(), error) {
+
+	return utils.AcquireVolumeLock(
+		ctx, s.locker, s.driver.Name(), volumeID, s.lockTTL)
+}
+
+// initLogLevel parses the service's log level. The level applies to the
+// service's driver as well as to the requests the service handles.
+func (s *storageService) initLogLevel(ctx types.Context) error {
+	val := s.config.GetString("logLevel")
+	if val == "" {
+		return nil
+	}
+	lvl, err := log.ParseLevel(val)
+	if err != nil {
+		return goof.WithFieldsE(goof.Fields{
+			"service":  s.name,
+			"logLevel": val,
+		}, "invalid log level", err)
+	}
+	s.logLevel = lvl
+	s.logLevelSet = true
+	ctx.WithField("logLevel", lvl).Info("configured service log level")
+	return nil
+}
+
+// LogLevel returns the service's log level.
+func (s *storageService) LogLevel() (log.Level, bool) {
+	return s.logLevel, s.logLevelSet
+}
+
 func (s *storageService) Config() gofig.Config {
 	return s.config
 }
@@ -90,11 +293,71 @@ func (s *storageService) Driver() types.StorageDriver {
 	return s.driver
 }
 
+func (s *storageService) InstanceInspect(
+	ctx types.Context,
+	opts types.Store) (*types.Instance, error) {
+
+	ctx, cancel := context.WithDefaultTimeout(ctx, s.operationTimeout)
+	defer cancel()
+
+	inspect := s.instanceInspector(ctx, opts)
+
+	iid, ok := context.InstanceID(ctx)
+	if !ok {
+		return inspect()
+	}
+	return s.instanceCache.Get(iid.String(), inspect)
+}
+
+func (s *storageService) RefreshInstance(
+	ctx types.Context,
+	opts types.Store) (*types.Instance, error) {
+
+	ctx, cancel := context.WithDefaultTimeout(ctx, s.operationTimeout)
+	defer cancel()
+
+	inspect := s.instanceInspector(ctx, opts)
+
+	iid, ok := context.InstanceID(ctx)
+	if !ok {
+		return inspect()
+	}
+	return s.instanceCache.Refresh(iid.String(), inspect)
+}
+
+// instanceInspector returns a function that inspects the instance with the
+// service's driver, retrying the inspection if it is configured to be.
+func (s *storageService) instanceInspector(
+	ctx types.Context,
+	opts types.Store) func() (*types.Instance, error) {
+
+	return func() (i *types.Instance, err error) {
+		err = s.Retry(ctx, utils.RetryOpInstanceInspect, func() error {
+			i, err = s.driver.InstanceInspect(ctx, opts)
+			return err
+		})
+		return
+	}
+}
+
+func (s *storageService) Retry(
+	ctx types.Context, op string, f func() error) error {
+
+	if !s.retryOpts.Retries(op) {
+		return f()
+	}
+	return utils.Retry(ctx, s.retryOpts, f)
+}
+
 func (s *storageService) TaskEnqueue(
 	ctx types.Context,
 	run types.StorageTaskRunFunc,
 	schema []byte) *types.Task {
 
+	if s.operationTimeout > 0 {
+		run = s.withOperationTimeout(run)
+	}
+
 	t := newStorageServiceTask(ctx, run, s, schema)
 	go func() { s.taskExecQueue <- t }()
 	return &t.Task
@@ -103,3 +366,19 @@ func (s *storageService) TaskEnqueue(
 func (s *storageService) Name() string {
 	return s.name
 }
+
+// withOperationTimeout returns a run function that applies the service's
+// operation timeout to the context of the provided run function if the
+// context does not have a deadline.
+func (s *storageService) withOperationTimeout(
+	run types.StorageTaskRunFunc) types.StorageTaskRunFunc {
+
+	return func(
+		ctx types.Context,
+		svc types.StorageService) (interface{}, error) {
+
+		ctx, cancel := context.WithDefaultTimeout(ctx, s.operationTimeout)
+		defer cancel()
+		return run(ctx, svc)
+	}
+}
@@ -45,6 +45,21 @@ const (
 	// ConfigIntegrationDriver is a config key.
 	ConfigIntegrationDriver = ConfigRoot + ".integration.driver"
 
+	// ConfigRateLimit is a config key.
+	ConfigRateLimit = ConfigRoot + ".rateLimit"
+
+	// ConfigRateLimitReads is a config key.
+	ConfigRateLimitReads = ConfigRateLimit + ".reads"
+
+	// ConfigRateLimitReadBurst is a config key.
+	ConfigRateLimitReadBurst = ConfigRateLimit + ".readBurst"
+
+	// ConfigRateLimitMutations is a config key.
+	ConfigRateLimitMutations = ConfigRateLimit + ".mutations"
+
+	// ConfigRateLimitMutationBurst is a config key.
+	ConfigRateLimitMutationBurst = ConfigRateLimit + ".mutationBurst"
+
 	// ConfigLogging is a config key.
 	ConfigLogging = ConfigRoot + ".logging"
 
@@ -142,6 +157,42 @@ const (
 	// ConfigServerTasksLogTimeout is a config key.
 	ConfigServerTasksLogTimeout = ConfigServerTasks + ".logTimeout"
 
+	// ConfigServerInstanceCacheTTL is a config key.
+	ConfigServerInstanceCacheTTL = ConfigServer + ".instanceCache.ttl"
+
+	// ConfigServerDriverCache is a config key.
+	ConfigServerDriverCache = ConfigServer + ".driverCache"
+
+	// ConfigServerDriverCacheTTL is a config key.
+	ConfigServerDriverCacheTTL = ConfigServerDriverCache + ".ttl"
+
+	// ConfigServerDriverCacheSize is a config key.
+	ConfigServerDriverCacheSize = ConfigServerDriverCache + ".size"
+
+	// ConfigServerOperationTimeout is a config key.
+	ConfigServerOperationTimeout = ConfigServer + ".operationTimeout"
+
+	// ConfigServerLocker is a config key.
+	ConfigServerLocker = ConfigServer + ".locker"
+
+	// ConfigServerLockerType is a config key.
+	ConfigServerLockerType = ConfigServerLocker + ".type"
+
+	// ConfigServerLockerTTL is a config key.
+	ConfigServerLockerTTL = ConfigServerLocker + ".ttl"
+
+	// ConfigServerRetry is a config key.
+	ConfigServerRetry = ConfigServer + ".retry"
+
+	// ConfigServerRetryMaxAttempts is a config key.
+	ConfigServerRetryMaxAttempts = ConfigServerRetry + ".maxAttempts"
+
+	// ConfigServerRetryBaseDelay is a config key.
+	ConfigServerRetryBaseDelay = ConfigServerRetry + ".baseDelay"
+
+	// ConfigServerRetryOps is a config key.
+	ConfigServerRetryOps = ConfigServerRetry + ".operations"
+
 	// ConfigClientAuth is a config key.
 	ConfigClientAuth = ConfigClient + ".auth"
 
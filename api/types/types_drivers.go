@@ -11,3 +11,14 @@ type Driver interface {
 	// Init initializes the driver.
 	Init(ctx Context, config gofig.Config) error
 }
+
+// DriverWithClose is a Driver that holds resources, such as client
+// connections or goroutines, that should be released when the driver is no
+// longer used.
+type DriverWithClose interface {
+	Driver
+
+	// Close releases the driver's resources. Calling the driver's other
+	// functions after Close returns ErrClosed.
+	Close() error
+}
@@ -8,17 +8,29 @@ type VolumeMountOpts struct {
 	OverwriteFS bool
 	NewFSType   string
 	Preempt     bool
-	Opts        Store
+
+	// ReadOnly requests the volume be attached and mounted read-only. A
+	// read-only volume is not formatted.
+	ReadOnly bool
+	Opts     Store
 }
 
 // VolumeMapping is a volume's name and the path to which it is mounted.
 type VolumeMapping interface {
+	// VolumeID returns the volume's ID.
+	VolumeID() string
+
 	// VolumeName returns the volume's name.
 	VolumeName() string
 
 	// MountPoint returns the volume's mount point.
 	MountPoint() string
 
+	// InstanceID returns the ID of the instance to which the volume is
+	// attached. An empty string is returned if the volume is not attached
+	// or the instance is unknown.
+	InstanceID() string
+
 	// Status returns the volume's details for an inspect.
 	Status() map[string]interface{}
 }
@@ -36,7 +48,10 @@ type IntegrationDriverManager interface {
 type IntegrationDriver interface {
 	Driver
 
-	// List a map that relates volume names to their mount points.
+	// List a map that relates volume names to their mount points. The
+	// "attachments" key in the provided store filters the volumes by their
+	// attachment state and the "volumeID" key limits the list to a single
+	// volume.
 	List(
 		ctx Context,
 		opts Store) ([]VolumeMapping, error)
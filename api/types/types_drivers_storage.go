@@ -225,22 +225,45 @@ type VolumeInspectOpts struct {
 type VolumeCreateOpts struct {
 	AvailabilityZone *string
 	IOPS             *int64
+	Throughput       *int64
 	Size             *int64
 	Type             *string
 	Encrypted        *bool
 	EncryptionKey    *string
-	Opts             Store
+	Tags             map[string]string
+
+	// IdempotentByName requests that an existing volume with the same name
+	// be returned instead of creating a new volume. The request fails with
+	// an ErrConflict error if the existing volume's properties differ from
+	// the requested properties.
+	IdempotentByName bool
+
+	Opts Store
 }
 
 // VolumeAttachOpts are options for attaching a volume.
 type VolumeAttachOpts struct {
 	NextDevice *string
 	Force      bool
-	Opts       Store
+
+	// MultiAttach requests the volume be attached to the instance even if
+	// the volume is already attached to other instances. The option is
+	// only honored by drivers that support multi-attach.
+	MultiAttach bool
+
+	// ReadOnly requests the volume be attached read-only. Drivers whose
+	// storage platforms cannot attach a volume read-only still record the
+	// request on the volume's attachment so that it is mounted read-only.
+	ReadOnly bool
+	Opts     Store
 }
 
 // VolumeDetachOpts are options for detaching a volume.
 type VolumeDetachOpts struct {
+	// Force requests the backend forcibly detach the volume even if the
+	// instance to which it is attached is unresponsive. A forced detach
+	// does not wait for the volume to be cleanly unmounted and may result
+	// in the loss of data that has not yet been flushed to the volume.
 	Force bool
 	Opts  Store
 }
@@ -251,6 +274,70 @@ type VolumeRemoveOpts struct {
 	Opts  Store
 }
 
+// VolumeResizeOpts are options for resizing a volume.
+type VolumeResizeOpts struct {
+	Opts Store
+}
+
+// VolumeCloneOpts are options for cloning a volume. Fields that are nil
+// are inherited from the source volume.
+type VolumeCloneOpts struct {
+	AvailabilityZone *string
+	IOPS             *int64
+	Type             *string
+	Opts             Store
+}
+
+// VolumesAttachItem describes a single attachment in a batch of attachments.
+type VolumesAttachItem struct {
+	// VolumeID is the ID of the volume to attach.
+	VolumeID string
+
+	// DeviceName is the name of the device at which the volume should be
+	// attached. If empty the driver selects the device.
+	DeviceName string
+}
+
+// SnapshotsCopyItem describes a single snapshot copy in a batch of copies.
+type SnapshotsCopyItem struct {
+	// SnapshotID is the ID of the snapshot to copy.
+	SnapshotID string
+
+	// SnapshotName is the name of the new snapshot.
+	SnapshotName string
+
+	// DestinationID is the ID of the region or other location to which the
+	// snapshot is copied.
+	DestinationID string
+}
+
+// SnapshotCopyResult is the outcome of a single snapshot copy in a batch of
+// copies.
+type SnapshotCopyResult struct {
+	// Item is the copy that was requested.
+	Item *SnapshotsCopyItem
+
+	// Snapshot is the new snapshot. It is nil if the copy failed.
+	Snapshot *Snapshot
+
+	// Error is the reason the copy failed.
+	Error error
+}
+
+// SnapshotsCopyOpts are options for copying a batch of snapshots.
+type SnapshotsCopyOpts struct {
+	// Workers is the maximum number of concurrent copies. A value less than
+	// or equal to zero indicates the default.
+	Workers int
+
+	// Progress, if not nil, is invoked after each copy completes with the
+	// copy's result and the number of copies that have completed. Progress
+	// may be invoked concurrently.
+	Progress func(result *SnapshotCopyResult, completed, total int)
+
+	Opts Store
+}
+
 // StorageDriverManager is the management wrapper for a StorageDriver.
 type StorageDriverManager interface {
 	StorageDriver
@@ -259,6 +346,24 @@ type StorageDriverManager interface {
 	Driver() StorageDriver
 }
 
+// UnwrapStorageDriver returns the driver beneath the provided driver and any
+// StorageDriverManagers that wrap it. The optional interfaces a driver
+// implements, such as StorageDriverVolResize, are not visible through the
+// managers that wrap it, so they should be asserted on the returned driver.
+func UnwrapStorageDriver(d StorageDriver) StorageDriver {
+	for {
+		m, ok := d.(StorageDriverManager)
+		if !ok {
+			return d
+		}
+		u := m.Driver()
+		if u == nil {
+			return d
+		}
+		d = u
+	}
+}
+
 /*
 StorageDriver is a libStorage driver used by the routes to implement the
 backend functionality.
@@ -278,7 +383,8 @@ type StorageDriver interface {
 	Type(
 		ctx Context) (StorageType, error)
 
-	// InstanceInspect returns an instance.
+	// InstanceInspect returns the instance identified by the context's
+	// instance ID.
 	InstanceInspect(
 		ctx Context,
 		opts Store) (*Instance, error)
@@ -327,14 +433,16 @@ type StorageDriver interface {
 		volumeID string,
 		opts *VolumeRemoveOpts) error
 
-	// VolumeAttach attaches a volume and provides a token clients can use
-	// to validate that device has appeared locally.
+	// VolumeAttach attaches a volume to the instance identified by the
+	// context's instance ID and provides a token clients can use to validate
+	// that device has appeared locally.
 	VolumeAttach(
 		ctx Context,
 		volumeID string,
 		opts *VolumeAttachOpts) (*Volume, string, error)
 
-	// VolumeDetach detaches a volume.
+	// VolumeDetach detaches a volume from the instance identified by the
+	// context's instance ID.
 	VolumeDetach(
 		ctx Context,
 		volumeID string,
@@ -387,3 +495,351 @@ type StorageDriverVolInspectByName interface {
 		volumeName string,
 		opts *VolumeInspectOpts) (*Volume, error)
 }
+
+// StorageDriverVolResize is a StorageDriver with a VolumeResize function.
+type StorageDriverVolResize interface {
+	StorageDriver
+
+	// VolumeResize grows an existing volume to the specified size and returns
+	// the updated volume.
+	VolumeResize(
+		ctx Context,
+		volumeID string,
+		size int64,
+		opts *VolumeResizeOpts) (*Volume, error)
+}
+
+// StorageDriverVolClone is a StorageDriver with a VolumeClone function.
+type StorageDriverVolClone interface {
+	StorageDriver
+
+	// VolumeClone creates a new volume backed by the data of an existing
+	// volume using the storage platform's native clone mechanism.
+	VolumeClone(
+		ctx Context,
+		volumeID,
+		volumeName string,
+		opts *VolumeCloneOpts) (*Volume, error)
+}
+
+// StorageDriverVolTags is a StorageDriver with a VolumeSetTags function.
+type StorageDriverVolTags interface {
+	StorageDriver
+
+	// VolumeSetTags adds the provided tags to a volume, replacing the values
+	// of any tags that already exist.
+	VolumeSetTags(
+		ctx Context,
+		volumeID string,
+		tags map[string]string,
+		opts Store) error
+}
+
+// StorageDriverWithCapabilities is a StorageDriver with a Capabilities
+// function.
+type StorageDriverWithCapabilities interface {
+	StorageDriver
+
+	// Capabilities returns the features supported by the driver.
+	Capabilities(
+		ctx Context) (*DriverCapabilities, error)
+}
+
+// StorageDriverVolsByID is a StorageDriver that is able to inspect multiple
+// volumes with a single backend call.
+type StorageDriverVolsByID interface {
+	StorageDriver
+
+	// VolumesByID inspects the volumes with the specified IDs. Volumes that
+	// cannot be found are omitted from the result.
+	VolumesByID(
+		ctx Context,
+		volumeIDs []string,
+		opts *VolumeInspectOpts) ([]*Volume, error)
+}
+
+// VolumeFilter describes the volumes to return when finding volumes. Empty
+// fields match all volumes, so an empty filter matches every volume.
+type VolumeFilter struct {
+	// Name is the volume's name.
+	Name string
+
+	// Status is the volume's status.
+	Status string
+
+	// AvailabilityZone is the zone in which the volume resides.
+	AvailabilityZone string
+
+	// Tags are the key/value pairs with which the volume must be tagged.
+	// The volume may have additional tags.
+	Tags map[string]string
+}
+
+// IsEmpty returns a flag indicating whether or not the filter matches all
+// volumes.
+func (f *VolumeFilter) IsEmpty() bool {
+	return f == nil ||
+		(f.Name == "" &&
+			f.Status == "" &&
+			f.AvailabilityZone == "" &&
+			len(f.Tags) == 0)
+}
+
+// Matches returns a flag indicating whether or not the volume matches the
+// filter.
+func (f *VolumeFilter) Matches(v *Volume) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	if v == nil {
+		return false
+	}
+	if f.Name != "" && f.Name != v.Name {
+		return false
+	}
+	if f.Status != "" && f.Status != v.Status {
+		return false
+	}
+	if f.AvailabilityZone != "" && f.AvailabilityZone != v.AvailabilityZone {
+		return false
+	}
+	for k, tv := range f.Tags {
+		if vv, ok := v.Tags[k]; !ok || vv != tv {
+			return false
+		}
+	}
+	return true
+}
+
+// StorageDriverVolFind is a StorageDriver that is able to filter volumes on
+// the storage platform.
+type StorageDriverVolFind interface {
+	StorageDriver
+
+	// FindVolumes returns the volumes that match the filter. A driver may
+	// apply only part of the filter natively; the remainder of the filter
+	// is applied to the returned volumes by the caller.
+	FindVolumes(
+		ctx Context,
+		filter *VolumeFilter,
+		opts *VolumesOpts) ([]*Volume, error)
+}
+
+// ListOpts are options when listing resources one page at a time.
+type ListOpts struct {
+	// MaxResults is the maximum number of items to return in a single page.
+	// A value less than or equal to zero indicates no limit.
+	MaxResults int64
+
+	// PageToken is the token returned by the previous page. An empty token
+	// requests the first page.
+	PageToken string
+
+	// SortBy is the key by which the items are sorted, one of the ListSortBy
+	// constants. Items with the same key are sorted by their IDs. An empty
+	// value sorts the items by their IDs.
+	SortBy string
+
+	// SortOrder is the order in which the items are sorted, ListSortAsc or
+	// ListSortDesc. An empty value sorts the items in ascending order.
+	SortOrder string
+}
+
+const (
+	// ListSortByID sorts items by their IDs.
+	ListSortByID = "id"
+
+	// ListSortByName sorts items by their names.
+	ListSortByName = "name"
+
+	// ListSortByCreateTime sorts volumes by their create times and snapshots
+	// by their start times.
+	ListSortByCreateTime = "createTime"
+
+	// ListSortBySize sorts volumes by their sizes and snapshots by the sizes
+	// of the volumes to which they belong.
+	ListSortBySize = "size"
+
+	// ListSortAsc sorts items in ascending order.
+	ListSortAsc = "asc"
+
+	// ListSortDesc sorts items in descending order.
+	ListSortDesc = "desc"
+)
+
+// VolumePage is a single page of volumes.
+type VolumePage struct {
+	// Items are the volumes in the page.
+	Items []*Volume
+
+	// NextPageToken is the token used to request the next page. An empty
+	// token indicates this is the final page.
+	NextPageToken string
+}
+
+// SnapshotPage is a single page of snapshots.
+type SnapshotPage struct {
+	// Items are the snapshots in the page.
+	Items []*Snapshot
+
+	// NextPageToken is the token used to request the next page. An empty
+	// token indicates this is the final page.
+	NextPageToken string
+}
+
+// StorageDriverPaged is a StorageDriver that is able to list volumes and
+// snapshots one page at a time. Drivers sort the items in the order requested
+// by the ListOpts.
+type StorageDriverPaged interface {
+	StorageDriver
+
+	// VolumesPaged returns a single page of volumes.
+	VolumesPaged(
+		ctx Context,
+		listOpts *ListOpts,
+		opts *VolumesOpts) (*VolumePage, error)
+
+	// SnapshotsPaged returns a single page of snapshots.
+	SnapshotsPaged(
+		ctx Context,
+		listOpts *ListOpts,
+		opts Store) (*SnapshotPage, error)
+}
+
+// StorageDriverWithPing is a StorageDriver with a Ping function.
+type StorageDriverWithPing interface {
+	StorageDriver
+
+	// Ping performs an inexpensive, authenticated call to the driver's
+	// backend in order to verify it is reachable. Ping returns nil on
+	// success.
+	Ping(ctx Context) error
+}
+
+// StorageDriverSnapRestore is a StorageDriver that is able to restore a
+// snapshot onto an existing volume.
+type StorageDriverSnapRestore interface {
+	StorageDriver
+
+	// SnapshotRestore restores the contents of a snapshot onto an existing
+	// volume.
+	SnapshotRestore(
+		ctx Context,
+		snapshotID, volumeID string,
+		opts Store) (*Volume, error)
+}
+
+// StorageDriverVolRename is a StorageDriver that is able to rename a volume.
+type StorageDriverVolRename interface {
+	StorageDriver
+
+	// VolumeRename changes the name of a volume.
+	VolumeRename(
+		ctx Context,
+		volumeID, newName string,
+		opts Store) (*Volume, error)
+}
+
+// StorageDriverSnapRename is a StorageDriver that is able to rename a
+// snapshot.
+type StorageDriverSnapRename interface {
+	StorageDriver
+
+	// SnapshotRename changes the name of a snapshot.
+	SnapshotRename(
+		ctx Context,
+		snapshotID, newName string,
+		opts Store) (*Snapshot, error)
+}
+
+// StorageDriverSnapCopyIncremental is a StorageDriver that is able to copy
+// only the blocks that differ between a snapshot and a snapshot previously
+// copied to the same destination.
+type StorageDriverSnapCopyIncremental interface {
+	StorageDriver
+
+	// SnapshotCopyIncremental copies an existing snapshot, transferring only
+	// the changes since the base snapshot. The base snapshot is the ID of an
+	// earlier copy at the destination.
+	SnapshotCopyIncremental(
+		ctx Context,
+		snapshotID, snapshotName, destinationID, baseSnapshotID string,
+		opts Store) (*Snapshot, error)
+}
+
+// StorageDriverSnapExport is a StorageDriver that is able to export the
+// contents of a snapshot to a location outside of the storage platform.
+type StorageDriverSnapExport interface {
+	StorageDriver
+
+	// SnapshotExport writes the contents of a snapshot to the destination
+	// URI. The URI's scheme, such as file or s3, selects the writer.
+	SnapshotExport(
+		ctx Context,
+		snapshotID, destinationURI string,
+		opts Store) error
+}
+
+// StorageDriverSnapImport is a StorageDriver that is able to create a
+// snapshot from the contents of an exported snapshot.
+type StorageDriverSnapImport interface {
+	StorageDriver
+
+	// SnapshotImport reads an exported snapshot from the source URI and
+	// registers it as a new snapshot with the provided name. If the name is
+	// empty then the name of the exported snapshot is used.
+	SnapshotImport(
+		ctx Context,
+		sourceURI, snapshotName string,
+		opts Store) (*Snapshot, error)
+}
+
+// StorageDriverInstanceAttachments is a StorageDriver that is able to list
+// an instance's volume attachments without listing every volume.
+type StorageDriverInstanceAttachments interface {
+	StorageDriver
+
+	// InstanceAttachments returns the attachments of the volumes attached to
+	// the instance with the specified ID.
+	InstanceAttachments(
+		ctx Context,
+		instanceID string,
+		opts Store) ([]*VolumeAttachment, error)
+}
+
+// StorageDriverWithCapacity is a StorageDriver that is able to report the
+// capacity available to it.
+type StorageDriverWithCapacity interface {
+	StorageDriver
+
+	// StorageCapacity returns the total, used, and available capacity. The
+	// availability zone scopes the query when relevant and may be empty.
+	StorageCapacity(
+		ctx Context,
+		availabilityZone string,
+		opts Store) (*StorageCapacity, error)
+}
+
+// StorageDriverVolTypes is a StorageDriver that is able to enumerate the
+// types of volumes it can create.
+type StorageDriverVolTypes interface {
+	StorageDriver
+
+	// VolumeTypes returns the types of volumes the driver can create.
+	VolumeTypes(
+		ctx Context,
+		opts Store) ([]*VolumeType, error)
+}
+
+// StorageDriverWithMaxVolumes is a StorageDriver that is able to report how
+// many volumes may be attached to an instance.
+type StorageDriverWithMaxVolumes interface {
+	StorageDriver
+
+	// MaxVolumeCount returns the maximum number of volumes, including the
+	// boot volume, that may be attached to the instance identified by the
+	// context's instance ID.
+	MaxVolumeCount(
+		ctx Context,
+		opts Store) (int, error)
+}
@@ -83,3 +83,37 @@ func TestParseVolumeAttachmentsTypes(t *testing.T) {
 	assert.True(t, a.Devices())
 	assert.True(t, a.Attached())
 }
+
+func TestVolumeFilterMatches(t *testing.T) {
+	v := &Volume{
+		Name:             "data",
+		Status:           VolumeStatusAvailable,
+		AvailabilityZone: "us-east-1a",
+		Tags:             map[string]string{"env": "prod", "team": "db"},
+	}
+
+	var f *VolumeFilter
+	assert.True(t, f.IsEmpty())
+	assert.True(t, f.Matches(v))
+
+	f = &VolumeFilter{}
+	assert.True(t, f.IsEmpty())
+	assert.True(t, f.Matches(v))
+
+	f = &VolumeFilter{Name: "data", Tags: map[string]string{"env": "prod"}}
+	assert.False(t, f.IsEmpty())
+	assert.True(t, f.Matches(v))
+	assert.False(t, f.Matches(nil))
+
+	f = &VolumeFilter{Tags: map[string]string{"env": "dev"}}
+	assert.False(t, f.Matches(v))
+
+	f = &VolumeFilter{Tags: map[string]string{"owner": ""}}
+	assert.False(t, f.Matches(v))
+
+	f = &VolumeFilter{Status: VolumeStatusInUse}
+	assert.False(t, f.Matches(v))
+
+	f = &VolumeFilter{AvailabilityZone: "us-east-1b"}
+	assert.False(t, f.Matches(v))
+}
@@ -11,6 +11,23 @@ var ErrNotImplemented = goof.New("not implemented")
 // ErrTimedOut is the error that is used to indicate an operation timed out.
 var ErrTimedOut = goof.New("timed out")
 
+// ErrUnsupported occurs when an operation or option is not supported by a
+// driver.
+type ErrUnsupported struct{ goof.Goof }
+
+// ErrVolumeAttached occurs when an operation requires a volume to be detached
+// but the volume is attached to one or more instances.
+type ErrVolumeAttached struct{ goof.Goof }
+
+// ErrVolumeNotAttached occurs when an operation requires a volume to be
+// attached but the volume is not attached to any instance.
+type ErrVolumeNotAttached struct{ goof.Goof }
+
+// ErrVolumeAlreadyAttached occurs when attaching a volume that is already
+// attached to another instance and the volume may not be attached to more
+// than one instance at a time.
+type ErrVolumeAlreadyAttached struct{ goof.Goof }
+
 // ErrUnsupportedForClientType is the error that occurs when an operation is
 // invoked that is unsupported for the current client type.
 type ErrUnsupportedForClientType struct{ goof.Goof }
@@ -22,6 +39,33 @@ type ErrBadAdminToken struct{ goof.Goof }
 // resource that cannot be found.
 type ErrNotFound struct{ goof.Goof }
 
+// ErrVolumeNotFound occurs when a Driver inspects or sends an operation to a
+// volume that cannot be found.
+type ErrVolumeNotFound struct{ goof.Goof }
+
+// Unwrap returns the error that caused this error, if any.
+func (e *ErrVolumeNotFound) Unwrap() error {
+	return innerError(e.Goof)
+}
+
+// ErrSnapshotNotFound occurs when a Driver inspects or sends an operation to
+// a snapshot that cannot be found.
+type ErrSnapshotNotFound struct{ goof.Goof }
+
+// Unwrap returns the error that caused this error, if any.
+func (e *ErrSnapshotNotFound) Unwrap() error {
+	return innerError(e.Goof)
+}
+
+// ErrAlreadyExists occurs when a Driver is asked to create a resource with
+// the same name as an existing resource.
+type ErrAlreadyExists struct{ goof.Goof }
+
+// ErrConflict occurs when a Driver is asked to create a resource with the
+// same name as an existing resource whose properties differ from the
+// requested properties.
+type ErrConflict struct{ goof.Goof }
+
 // ErrMissingLocalDevices occurs when an operation requires local devices
 // and they're missing.
 type ErrMissingLocalDevices struct{ goof.Goof }
@@ -30,6 +74,13 @@ type ErrMissingLocalDevices struct{ goof.Goof }
 // the configured service to be avaialble.
 type ErrMissingInstanceID struct{ goof.Goof }
 
+// ErrMissingConfigKeys occurs when a driver is initialized without values
+// for one or more of its required configuration keys.
+type ErrMissingConfigKeys struct{ goof.Goof }
+
+// ErrClosed occurs when a driver is used after it has been closed.
+type ErrClosed struct{ goof.Goof }
+
 // ErrStoreKey occurs when no value exists for a specified store key.
 type ErrStoreKey struct{ goof.Goof }
 
@@ -43,15 +94,121 @@ type ErrContextType struct{ goof.Goof }
 // ErrDriverTypeErr occurs when a Driver is constructed with an invalid type.
 type ErrDriverTypeErr struct{ goof.Goof }
 
+// ErrUnknownDriver occurs when a driver is requested by a name with which no
+// driver is registered.
+type ErrUnknownDriver struct{ goof.Goof }
+
 // ErrBatchProcess occurs when a batch process is interrupted by an error
 // before the process is complete. This error will contain information about
 // the objects for which the process did complete.
 type ErrBatchProcess struct{ goof.Goof }
 
+// Unwrap returns the error that caused this error, if any.
+func (e *ErrBatchProcess) Unwrap() error {
+	return innerError(e.Goof)
+}
+
 // ErrBadFilter occurs when a bad filter is supplied via the filter query
 // string.
 type ErrBadFilter struct{ goof.Goof }
 
+// Unwrap returns the error that caused this error, if any.
+func (e *ErrBadFilter) Unwrap() error {
+	return innerError(e.Goof)
+}
+
+// ErrNoAvailableDevice occurs when every device name described by a driver's
+// NextDeviceInfo is in use.
+type ErrNoAvailableDevice struct{ goof.Goof }
+
+// ErrNextDeviceIgnored occurs when the next available device is requested
+// for a driver whose NextDeviceInfo indicates device names should not be
+// predicted by the client.
+type ErrNextDeviceIgnored struct{ goof.Goof }
+
+// ErrBadNextDeviceInfo occurs when a driver's NextDeviceInfo cannot be used
+// to determine the next available device name.
+type ErrBadNextDeviceInfo struct{ goof.Goof }
+
+// ErrUnsupportedFSType occurs when a device cannot be formatted with the
+// requested file system type.
+type ErrUnsupportedFSType struct{ goof.Goof }
+
+// ErrMountFailed occurs when a device cannot be mounted.
+type ErrMountFailed struct{ goof.Goof }
+
+// Unwrap returns the error that caused this error, if any.
+func (e *ErrMountFailed) Unwrap() error {
+	return innerError(e.Goof)
+}
+
+// ErrAlreadyMounted occurs when a device is already mounted or when a mount
+// point is already in use.
+type ErrAlreadyMounted struct{ goof.Goof }
+
+// ErrNotMounted occurs when a path that is expected to be a mount point is
+// not one.
+type ErrNotMounted struct{ goof.Goof }
+
+// ErrInvalidVolumeType occurs when a volume is requested with a type the
+// driver does not offer, or with a size or IOPS outside the constraints of
+// the type.
+type ErrInvalidVolumeType struct{ goof.Goof }
+
+// ErrLimitExceeded occurs when a volume cannot be attached to an instance
+// because the instance has the maximum number of volumes attached.
+type ErrLimitExceeded struct{ goof.Goof }
+
+// ErrInvalidArtifact occurs when a snapshot artifact that is being imported
+// is not in the format written by a snapshot export.
+type ErrInvalidArtifact struct{ goof.Goof }
+
+// Unwrap returns the error that caused this error, if any.
+func (e *ErrInvalidArtifact) Unwrap() error {
+	return innerError(e.Goof)
+}
+
+// ErrTemporary occurs when an operation fails due to a condition that is
+// expected to clear on its own, such as rate limiting or a server error on
+// the storage platform. The operation may succeed if it is retried.
+type ErrTemporary struct{ goof.Goof }
+
+// Unwrap returns the error that caused this error, if any.
+func (e *ErrTemporary) Unwrap() error {
+	return innerError(e.Goof)
+}
+
+// Temporary returns true, indicating the operation that caused the error may
+// be retried.
+func (e *ErrTemporary) Temporary() bool {
+	return true
+}
+
+// ErrRateLimited occurs when an operation is rate limited and a token would
+// not be available before the operation's deadline.
+type ErrRateLimited struct{ goof.Goof }
+
+// Temporary returns true, indicating the operation that caused the error may
+// be retried.
+func (e *ErrRateLimited) Temporary() bool {
+	return true
+}
+
+// ErrLockTimeout occurs when a lock cannot be acquired before the context
+// of the operation that requires it is done.
+type ErrLockTimeout struct{ goof.Goof }
+
+// Unwrap returns the error that caused this error, if any.
+func (e *ErrLockTimeout) Unwrap() error {
+	return innerError(e.Goof)
+}
+
+// Temporary returns true, indicating the operation that caused the error may
+// be retried.
+func (e *ErrLockTimeout) Temporary() bool {
+	return true
+}
+
 // ErrMissingStorageService occurs when the storage service is expected in
 // the provided context but is not there.
 var ErrMissingStorageService = goof.New("missing storage service")
@@ -124,3 +281,12 @@ type ErrKnownHostConflict struct {
 func (e *ErrKnownHostConflict) Error() string {
 	return "known host signature has changed"
 }
+
+// innerError returns the inner error of a goof error, if any.
+func innerError(g goof.Goof) error {
+	if g == nil {
+		return nil
+	}
+	err, _ := g.Fields()["inner"].(error)
+	return err
+}
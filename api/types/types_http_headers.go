@@ -13,6 +13,10 @@ const (
 	// sent from the client.
 	TransactionHeader = "Libstorage-Tx"
 
+	// RequestIDHeader is the HTTP header that contains the ID used to
+	// correlate the log entries of a request that spans multiple operations.
+	RequestIDHeader = "Libstorage-Requestid"
+
 	// ServerNameHeader is the HTTP header that contains the randomly generated
 	// name the server creates for unique identification when the server starts
 	// for the first time. This header is provided with every response sent
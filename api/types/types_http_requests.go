@@ -11,8 +11,11 @@ type VolumeCreateRequest struct {
 	Encrypted        *bool                  `json:"encrypted,omitempty"`
 	EncryptionKey    *string                `json:"encryptionKey,omitempty"`
 	IOPS             *int64                 `json:"iops,omitempty"`
+	Throughput       *int64                 `json:"throughput,omitempty"`
 	Size             *int64                 `json:"size,omitempty"`
 	Type             *string                `json:"type,omitempty"`
+	Tags             map[string]string      `json:"tags,omitempty"`
+	IdempotentByName bool                   `json:"idempotentByName,omitempty"`
 	Opts             map[string]interface{} `json:"opts,omitempty"`
 }
 
@@ -25,13 +28,16 @@ type VolumeCopyRequest struct {
 // VolumeSnapshotRequest is the JSON body for snapshotting a volume.
 type VolumeSnapshotRequest struct {
 	SnapshotName string                 `json:"snapshotName"`
+	ExpireTime   int64                  `json:"expireTime,omitempty"`
 	Opts         map[string]interface{} `json:"opts,omitempty"`
 }
 
 // VolumeAttachRequest is the JSON body for attaching a volume to an instance.
 type VolumeAttachRequest struct {
 	Force          bool                   `json:"force,omitempty"`
+	MultiAttach    bool                   `json:"multiAttach,omitempty"`
 	NextDeviceName *string                `json:"nextDeviceName,omitempty"`
+	ReadOnly       bool                   `json:"readOnly,omitempty"`
 	Opts           map[string]interface{} `json:"opts,omitempty"`
 }
 
@@ -43,9 +49,10 @@ type VolumeDetachRequest struct {
 
 // SnapshotCopyRequest is the JSON body for copying a snapshot.
 type SnapshotCopyRequest struct {
-	SnapshotName  string                 `json:"snapshotName"`
-	DestinationID string                 `json:"destinationID"`
-	Opts          map[string]interface{} `json:"opts,omitempty"`
+	SnapshotName   string                 `json:"snapshotName"`
+	DestinationID  string                 `json:"destinationID"`
+	BaseSnapshotID string                 `json:"baseSnapshotID,omitempty"`
+	Opts           map[string]interface{} `json:"opts,omitempty"`
 }
 
 // SnapshotRemoveRequest is the JSON body for removing a snapshot.
@@ -132,3 +132,23 @@ type byString []string
 func (a byString) Len() int           { return len(a) }
 func (a byString) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a byString) Less(i, j int) bool { return a[i] < a[j] }
+
+// DeviceStats are a local device's cumulative I/O counters since the host
+// booted. The counters are zero when the host does not provide statistics
+// for the device, such as on hosts other than Linux or for devices that are
+// not block devices. Rates such as IOPS are derived by sampling the counters
+// twice.
+type DeviceStats struct {
+
+	// ReadBytes is the number of bytes read from the device.
+	ReadBytes uint64 `json:"readBytes"`
+
+	// WriteBytes is the number of bytes written to the device.
+	WriteBytes uint64 `json:"writeBytes"`
+
+	// ReadOps is the number of completed reads.
+	ReadOps uint64 `json:"readOps"`
+
+	// WriteOps is the number of completed writes.
+	WriteOps uint64 `json:"writeOps"`
+}
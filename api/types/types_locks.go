@@ -0,0 +1,22 @@
+package types
+
+import "time"
+
+// NewLocker is a function that constructs a new Locker.
+type NewLocker func() Locker
+
+// Locker coordinates operations on a resource, such as attaching a volume,
+// across processes or hosts. Lockers are registered with the registry and
+// are backed by a distributed store such as etcd, Consul, or Redis.
+type Locker interface {
+	Driver
+
+	// Acquire acquires the lock identified by the key, waiting until the
+	// lock is available or the context is done. The lock is released
+	// automatically once the TTL elapses in case the holder fails to
+	// release it.
+	Acquire(ctx Context, key string, ttl time.Duration) error
+
+	// Release releases the lock identified by the key.
+	Release(ctx Context, key string) error
+}
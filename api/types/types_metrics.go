@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+const (
+	// MetricOperations is the name of the counter incremented each time a
+	// driver operation completes. The counter's labels include the
+	// operation's status, either "success" or "failure".
+	MetricOperations = "libstorage_driver_operations_total"
+
+	// MetricOperationLatency is the name of the metric that observes the
+	// duration of driver operations.
+	MetricOperationLatency = "libstorage_driver_operation_duration"
+)
+
+// Metrics records the counts and latencies of driver operations. A Metrics
+// is an adapter between libStorage and a metrics library, such as
+// Prometheus, and allows libStorage to remain free of a dependency on any
+// one library. A Metrics must be safe for concurrent use.
+type Metrics interface {
+
+	// IncrCounter increments the named counter.
+	IncrCounter(name string, labels map[string]string)
+
+	// ObserveLatency records a duration with the named metric.
+	ObserveLatency(name string, d time.Duration, labels map[string]string)
+}
@@ -1,6 +1,12 @@
 package types
 
-import "strconv"
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/akutz/goof"
+)
 
 // StorageType is the type of storage a driver provides.
 type StorageType string
@@ -46,10 +52,43 @@ type Instance struct {
 	// The region from which the object originates.
 	Region string `json:"region,omitempty" yaml:",omitempty"`
 
+	// The availability zone in which the instance is located.
+	AvailabilityZone string `json:"availabilityZone,omitempty" yaml:"availabilityZone,omitempty"`
+
 	// Fields are additional properties that can be defined for this type.
 	Fields map[string]string `json:"fields,omitempty" yaml:",omitempty"`
 }
 
+// String returns the string representation of an Instance object, ex.
+// "providerName=ebs, instanceID=ebs=i-1234, name=node1, region=us-east-1".
+func (i *Instance) String() string {
+	t := &bytes.Buffer{}
+	fmt.Fprintf(t, "providerName=%s", i.ProviderName)
+	if i.InstanceID != nil {
+		fmt.Fprintf(t, ", instanceID=%s", i.InstanceID)
+	}
+	if i.Name != "" {
+		fmt.Fprintf(t, ", name=%s", i.Name)
+	}
+	if i.Region != "" {
+		fmt.Fprintf(t, ", region=%s", i.Region)
+	}
+	if i.AvailabilityZone != "" {
+		fmt.Fprintf(t, ", availabilityZone=%s", i.AvailabilityZone)
+	}
+	return t.String()
+}
+
+// Validate returns an error if the Instance is missing its instance ID. The
+// name and region are optional as not every storage platform has them.
+func (i *Instance) Validate() error {
+	if i.InstanceID == nil || i.InstanceID.ID == "" {
+		return &ErrMissingInstanceID{Goof: goof.WithField(
+			"providerName", i.ProviderName, "instance missing instance ID")}
+	}
+	return nil
+}
+
 // MountInfo reveals information about a particular mounted filesystem. This
 // struct is populated from the content in the /proc/<pid>/mountinfo file.
 type MountInfo struct {
@@ -107,18 +146,45 @@ type Snapshot struct {
 	// The snapshot's ID.
 	ID string `json:"id" yaml:"id"`
 
-	// The time (epoch) at which the request to create the snapshot was submitted.
+	// The time (epoch) at which the request to create the snapshot was
+	// submitted. The value is zero if the storage platform does not report
+	// when a snapshot was created.
 	StartTime int64 `json:"startTime,omitempty" yaml:"startTime,omitempty"`
 
-	// The status of the snapshot.
+	// ExpireTime is the time (epoch) at which the snapshot expires. The
+	// value is zero if the snapshot does not expire. libStorage does not
+	// remove expired snapshots; see utils.ExpiredSnapshots.
+	ExpireTime int64 `json:"expireTime,omitempty" yaml:"expireTime,omitempty"`
+
+	// The status of the snapshot. A snapshot's status is one of the
+	// SnapshotStatus constants unless the storage platform reports a state
+	// that has no canonical equivalent. A snapshot begins as pending and
+	// transitions to either completed or error. Drivers that create
+	// snapshots asynchronously return pending snapshots from VolumeSnapshot,
+	// and callers should poll SnapshotInspect until the status changes.
 	Status string `json:"status,omitempty" yaml:",omitempty"`
 
+	// Progress is the percentage, from 0 to 100, of the snapshot that is
+	// complete.
+	Progress int `json:"progress,omitempty" yaml:"progress,omitempty"`
+
 	// The ID of the volume to which the snapshot belongs.
 	VolumeID string `json:"volumeID,omitempty" yaml:"volumeID,omitempty"`
 
 	// The size of the volume to which the snapshot belongs.
 	VolumeSize int64 `json:"volumeSize,omitempty" yaml:"volumeSize,omitempty"`
 
+	// Size is the amount of storage, in GB, consumed by the snapshot. An
+	// incremental snapshot may be much smaller than the volume to which it
+	// belongs. The value is zero if the storage platform does not report the
+	// size of a snapshot.
+	Size int64 `json:"size,omitempty" yaml:"size,omitempty"`
+
+	// FullCopy is a flag indicating that an incremental copy was requested
+	// but the snapshot was copied in full because the storage platform
+	// cannot copy a snapshot incrementally.
+	FullCopy bool `json:"fullCopy,omitempty" yaml:"fullCopy,omitempty"`
+
 	// Fields are additional properties that can be defined for this type.
 	Fields map[string]string `json:"fields,omitempty" yaml:",omitempty"`
 }
@@ -162,6 +228,66 @@ func (s VolumeAttachmentStates) String() string {
 	return strconv.Itoa(int(s))
 }
 
+const (
+	// VolumeStatusCreating is the status of a volume that is being created.
+	VolumeStatusCreating = "creating"
+
+	// VolumeStatusAvailable is the status of a volume that is ready for use
+	// and not attached to an instance.
+	VolumeStatusAvailable = "available"
+
+	// VolumeStatusAttaching is the status of a volume that is being attached
+	// to an instance.
+	VolumeStatusAttaching = "attaching"
+
+	// VolumeStatusInUse is the status of a volume that is attached to an
+	// instance.
+	VolumeStatusInUse = "in-use"
+
+	// VolumeStatusDetaching is the status of a volume that is being detached
+	// from an instance.
+	VolumeStatusDetaching = "detaching"
+
+	// VolumeStatusDeleting is the status of a volume that is being removed.
+	VolumeStatusDeleting = "deleting"
+
+	// VolumeStatusError is the status of a volume that is in an error state.
+	VolumeStatusError = "error"
+
+	// VolumeStatusDryRun is the status of a volume returned by a dry run of
+	// an operation that would have created the volume.
+	VolumeStatusDryRun = "dry-run"
+)
+
+const (
+	// SnapshotStatusPending is the status of a snapshot that is being
+	// created.
+	SnapshotStatusPending = "pending"
+
+	// SnapshotStatusCompleted is the status of a snapshot that is ready for
+	// use.
+	SnapshotStatusCompleted = "completed"
+
+	// SnapshotStatusError is the status of a snapshot that could not be
+	// created.
+	SnapshotStatusError = "error"
+
+	// SnapshotStatusDryRun is the status of a snapshot returned by a dry run
+	// of an operation that would have created the snapshot.
+	SnapshotStatusDryRun = "dry-run"
+)
+
+const (
+	// SnapshotExpireTimeKey is the key of the VolumeSnapshot option that
+	// specifies the time (epoch) at which the new snapshot expires.
+	SnapshotExpireTimeKey = "expireTime"
+
+	// SnapshotExpireTimeTag is the tag with which drivers that can tag
+	// snapshots record a snapshot's expire time. Drivers that cannot tag
+	// snapshots record the expire time in the snapshot's description.
+	SnapshotExpireTimeTag = "libstorage-expire-time"
+)
+
 // Volume provides information about a storage volume.
 type Volume struct {
 	// Attachments is information about the instances to which the volume
@@ -178,12 +304,22 @@ type Volume struct {
 	// The availability zone for which the volume is available.
 	AvailabilityZone string `json:"availabilityZone,omitempty" yaml:"availabilityZone,omitempty"`
 
+	// The time (epoch) at which the volume was created. The value is zero if
+	// the storage platform does not report when a volume was created.
+	CreateTime int64 `json:"createTime,omitempty" yaml:"createTime,omitempty"`
+
 	// A flag indicating whether or not the volume is encrypted.
 	Encrypted bool `json:"encrypted,omitempty" yaml:"encrypted,omitempty"`
 
+	// EncryptionKey is the ID of the key used to encrypt the volume.
+	EncryptionKey string `json:"encryptionKey,omitempty" yaml:"encryptionKey,omitempty"`
+
 	// The volume IOPs.
 	IOPS int64 `json:"iops,omitempty" yaml:"iops,omitempty"`
 
+	// Throughput is the volume's provisioned throughput in MiB/s.
+	Throughput int64 `json:"throughput,omitempty" yaml:"throughput,omitempty"`
+
 	// The name of the volume.
 	Name string `json:"name" yaml:"name,omitempty"`
 
@@ -197,6 +333,9 @@ type Volume struct {
 	// The volume status.
 	Status string `json:"status,omitempty" yaml:"status,omitempty"`
 
+	// Tags are the key/value pairs with which the volume is tagged.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
 	// ID is a piece of information that uniquely identifies the volume on
 	// the storage platform to which the volume belongs. A volume ID is not
 	// guaranteed to be unique across multiple, configured services.
@@ -222,6 +361,15 @@ func (v *Volume) MountPoint() string {
 	return v.Attachments[0].MountPoint
 }
 
+// InstanceID returns the ID of the instance to which the volume is attached,
+// if the volume is attached.
+func (v *Volume) InstanceID() string {
+	if len(v.Attachments) == 0 || v.Attachments[0].InstanceID == nil {
+		return ""
+	}
+	return v.Attachments[0].InstanceID.ID
+}
+
 // VolumeAttachment provides information about an object attached to a
 // storage volume.
 type VolumeAttachment struct {
@@ -229,15 +377,33 @@ type VolumeAttachment struct {
 	// attached is mounted.
 	DeviceName string `json:"deviceName" yaml:"deviceName,omitempty"`
 
+	// RequestedDeviceName is the name of the device requested when the
+	// volume was attached. This field is set when the storage platform may
+	// attach the volume at a device other than the one requested, such as an
+	// NVMe device.
+	RequestedDeviceName string `json:"requestedDeviceName,omitempty" yaml:"requestedDeviceName,omitempty"`
+
 	// MountPoint is the mount point for the volume. This field is set when a
 	// volume is retrieved via an integration driver.
 	MountPoint string `json:"mountPoint,omitempty" yaml:"mountPoint,omitempty"`
 
+	// ReadOnly is a flag indicating whether or not the volume is attached
+	// read-only. The flag is false for storage platforms that do not have a
+	// concept of read-only attachments.
+	ReadOnly bool `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+
 	// The ID of the instance on which the volume to which the attachment
 	// belongs is mounted.
 	InstanceID *InstanceID `json:"instanceID" yaml:"instanceID,omitempty"`
 
-	// The status of the attachment.
+	// The status of the attachment. An attachment's status is one of the
+	// AttachmentStatus constants unless the storage platform reports a state
+	// that has no canonical equivalent. An attachment begins as attaching and
+	// transitions to attached once its device is ready to be used. Removing
+	// the attachment transitions it to detaching and then to detached.
+	// Drivers that attach volumes asynchronously return attaching
+	// attachments from VolumeAttach, and callers should poll VolumeInspect
+	// until the status is attached before using the device.
 	Status string `json:"status" yaml:",omitempty"`
 
 	// The ID of the volume to which the attachment belongs.
@@ -247,6 +413,24 @@ type VolumeAttachment struct {
 	Fields map[string]string `json:"fields,omitempty" yaml:",omitempty"`
 }
 
+const (
+	// AttachmentStatusAttaching is the status of an attachment that is in
+	// progress.
+	AttachmentStatusAttaching = "attaching"
+
+	// AttachmentStatusAttached is the status of an attachment whose device
+	// is ready to be used.
+	AttachmentStatusAttached = "attached"
+
+	// AttachmentStatusDetaching is the status of an attachment that is being
+	// removed.
+	AttachmentStatusDetaching = "detaching"
+
+	// AttachmentStatusDetached is the status of an attachment that has been
+	// removed.
+	AttachmentStatusDetached = "detached"
+)
+
 // VolumeDevice provides information about a volume's backing storage
 // device. This might be a block device, NAS device, object device, etc.
 type VolumeDevice struct {
@@ -297,6 +481,84 @@ type DriverInfo struct {
 
 	// NextDevice is the next available device information for the service.
 	NextDevice *NextDeviceInfo `json:"nextDevice,omitempty" yaml:"nextDevice,omitempty"`
+
+	// Capabilities are the features supported by the driver.
+	Capabilities *DriverCapabilities `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+}
+
+// VolumeType describes a type of volume offered by a storage platform and the
+// constraints on the volumes of that type. A zero minimum or maximum
+// indicates the value is not constrained. Sizes are in GB.
+type VolumeType struct {
+	// Name is the value of a volume's Type field and the value to request
+	// when creating a volume of this type.
+	Name string `json:"name" yaml:"name"`
+
+	// Description is a human readable description of the volume type.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// MinSize is the smallest size of a volume of this type.
+	MinSize int64 `json:"minSize,omitempty" yaml:"minSize,omitempty"`
+
+	// MaxSize is the largest size of a volume of this type.
+	MaxSize int64 `json:"maxSize,omitempty" yaml:"maxSize,omitempty"`
+
+	// MinIOPS is the fewest IOPS that may be provisioned for a volume of this
+	// type.
+	MinIOPS int64 `json:"minIOPS,omitempty" yaml:"minIOPS,omitempty"`
+
+	// MaxIOPS is the most IOPS that may be provisioned for a volume of this
+	// type.
+	MaxIOPS int64 `json:"maxIOPS,omitempty" yaml:"maxIOPS,omitempty"`
+
+	// SupportsIOPS indicates whether or not IOPS may be provisioned for a
+	// volume of this type.
+	SupportsIOPS bool `json:"supportsIOPS,omitempty" yaml:"supportsIOPS,omitempty"`
+}
+
+// StorageCapacity describes the capacity available to a storage driver. All
+// values are in bytes.
+type StorageCapacity struct {
+	// TotalBytes is the total capacity, or quota, available to the driver.
+	TotalBytes int64 `json:"totalBytes" yaml:"totalBytes"`
+
+	// UsedBytes is the capacity currently in use.
+	UsedBytes int64 `json:"usedBytes" yaml:"usedBytes"`
+
+	// AvailableBytes is the capacity remaining for new volumes.
+	AvailableBytes int64 `json:"availableBytes" yaml:"availableBytes"`
+}
+
+// DriverCapabilities describes the optional features supported by a storage
+// driver. Callers may inspect a driver's capabilities in order to avoid
+// invoking operations the driver does not support.
+type DriverCapabilities struct {
+	// SupportsSnapshots indicates whether or not the driver can create and
+	// manage snapshots.
+	SupportsSnapshots bool `json:"supportsSnapshots"`
+
+	// SupportsResize indicates whether or not the driver can grow a volume.
+	SupportsResize bool `json:"supportsResize"`
+
+	// SupportsClone indicates whether or not the driver can create a volume
+	// from an existing volume.
+	SupportsClone bool `json:"supportsClone"`
+
+	// SupportsMultiAttach indicates whether or not a volume may be attached
+	// to more than one instance at a time.
+	SupportsMultiAttach bool `json:"supportsMultiAttach"`
+
+	// SupportsEncryption indicates whether or not the driver can create
+	// encrypted volumes.
+	SupportsEncryption bool `json:"supportsEncryption"`
+
+	// SupportsCopySnapshot indicates whether or not the driver can copy an
+	// existing snapshot.
+	SupportsCopySnapshot bool `json:"supportsCopySnapshot"`
+
+	// SupportsThroughput indicates whether or not the driver can provision
+	// a volume's throughput independently of its IOPS.
+	SupportsThroughput bool `json:"supportsThroughput"`
 }
 
 // NextDeviceInfo assists the libStorage client in determining the
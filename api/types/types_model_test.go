@@ -1,10 +1,13 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
 	"gopkg.in/yaml.v1"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestVolumeMarshalToYAML(t *testing.T) {
@@ -45,6 +48,26 @@ func TestVolumeMarshalToYAML(t *testing.T) {
 	fmt.Println(string(out))
 }
 
+func TestVolumeInstanceID(t *testing.T) {
+	v := &Volume{ID: "vol-000"}
+	if iid := v.InstanceID(); iid != "" {
+		t.Fatalf("unexpected instance ID: %s", iid)
+	}
+
+	v.Attachments = []*VolumeAttachment{
+		&VolumeAttachment{
+			InstanceID: &InstanceID{
+				ID:     "hi",
+				Driver: "vfs",
+			},
+			VolumeID: "vol-000",
+		},
+	}
+	if iid := v.InstanceID(); iid != "hi" {
+		t.Fatalf("unexpected instance ID: %s", iid)
+	}
+}
+
 func TestInstanceMarshalToYAML(t *testing.T) {
 
 	iid := &InstanceID{
@@ -69,6 +92,47 @@ func TestInstanceMarshalToYAML(t *testing.T) {
 	fmt.Println(string(out))
 }
 
+func TestInstanceString(t *testing.T) {
+	i := &Instance{
+		InstanceID: &InstanceID{
+			ID:     "i-1234",
+			Driver: "ebs",
+		},
+		Name:         "node1",
+		ProviderName: "ebs",
+		Region:       "us-east-1",
+	}
+	assert.Equal(t,
+		"providerName=ebs, instanceID=ebs=i-1234, name=node1, region=us-east-1",
+		i.String())
+
+	i.AvailabilityZone = "us-east-1a"
+	assert.Equal(t,
+		"providerName=ebs, instanceID=ebs=i-1234, name=node1, "+
+			"region=us-east-1, availabilityZone=us-east-1a",
+		i.String())
+
+	i = &Instance{ProviderName: "vfs"}
+	assert.Equal(t, "providerName=vfs", i.String())
+}
+
+func TestInstanceValidate(t *testing.T) {
+	i := &Instance{
+		InstanceID: &InstanceID{
+			ID:     "i-1234",
+			Driver: "ebs",
+		},
+		ProviderName: "ebs",
+	}
+	assert.NoError(t, i.Validate())
+
+	i.InstanceID.ID = ""
+	assert.IsType(t, &ErrMissingInstanceID{}, i.Validate())
+
+	i.InstanceID = nil
+	assert.IsType(t, &ErrMissingInstanceID{}, i.Validate())
+}
+
 func TestInstanceWithOnlyInstanceIDMarshalToYAML(t *testing.T) {
 
 	i := &Instance{
@@ -85,3 +149,93 @@ func TestInstanceWithOnlyInstanceIDMarshalToYAML(t *testing.T) {
 
 	fmt.Println(string(out))
 }
+
+// expectedVolumeJSON is the wire format of a fully populated volume. The field
+// names are part of the API contract and must not change.
+const expectedVolumeJSON = `{"attachments":[{"deviceName":"/dev/xvda",` +
+	`"mountPoint":"/mnt","instanceID":null,"status":"attached",` +
+	`"volumeID":"vol-000"}],"attachmentState":2,` +
+	`"availabilityZone":"zone-000","createTime":1490000000,` +
+	`"encrypted":true,"encryptionKey":"key-000","iops":100,` +
+	`"throughput":10,"name":"Volume 000","networkName":"net-000",` +
+	`"size":10240,"status":"in-use","tags":{"env":"test"},"id":"vol-000",` +
+	`"type":"gp2","fields":{"priority":"2"}}`
+
+func TestVolumeMarshalJSON(t *testing.T) {
+
+	v1 := &Volume{
+		Attachments: []*VolumeAttachment{
+			&VolumeAttachment{
+				DeviceName: "/dev/xvda",
+				MountPoint: "/mnt",
+				Status:     "attached",
+				VolumeID:   "vol-000",
+			},
+		},
+		AttachmentState:  VolumeAttached,
+		AvailabilityZone: "zone-000",
+		CreateTime:       1490000000,
+		Encrypted:        true,
+		EncryptionKey:    "key-000",
+		IOPS:             100,
+		Throughput:       10,
+		Name:             "Volume 000",
+		NetworkName:      "net-000",
+		Size:             10240,
+		Status:           VolumeStatusInUse,
+		Tags:             map[string]string{"env": "test"},
+		ID:               "vol-000",
+		Type:             "gp2",
+		Fields:           map[string]string{"priority": "2"},
+	}
+
+	buf, err := json.Marshal(v1)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedVolumeJSON, string(buf))
+
+	v2 := &Volume{}
+	assert.NoError(t, json.Unmarshal(buf, v2))
+	assert.EqualValues(t, v1, v2)
+
+	// optional fields are omitted
+	buf, err = json.Marshal(&Volume{})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"","id":"","type":""}`, string(buf))
+}
+
+// expectedSnapshotJSON is the wire format of a fully populated snapshot. The
+// field names are part of the API contract and must not change.
+const expectedSnapshotJSON = `{"description":"desc","name":"Snapshot 000",` +
+	`"encrypted":true,"id":"snap-000","startTime":1490000000,` +
+	`"status":"completed","progress":100,"volumeID":"vol-000",` +
+	`"volumeSize":10240,"size":512,"fields":{"priority":"2"}}`
+
+func TestSnapshotMarshalJSON(t *testing.T) {
+
+	s1 := &Snapshot{
+		Description: "desc",
+		Name:        "Snapshot 000",
+		Encrypted:   true,
+		ID:          "snap-000",
+		StartTime:   1490000000,
+		Status:      SnapshotStatusCompleted,
+		Progress:    100,
+		VolumeID:    "vol-000",
+		VolumeSize:  10240,
+		Size:        512,
+		Fields:      map[string]string{"priority": "2"},
+	}
+
+	buf, err := json.Marshal(s1)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSnapshotJSON, string(buf))
+
+	s2 := &Snapshot{}
+	assert.NoError(t, json.Unmarshal(buf, s2))
+	assert.EqualValues(t, s1, s2)
+
+	// optional fields are omitted
+	buf, err = json.Marshal(&Snapshot{})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":""}`, string(buf))
+}
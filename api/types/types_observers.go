@@ -0,0 +1,22 @@
+package types
+
+// VolumeObserver is notified of the changes to volumes made by a driver.
+// Observers are notified after an operation succeeds and are invoked
+// asynchronously so they do not delay the operation; the order in which
+// notifications are received is not guaranteed. A VolumeObserver must be
+// safe for concurrent use.
+type VolumeObserver interface {
+
+	// OnCreate is invoked after a volume is created, including when the
+	// volume is created from a snapshot or copied from another volume.
+	OnCreate(ctx Context, volume *Volume)
+
+	// OnAttach is invoked after a volume is attached.
+	OnAttach(ctx Context, volume *Volume)
+
+	// OnDetach is invoked after a volume is detached.
+	OnDetach(ctx Context, volume *Volume)
+
+	// OnRemove is invoked after a volume is removed.
+	OnRemove(ctx Context, volumeID string)
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces the value of a secret when it is logged or returned in an
+// error.
+const Redacted = "******"
+
+// minSecretLen is the length of the shortest value that is registered as a
+// secret. Shorter values would redact unrelated text.
+const minSecretLen = 4
+
+// secretConfigKeys are the lower-case names of the configuration properties
+// whose values are secrets, such as the ebs.secretKey and
+// libstorage.server.auth.key properties.
+var secretConfigKeys = map[string]bool{
+	"accesskey":        true,
+	"chappassword":     true,
+	"clientsecret":     true,
+	"key":              true,
+	"password":         true,
+	"secretkey":        true,
+	"storageaccesskey": true,
+	"token":            true,
+	"tokenid":          true,
+}
+
+var (
+	secrets  []string
+	secretsL = &sync.RWMutex{}
+)
+
+// IsSecretConfigKey returns a flag indicating whether or not the value of the
+// configuration property is a secret. Only the last segment of the property's
+// name is considered, and the comparison is case-insensitive.
+func IsSecretConfigKey(key string) bool {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		key = key[i+1:]
+	}
+	return secretConfigKeys[strings.ToLower(key)]
+}
+
+// RegisterSecret registers a value that RedactSecrets replaces with Redacted.
+// Values shorter than four characters are ignored.
+//
+// The value is also registered as it appears in a JSON string, such as an
+// API error response, since encoding escapes characters such as &, <, and ".
+func RegisterSecret(secret string) {
+	if len(secret) < minSecretLen {
+		return
+	}
+	secretsL.Lock()
+	defer secretsL.Unlock()
+	registerSecret(secret)
+	if buf, err := json.Marshal(secret); err == nil {
+		registerSecret(string(buf[1 : len(buf)-1]))
+	}
+
+	// replace longer secrets first so that a secret that contains another
+	// secret is redacted in full
+	sort.Sort(byLenDesc(secrets))
+}
+
+func registerSecret(secret string) {
+	for _, s := range secrets {
+		if s == secret {
+			return
+		}
+	}
+	secrets = append(secrets, secret)
+}
+
+// RedactSecrets returns the string with each registered secret replaced by
+// Redacted.
+func RedactSecrets(s string) string {
+	secretsL.RLock()
+	defer secretsL.RUnlock()
+	for _, secret := range secrets {
+		if strings.Contains(s, secret) {
+			s = strings.Replace(s, secret, Redacted, -1)
+		}
+	}
+	return s
+}
+
+type byLenDesc []string
+
+func (s byLenDesc) Len() int           { return len(s) }
+func (s byLenDesc) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byLenDesc) Less(i, j int) bool { return len(s[i]) > len(s[j]) }
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/akutz/goof"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSecretConfigKey(t *testing.T) {
+	assert.True(t, IsSecretConfigKey("ebs.secretKey"))
+	assert.True(t, IsSecretConfigKey("libstorage.server.auth.key"))
+	assert.True(t, IsSecretConfigKey("PASSWORD"))
+	assert.False(t, IsSecretConfigKey("ebs.region"))
+	assert.False(t, IsSecretConfigKey("gcepd.keyfile"))
+}
+
+func TestRedactSecrets(t *testing.T) {
+	RegisterSecret("abc")
+	RegisterSecret("s3cr3t")
+	RegisterSecret("s3cr3t-longer")
+
+	err := goof.WithField(
+		"secretKey", "s3cr3t", "error connecting with key s3cr3t-longer")
+	assert.Equal(t,
+		"error connecting with key ******", RedactSecrets(err.Error()))
+	assert.Equal(t, "password is ******", RedactSecrets("password is s3cr3t"))
+
+	// values shorter than four characters are not registered
+	assert.Equal(t, "abc", RedactSecrets("abc"))
+}
+
+func TestRedactSecretsJSON(t *testing.T) {
+	secret := `p&ss<w"rd>\`
+	RegisterSecret(secret)
+
+	buf, _ := json.MarshalIndent(map[string]interface{}{
+		"message": "error connecting with password " + secret,
+		"fields":  map[string]string{"password": secret},
+	}, "", "  ")
+	assert.Contains(t, string(buf), `p\u0026ss\u003cw\"rd\u003e\\`)
+
+	redacted := RedactSecrets(string(buf))
+	assert.NotContains(t, redacted, `\u0026ss`)
+	assert.Contains(t, redacted,
+		`"message": "error connecting with password ******"`)
+	assert.Contains(t, redacted, `"password": "******"`)
+}
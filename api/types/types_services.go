@@ -1,5 +1,7 @@
 package types
 
+import log "github.com/Sirupsen/logrus"
+
 // Service is the base type for services.
 type Service interface {
 	Driver
@@ -39,6 +41,42 @@ type StorageService interface {
 
 	// AuthConfig returns the storage service's authentication configuration.
 	AuthConfig() *AuthConfig
+
+	// InstanceInspect returns the instance identified by the instance ID in
+	// the context. Instances are cached for the duration specified by
+	// libstorage.server.instanceCache.ttl.
+	InstanceInspect(ctx Context, opts Store) (*Instance, error)
+
+	// RefreshInstance inspects the instance identified by the instance ID in
+	// the context without using the cached instance, and then caches the new
+	// result. A caller that suspects the instance's identity has changed,
+	// such as after a volume fails to attach following a live migration, may
+	// use RefreshInstance to discard the stale instance.
+	RefreshInstance(ctx Context, opts Store) (*Instance, error)
+
+	// Retry invokes the idempotent operation with the provided name,
+	// retrying it with exponential backoff if it fails with a temporary
+	// error. The operations that are retried, the number of attempts, and
+	// the delay between them are specified by libstorage.server.retry.
+	// Operations that are not retried are invoked once.
+	Retry(ctx Context, op string, f func() error) error
+
+	// LockVolume acquires the lock that coordinates attaching and detaching
+	// the volume with other hosts and returns a function that releases it.
+	// The lock is acquired immediately unless libstorage.server.locker is
+	// configured. An ErrLockTimeout error is returned if the lock cannot be
+	// acquired before the context is done.
+	LockVolume(ctx Context, volumeID string) (func(), error)
+}
+
+// StorageServiceWithLogLevel is a StorageService that logs at its own level
+// rather than at the server's level.
+type StorageServiceWithLogLevel interface {
+	StorageService
+
+	// LogLevel returns the service's log level. The flag is false if the
+	// service logs at the server's level.
+	LogLevel() (log.Level, bool)
 }
 
 // TaskTrackingService a service for tracking tasks.
@@ -0,0 +1,20 @@
+package types
+
+// Tracer records spans around driver operations so the operations may be
+// observed by a distributed tracing system. A Tracer is an adapter between
+// libStorage and a tracing library, such as OpenTelemetry, and allows
+// libStorage to remain free of a dependency on any one library.
+type Tracer interface {
+
+	// StartSpan starts a span for the named operation. The fields describe
+	// the operation, such as the volume or snapshot ID upon which it acts.
+	// The returned context carries the span and is provided to EndSpan.
+	StartSpan(
+		ctx Context,
+		operation string,
+		fields map[string]interface{}) Context
+
+	// EndSpan ends the span carried by the context. The error is the result
+	// of the operation and is nil if the operation succeeded.
+	EndSpan(ctx Context, err error)
+}
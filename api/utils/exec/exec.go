@@ -0,0 +1,168 @@
+/*
+Package exec runs the commands of drivers that shell out to binaries, such
+as s3fs. Drivers run commands with an Executor so that tests can substitute a
+FakeExecutor and assert the commands that would have been run.
+*/
+package exec
+
+import (
+	"bytes"
+	"io"
+	"os"
+	goexec "os/exec"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// Cmd is a command to run.
+type Cmd struct {
+	// Name is the name or path of the binary.
+	Name string
+
+	// Args are the arguments passed to the binary.
+	Args []string
+
+	// Env is the command's environment. If Env is nil the command inherits
+	// the environment of the current process.
+	Env []string
+
+	// Stdin is the command's standard input. If Stdin is nil the command
+	// reads from the null device.
+	Stdin io.Reader
+}
+
+// Argv returns the command's name followed by its arguments.
+func (c *Cmd) Argv() []string {
+	return append([]string{c.Name}, c.Args...)
+}
+
+// String returns the command line.
+func (c *Cmd) String() string {
+	return strings.Join(c.Argv(), " ")
+}
+
+// Result is the result of a command that was started.
+type Result struct {
+	// Stdout is the data the command wrote to standard output.
+	Stdout []byte
+
+	// Stderr is the data the command wrote to standard error.
+	Stderr []byte
+
+	// ExitCode is the command's exit code. It is -1 if the command was
+	// killed.
+	ExitCode int
+}
+
+// Output returns the data the command wrote to standard output followed by
+// the data it wrote to standard error.
+func (r *Result) Output() []byte {
+	return append(append([]byte{}, r.Stdout...), r.Stderr...)
+}
+
+// Executor runs commands.
+type Executor interface {
+
+	// Run runs the command and waits for it to exit. The command is killed
+	// if the context is cancelled or its deadline passes, in which case the
+	// context's error is returned. An error is also returned if the command
+	// cannot be started or exits with a non-zero code. The result is nil
+	// only if the command could not be started.
+	Run(ctx types.Context, cmd *Cmd) (*Result, error)
+}
+
+// RealExecutor is an Executor that runs commands on the host.
+type RealExecutor struct{}
+
+// Run runs the command on the host.
+func (e RealExecutor) Run(ctx types.Context, cmd *Cmd) (*Result, error) {
+
+	var stdout, stderr bytes.Buffer
+	c := goexec.Command(cmd.Name, cmd.Args...)
+	c.Env = cmd.Env
+	c.Stdin = cmd.Stdin
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	if err := c.Start(); err != nil {
+		return nil, goof.WithFieldE(
+			"cmd", cmd.Name, "error starting command", err)
+	}
+
+	// exec.CommandContext is not available before Go 1.7
+	done := make(chan error, 1)
+	go func() { done <- c.Wait() }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		c.Process.Kill()
+		<-done
+		err = ctx.Err()
+	}
+
+	res := &Result{
+		Stdout:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
+		ExitCode: exitCode(c.ProcessState),
+	}
+	return res, err
+}
+
+func exitCode(ps *os.ProcessState) int {
+	if ps == nil {
+		return -1
+	}
+	if ws, ok := ps.Sys().(syscall.WaitStatus); ok {
+		if ws.Signaled() {
+			return -1
+		}
+		return ws.ExitStatus()
+	}
+	return 0
+}
+
+// FakeExecutor is an Executor that records the commands it is asked to run
+// instead of running them. A FakeExecutor is safe for concurrent use.
+type FakeExecutor struct {
+	sync.Mutex
+
+	// Cmds are the commands the executor was asked to run, in order.
+	Cmds []*Cmd
+
+	// RunFunc, if set, returns the result of each command. Otherwise each
+	// command succeeds without output.
+	RunFunc func(ctx types.Context, cmd *Cmd) (*Result, error)
+}
+
+// Run records the command and returns the result of RunFunc.
+func (e *FakeExecutor) Run(ctx types.Context, cmd *Cmd) (*Result, error) {
+	e.Lock()
+	e.Cmds = append(e.Cmds, cmd)
+	e.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if e.RunFunc != nil {
+		return e.RunFunc(ctx, cmd)
+	}
+	return &Result{}, nil
+}
+
+// Argvs returns the argv of each command the executor was asked to run.
+func (e *FakeExecutor) Argvs() [][]string {
+	e.Lock()
+	defer e.Unlock()
+	argvs := make([][]string, len(e.Cmds))
+	for i, c := range e.Cmds {
+		argvs[i] = c.Argv()
+	}
+	return argvs
+}
@@ -0,0 +1,84 @@
+// +build !windows
+
+package exec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestRealExecutorRun(t *testing.T) {
+	ctx := context.Background()
+	res, err := RealExecutor{}.Run(ctx, &Cmd{
+		Name: "sh",
+		Args: []string{"-c", "echo out; echo err >&2"},
+	})
+	assert.NoError(t, err)
+	if assert.NotNil(t, res) {
+		assert.Equal(t, "out\n", string(res.Stdout))
+		assert.Equal(t, "err\n", string(res.Stderr))
+		assert.Equal(t, 0, res.ExitCode)
+	}
+}
+
+func TestRealExecutorRunExitCode(t *testing.T) {
+	res, err := RealExecutor{}.Run(context.Background(), &Cmd{
+		Name: "sh",
+		Args: []string{"-c", "exit 3"},
+	})
+	assert.Error(t, err)
+	if assert.NotNil(t, res) {
+		assert.Equal(t, 3, res.ExitCode)
+	}
+}
+
+func TestRealExecutorRunTimeout(t *testing.T) {
+	ctx, cancel := context.WithDefaultTimeout(
+		context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	res, err := RealExecutor{}.Run(ctx, &Cmd{
+		Name: "sleep",
+		Args: []string{"10"},
+	})
+	assert.Equal(t, ctx.Err(), err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+	if assert.NotNil(t, res) {
+		assert.Equal(t, -1, res.ExitCode)
+	}
+}
+
+func TestRealExecutorRunNotFound(t *testing.T) {
+	res, err := RealExecutor{}.Run(context.Background(), &Cmd{
+		Name: "/nonexistent/command",
+	})
+	assert.Error(t, err)
+	assert.Nil(t, res)
+}
+
+func TestFakeExecutor(t *testing.T) {
+	e := &FakeExecutor{}
+	ctx := context.Background()
+
+	res, err := e.Run(ctx, &Cmd{Name: "s3fs", Args: []string{"a", "b"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &Result{}, res)
+
+	e.RunFunc = func(ctx types.Context, cmd *Cmd) (*Result, error) {
+		return &Result{Stderr: []byte("s3fs: failed"), ExitCode: 1}, nil
+	}
+	res, err = e.Run(ctx, &Cmd{Name: "s3fs"})
+	assert.NoError(t, err)
+	assert.Equal(t, "s3fs: failed", string(res.Output()))
+
+	assert.Equal(t, [][]string{
+		{"s3fs", "a", "b"},
+		{"s3fs"},
+	}, e.Argvs())
+}
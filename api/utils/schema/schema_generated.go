@@ -41,14 +41,26 @@ const (
                     "type": "string",
                     "description": "The zone for which the volume is available."
                 },
+                "createTime": {
+                    "type": "number",
+                    "description": "The time (epoch) at which the volume was created."
+                },
                 "encrypted": {
                     "type": "boolean",
                     "description": "A flag indicating whether or not the volume is encrypted."
                 },
+                "encryptionKey": {
+                    "type": "string",
+                    "description": "The ID of the key used to encrypt the volume."
+                },
                 "iops": {
                     "type": "number",
                     "description": "The volume IOPs."
                 },
+                "throughput": {
+                    "type": "number",
+                    "description": "The volume's provisioned throughput in MiB/s."
+                },
                 "networkName": {
                     "type": "string",
                     "description": "The name of the network on which the volume resides."
@@ -61,6 +73,7 @@ const (
                     "type": "string",
                     "description": "The volume status."
                 },
+                "tags": { "$ref": "#/definitions/tags" },
                 "fields": { "$ref": "#/definitions/fields" }
             },
             "required": [ "id", "name" ],
@@ -78,6 +91,10 @@ const (
                     "type": "string",
                     "description": "The name of the device on to which the volume is mounted."
                 },
+                "requestedDeviceName": {
+                    "type": "string",
+                    "description": "The name of the device requested when the volume was attached."
+                },
                 "status": {
                     "type": "string",
                     "description": "The status of the attachment."
@@ -90,6 +107,10 @@ const (
                     "type": "string",
                     "description": "The file system path to which the volume is mounted."
                 },
+                "readOnly": {
+                    "type": "boolean",
+                    "description": "A flag indicating whether or not the volume is attached read-only."
+                },
                 "fields": { "$ref": "#/definitions/fields" }
             },
             "required": [ "instanceID", "deviceName", "volumeID" ],
@@ -146,6 +167,10 @@ const (
                     "type": "string",
                     "description": "The region from which the object originates."
                 },
+                "availabilityZone": {
+                    "type": "string",
+                    "description": "The availability zone in which the instance is located."
+                },
                 "fields": { "$ref": "#/definitions/fields" }
             },
             "required": [ "id" ],
@@ -162,6 +187,12 @@ const (
                     "type": "string",
                     "description": "The snapshot's ID."
                 },
+                "progress": {
+                    "type": "number",
+                    "minimum": 0,
+                    "maximum": 100,
+                    "description": "The percentage of the snapshot that is complete."
+                },
                 "name": {
                     "type": "string",
                     "description": "The name of the snapshot."
@@ -178,6 +209,10 @@ const (
                     "type": "number",
                     "description": "The time (epoch) at which the request to create the snapshot was submitted."
                 },
+                "expireTime": {
+                    "type": "number",
+                    "description": "The time (epoch) at which the snapshot expires."
+                },
                 "status": {
                     "type": "string",
                     "description": "The status of the snapshot."
@@ -190,6 +225,14 @@ const (
                     "type": "number",
                     "description": "The size of the volume to which the snapshot belongs."
                 },
+                "size": {
+                    "type": "number",
+                    "description": "The amount of storage, in GB, consumed by the snapshot."
+                },
+                "fullCopy": {
+                    "type": "boolean",
+                    "description": "A flag indicating that an incremental copy was requested but the snapshot was copied in full."
+                },
                 "fields": { "$ref": "#/definitions/fields" }
             },
             "required": [ "id" ],
@@ -265,13 +308,50 @@ const (
                     "type": "string",
                     "description": "Type is the type of storage the driver provides: block, nas, object."
                 },
-                "nextDevice": { "$ref": "#/definitions/nextDeviceInfo" }
+                "nextDevice": { "$ref": "#/definitions/nextDeviceInfo" },
+                "capabilities": { "$ref": "#/definitions/driverCapabilities" }
             },
             "required": [ "name", "type" ],
             "additionalProperties": false
         },
 
 
+        "driverCapabilities": {
+            "type": "object",
+            "properties": {
+                "supportsSnapshots": {
+                    "type": "boolean",
+                    "description": "A flag indicating whether or not the driver can create and manage snapshots."
+                },
+                "supportsResize": {
+                    "type": "boolean",
+                    "description": "A flag indicating whether or not the driver can grow a volume."
+                },
+                "supportsClone": {
+                    "type": "boolean",
+                    "description": "A flag indicating whether or not the driver can create a volume from an existing volume."
+                },
+                "supportsMultiAttach": {
+                    "type": "boolean",
+                    "description": "A flag indicating whether or not a volume may be attached to more than one instance at a time."
+                },
+                "supportsEncryption": {
+                    "type": "boolean",
+                    "description": "A flag indicating whether or not the driver can create encrypted volumes."
+                },
+                "supportsCopySnapshot": {
+                    "type": "boolean",
+                    "description": "A flag indicating whether or not the driver can copy an existing snapshot."
+                },
+                "supportsThroughput": {
+                    "type": "boolean",
+                    "description": "A flag indicating whether or not the driver can provision a volume's throughput independently of its IOPS."
+                }
+            },
+            "additionalProperties": false
+        },
+
+
         "nextDeviceInfo": {
             "type": "object",
             "properties": {
@@ -302,6 +382,16 @@ const (
         },
 
 
+        "tags": {
+            "type": "object",
+            "description": "Tags are the key/value pairs with which a resource is tagged.",
+            "patternProperties": {
+                ".+": { "type": "string" }
+            },
+            "additionalProperties": true
+        },
+
+
         "volumeMap": {
             "type": "object",
             "patternProperties": {
@@ -412,12 +502,19 @@ const (
                 "iops": {
                     "type": "number"
                 },
+                "throughput": {
+                    "type": "number"
+                },
                 "size": {
                     "type": "number"
                 },
                 "type": {
                     "type": "string"
                 },
+                "tags": { "$ref": "#/definitions/tags" },
+                "idempotentByName": {
+                    "type": "boolean"
+                },
                 "opts": { "$ref" : "#/definitions/opts" }
             },
             "required": [ "name" ],
@@ -444,6 +541,9 @@ const (
                 "snapshotName": {
                     "type": "string"
                 },
+                "expireTime": {
+                    "type": "number"
+                },
                 "opts": { "$ref" : "#/definitions/opts" }
             },
             "required": [ "snapshotName" ],
@@ -460,6 +560,12 @@ const (
                 "force": {
                     "type": "boolean"
                 },
+                "multiAttach": {
+                    "type": "boolean"
+                },
+                "readOnly": {
+                    "type": "boolean"
+                },
                 "opts": { "$ref" : "#/definitions/opts" }
             },
             "additionalProperties": false
@@ -498,6 +604,9 @@ const (
                 "destinationID": {
                     "type": "string"
                 },
+                "baseSnapshotID": {
+                    "type": "string"
+                },
                 "opts": { "$ref" : "#/definitions/opts" }
             },
             "required": [ "snapshotName", "destinationID" ],
@@ -49,3 +49,15 @@ func DeviceAttachTimeout(val string) time.Duration {
 	}
 	return dur
 }
+
+// cleanupTimeout is the longest that cleaning up after an operation, such as
+// removing an intermediate snapshot, may take.
+const cleanupTimeout = 30 * time.Second
+
+// cleanupContext returns a context with ctx's values for cleaning up after an
+// operation. The returned context is not canceled when ctx is, so cleanup
+// still happens after the operation is canceled or times out, but it is
+// canceled after cleanupTimeout.
+func cleanupContext(ctx types.Context) (types.Context, func()) {
+	return context.WithDefaultTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+}
@@ -0,0 +1,352 @@
+package utils
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// DefaultDriverCacheSize is the maximum number of results a
+// CachedStorageDriver retains when no size is specified.
+const DefaultDriverCacheSize = 1024
+
+// lruCache is a size-bounded cache whose entries expire after a TTL. When
+// the cache is full the least recently used entry is evicted. Each entry
+// belongs to a group, such as the ID of the volume the entry describes, so
+// that all of the entries for a resource can be invalidated at once.
+//
+// The cache's generation is incremented by every invalidation. A value read
+// from the backend is only stored if the generation has not changed since
+// the read began, so a read that races with a modification cannot cache the
+// value from before the modification.
+type lruCache struct {
+	sync.Mutex
+	ttl     time.Duration
+	size    int
+	gen     uint64
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruCacheEntry struct {
+	key     string
+	group   string
+	value   interface{}
+	expires time.Time
+}
+
+func newLRUCache(ttl time.Duration, size int) *lruCache {
+	if size <= 0 {
+		size = DefaultDriverCacheSize
+	}
+	return &lruCache{
+		ttl:     ttl,
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// get returns the value for the key if it has not expired, otherwise the
+// current generation.
+func (c *lruCache) get(key string) (interface{}, uint64, bool) {
+	c.Lock()
+	defer c.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, c.gen, false
+	}
+	e := el.Value.(*lruCacheEntry)
+	if !time.Now().Before(e.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, c.gen, false
+	}
+	c.order.MoveToFront(el)
+	return e.value, c.gen, true
+}
+
+// set stores the value for the key unless the cache has been invalidated
+// since the generation gen.
+func (c *lruCache) set(key, group string, gen uint64, value interface{}) {
+	c.Lock()
+	defer c.Unlock()
+	if gen != c.gen {
+		return
+	}
+	e := &lruCacheEntry{
+		key:     key,
+		group:   group,
+		value:   value,
+		expires: time.Now().Add(c.ttl),
+	}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(e)
+	for c.order.Len() > c.size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*lruCacheEntry).key)
+	}
+}
+
+func (c *lruCache) invalidate(group string) {
+	c.Lock()
+	defer c.Unlock()
+	c.gen++
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		if e := el.Value.(*lruCacheEntry); e.group == group {
+			c.order.Remove(el)
+			delete(c.entries, e.key)
+		}
+		el = next
+	}
+}
+
+func (c *lruCache) purge() {
+	c.Lock()
+	defer c.Unlock()
+	c.gen++
+	c.order.Init()
+	c.entries = map[string]*list.Element{}
+}
+
+// CachedStorageDriver is a StorageDriver that caches the results of
+// VolumeInspect, SnapshotInspect, and VolumeTypes for a short time.
+// Operations that modify a volume or snapshot invalidate its cached results.
+// Instances are not cached by a CachedStorageDriver since the storage service
+// caches them with an InstanceCache. A CachedStorageDriver is safe for
+// concurrent use.
+//
+// The optional interfaces a driver implements, such as
+// StorageDriverVolResize, are not visible through a CachedStorageDriver.
+// The functions in this package that invoke them, such as VolumeResize, use
+// the underlying driver and invalidate the cached results of the volume or
+// snapshot they modify. Callers that use Driver to access the underlying
+// driver directly should call InvalidateVolume or InvalidateSnapshot.
+type CachedStorageDriver struct {
+	types.StorageDriver
+	cache *lruCache
+}
+
+// NewCachedStorageDriver returns a StorageDriver that caches the read
+// results of the provided driver for the specified TTL. At most size results
+// are retained; a size less than or equal to zero results in
+// DefaultDriverCacheSize. A TTL less than or equal to zero disables caching
+// and the provided driver is returned as is.
+func NewCachedStorageDriver(
+	d types.StorageDriver,
+	ttl time.Duration,
+	size int) types.StorageDriver {
+
+	if ttl <= 0 {
+		return d
+	}
+	return &CachedStorageDriver{
+		StorageDriver: d,
+		cache:         newLRUCache(ttl, size),
+	}
+}
+
+// Driver returns the underlying driver.
+func (d *CachedStorageDriver) Driver() types.StorageDriver {
+	return d.StorageDriver
+}
+
+// InvalidateVolume removes the cached results for the volume.
+func (d *CachedStorageDriver) InvalidateVolume(volumeID string) {
+	d.cache.invalidate("volume=" + volumeID)
+}
+
+// InvalidateSnapshot removes the cached results for the snapshot.
+func (d *CachedStorageDriver) InvalidateSnapshot(snapshotID string) {
+	d.cache.invalidate("snapshot=" + snapshotID)
+}
+
+// Purge removes all of the cached results.
+func (d *CachedStorageDriver) Purge() {
+	d.cache.purge()
+}
+
+// VolumeInspect inspects a single volume. Results are cached separately for
+// each attachment mask and instance ID.
+func (d *CachedStorageDriver) VolumeInspect(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeInspectOpts) (*types.Volume, error) {
+
+	var attachments types.VolumeAttachmentsTypes
+	if opts != nil {
+		attachments = opts.Attachments
+	}
+	iid := ""
+	if v, ok := context.InstanceID(ctx); ok {
+		iid = v.String()
+	}
+	key := fmt.Sprintf("volume=%s;attachments=%d;iid=%s",
+		volumeID, attachments, iid)
+
+	v, gen, ok := d.cache.get(key)
+	if ok {
+		return copyVolume(v.(*types.Volume)), nil
+	}
+
+	vol, err := d.StorageDriver.VolumeInspect(ctx, volumeID, opts)
+	if err != nil || vol == nil {
+		return vol, err
+	}
+	d.cache.set(key, "volume="+volumeID, gen, copyVolume(vol))
+	return vol, nil
+}
+
+// SnapshotInspect inspects a single snapshot.
+func (d *CachedStorageDriver) SnapshotInspect(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	key := "snapshot=" + snapshotID
+	v, gen, ok := d.cache.get(key)
+	if ok {
+		return copySnapshot(v.(*types.Snapshot)), nil
+	}
+
+	snap, err := d.StorageDriver.SnapshotInspect(ctx, snapshotID, opts)
+	if err != nil || snap == nil {
+		return snap, err
+	}
+	d.cache.set(key, key, gen, copySnapshot(snap))
+	return snap, nil
+}
+
+// VolumeTypes returns the types of volumes the underlying driver can create.
+// The types are cached because they rarely change. If the underlying driver
+// does not implement StorageDriverVolTypes then ErrUnsupported is returned.
+func (d *CachedStorageDriver) VolumeTypes(
+	ctx types.Context,
+	opts types.Store) ([]*types.VolumeType, error) {
+
+	key := "volumeTypes"
+	v, gen, ok := d.cache.get(key)
+	if ok {
+		return copyVolumeTypes(v.([]*types.VolumeType)), nil
+	}
+
+	vts, err := VolumeTypes(ctx, d.StorageDriver, opts)
+	if err != nil {
+		return nil, err
+	}
+	d.cache.set(key, key, gen, copyVolumeTypes(vts))
+	return vts, nil
+}
+
+// VolumeRemove removes a volume and invalidates its cached results.
+func (d *CachedStorageDriver) VolumeRemove(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeRemoveOpts) error {
+
+	defer d.InvalidateVolume(volumeID)
+	return d.StorageDriver.VolumeRemove(ctx, volumeID, opts)
+}
+
+// VolumeAttach attaches a volume and invalidates its cached results.
+func (d *CachedStorageDriver) VolumeAttach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeAttachOpts) (*types.Volume, string, error) {
+
+	defer d.InvalidateVolume(volumeID)
+	return d.StorageDriver.VolumeAttach(ctx, volumeID, opts)
+}
+
+// VolumeDetach detaches a volume and invalidates its cached results.
+func (d *CachedStorageDriver) VolumeDetach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeDetachOpts) (*types.Volume, error) {
+
+	defer d.InvalidateVolume(volumeID)
+	return d.StorageDriver.VolumeDetach(ctx, volumeID, opts)
+}
+
+// SnapshotRemove removes a snapshot and invalidates its cached results.
+func (d *CachedStorageDriver) SnapshotRemove(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) error {
+
+	defer d.InvalidateSnapshot(snapshotID)
+	return d.StorageDriver.SnapshotRemove(ctx, snapshotID, opts)
+}
+
+// invalidateVolume removes the cached results for the volume from each
+// CachedStorageDriver that wraps the driver.
+func invalidateVolume(d types.StorageDriver, volumeID string) {
+	eachStorageDriver(d, func(d types.StorageDriver) {
+		if cd, ok := d.(*CachedStorageDriver); ok {
+			cd.InvalidateVolume(volumeID)
+		}
+	})
+}
+
+// invalidateSnapshot removes the cached results for the snapshot from each
+// CachedStorageDriver that wraps the driver.
+func invalidateSnapshot(d types.StorageDriver, snapshotID string) {
+	eachStorageDriver(d, func(d types.StorageDriver) {
+		if cd, ok := d.(*CachedStorageDriver); ok {
+			cd.InvalidateSnapshot(snapshotID)
+		}
+	})
+}
+
+// copyVolume returns a copy of the volume that shares no slices or maps with
+// the original, so a caller may modify a cached volume's attachments, tags,
+// or fields without affecting the cache.
+func copyVolume(v *types.Volume) *types.Volume {
+	c := *v
+	if v.Attachments != nil {
+		c.Attachments = make([]*types.VolumeAttachment, len(v.Attachments))
+		for i, a := range v.Attachments {
+			ac := *a
+			c.Attachments[i] = &ac
+		}
+	}
+	c.Tags = copyStringMap(v.Tags)
+	c.Fields = copyStringMap(v.Fields)
+	return &c
+}
+
+func copySnapshot(s *types.Snapshot) *types.Snapshot {
+	c := *s
+	c.Fields = copyStringMap(s.Fields)
+	return &c
+}
+
+func copyVolumeTypes(vts []*types.VolumeType) []*types.VolumeType {
+	c := make([]*types.VolumeType, len(vts))
+	for i, vt := range vts {
+		vtc := *vt
+		c[i] = &vtc
+	}
+	return c
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// newTestCachedDriver returns a driver with the volume vol-1 and the
+// snapshot snap-1.
+func newTestCachedDriver() *testOptionalDriver {
+	d := &testOptionalDriver{newTestDriver()}
+	d.vols = []*types.Volume{{
+		ID:     "vol-1",
+		Size:   10,
+		Status: "available",
+		Fields: map[string]string{"inspects": "1"},
+	}}
+	d.snaps = []*types.Snapshot{{
+		ID:     "snap-1",
+		Fields: map[string]string{"inspects": "1"},
+	}}
+	return d
+}
+
+func TestCachedStorageDriverDisabled(t *testing.T) {
+	d := newTestCachedDriver()
+	assert.Equal(t, d, NewCachedStorageDriver(d, 0, 0))
+}
+
+func TestCachedStorageDriverVolumeInspect(t *testing.T) {
+	ctx := context.Background()
+	d := newTestCachedDriver()
+	cd := NewCachedStorageDriver(d, time.Minute, 0).(*CachedStorageDriver)
+	assert.Equal(t, d, cd.Driver())
+
+	opts := &types.VolumeInspectOpts{Attachments: types.VolAttReq}
+	v, err := cd.VolumeInspect(ctx, "vol-1", opts)
+	assert.NoError(t, err)
+	v.Fields["inspects"] = "modified"
+
+	v, err = cd.VolumeInspect(ctx, "vol-1", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", v.Fields["inspects"])
+	assert.Equal(t, 1, d.count("VolumeInspect"))
+
+	cd.VolumeInspect(ctx, "vol-1", &types.VolumeInspectOpts{})
+	assert.Equal(t, 2, d.count("VolumeInspect"))
+
+	_, err = cd.VolumeDetach(ctx, "vol-1", &types.VolumeDetachOpts{})
+	assert.NoError(t, err)
+	cd.VolumeInspect(ctx, "vol-1", opts)
+	assert.Equal(t, 3, d.count("VolumeInspect"))
+
+	cd.InvalidateVolume("vol-1")
+	cd.VolumeInspect(ctx, "vol-1", opts)
+	assert.Equal(t, 4, d.count("VolumeInspect"))
+}
+
+func TestCachedStorageDriverSnapshotInspect(t *testing.T) {
+	ctx := context.Background()
+	d := newTestCachedDriver()
+	cd := NewCachedStorageDriver(d, time.Minute, 0)
+
+	s, err := cd.SnapshotInspect(ctx, "snap-1", nil)
+	assert.NoError(t, err)
+	s.Fields["inspects"] = "modified"
+
+	s, err = cd.SnapshotInspect(ctx, "snap-1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", s.Fields["inspects"])
+	s.Fields["inspects"] = "modified"
+	s, _ = cd.SnapshotInspect(ctx, "snap-1", nil)
+	assert.Equal(t, "1", s.Fields["inspects"])
+	assert.Equal(t, 1, d.count("SnapshotInspect"))
+
+	assert.NoError(t, cd.SnapshotRemove(ctx, "snap-1", nil))
+	cd.SnapshotInspect(ctx, "snap-1", nil)
+	assert.Equal(t, 2, d.count("SnapshotInspect"))
+}
+
+func TestCachedStorageDriverExpires(t *testing.T) {
+	ctx := context.Background()
+	d := newTestCachedDriver()
+	cd := NewCachedStorageDriver(d, 10*time.Millisecond, 0)
+
+	cd.SnapshotInspect(ctx, "snap-1", nil)
+	cd.SnapshotInspect(ctx, "snap-1", nil)
+	assert.Equal(t, 1, d.count("SnapshotInspect"))
+
+	time.Sleep(20 * time.Millisecond)
+	cd.SnapshotInspect(ctx, "snap-1", nil)
+	assert.Equal(t, 2, d.count("SnapshotInspect"))
+}
+
+func TestCachedStorageDriverOptional(t *testing.T) {
+	ctx := context.Background()
+	d := newTestCachedDriver()
+	cd := NewCachedStorageDriver(d, time.Minute, 0)
+
+	caps, err := DriverCapabilities(ctx, cd)
+	assert.NoError(t, err)
+	assert.True(t, caps.SupportsResize)
+
+	v, _ := cd.VolumeInspect(ctx, "vol-1", nil)
+	assert.EqualValues(t, 10, v.Size)
+
+	// the resize is dispatched to the underlying driver and discards the
+	// volume's cached results
+	_, err = VolumeResize(ctx, cd, "vol-1", 20, nil)
+	assert.NoError(t, err)
+	v, _ = cd.VolumeInspect(ctx, "vol-1", nil)
+	assert.EqualValues(t, 20, v.Size)
+	assert.Equal(t, 2, d.count("VolumeInspect"))
+
+	assert.NoError(t, CloseDriver(cd))
+	assert.True(t, d.closed)
+}
+
+func TestLRUCache(t *testing.T) {
+	c := newLRUCache(time.Minute, 2)
+
+	_, gen, _ := c.get("a")
+	c.set("a", "1", gen, "a")
+	c.set("b", "2", gen, "b")
+	_, _, ok := c.get("a")
+	assert.True(t, ok)
+
+	// b is the least recently used entry
+	c.set("c", "2", gen, "c")
+	_, _, ok = c.get("b")
+	assert.False(t, ok)
+	_, _, ok = c.get("a")
+	assert.True(t, ok)
+
+	c.invalidate("1")
+	_, _, ok = c.get("a")
+	assert.False(t, ok)
+	_, _, ok = c.get("c")
+	assert.True(t, ok)
+
+	// a value read before an invalidation is not stored
+	c.set("d", "3", gen, "d")
+	_, _, ok = c.get("d")
+	assert.False(t, ok)
+}
@@ -7,6 +7,22 @@ import (
 	gofig "github.com/akutz/gofig/types"
 )
 
+// RequireConfigKeys returns an ErrMissingConfigKeys error that names each of
+// the provided keys that does not have a value in the configuration. A nil
+// error is returned if all of the keys have values.
+func RequireConfigKeys(config gofig.Config, keys ...string) error {
+	var missing []string
+	for _, k := range keys {
+		if strings.TrimSpace(config.GetString(k)) == "" {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		return NewMissingConfigKeysErr(missing)
+	}
+	return nil
+}
+
 func isSet(
 	config gofig.Config,
 	key string,
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	gofigCore "github.com/akutz/gofig"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestRequireConfigKeys(t *testing.T) {
+	config := gofigCore.New()
+	config.Set("test.host", "localhost")
+	config.Set("test.export", "  ")
+
+	assert.NoError(t, RequireConfigKeys(config, "test.host"))
+
+	err := RequireConfigKeys(
+		config, "test.host", "test.export", "test.localPath")
+	if assert.Error(t, err) {
+		assert.IsType(t, &types.ErrMissingConfigKeys{}, err)
+		assert.Equal(t,
+			"missing required config keys: test.export, test.localPath",
+			err.Error())
+	}
+}
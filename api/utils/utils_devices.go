@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// GetNextAvailableDevice returns the path of the first device described by
+// the provided NextDeviceInfo that is not in use, ex. "/dev/xvdf".
+//
+// The info's Pattern must be a bracket expression of single characters and
+// character ranges, such as "[f-p]" or "[0-9]". The candidate device names
+// are the info's Prefix followed by each of the pattern's characters in the
+// order in which they appear.
+//
+// The inUse argument is a list of device names, with or without the "/dev/"
+// prefix. A device name's partitions, ex. "/dev/xvdf1", mark the device
+// "/dev/xvdf" as in use when the candidate names end with a letter.
+func GetNextAvailableDevice(
+	info *types.NextDeviceInfo, inUse []string) (string, error) {
+
+	if info == nil {
+		return "", NewBadNextDeviceInfoErr(
+			"nextDeviceInfo", "", "missing next device info")
+	}
+	if info.Ignore {
+		return "", NewNextDeviceIgnoredErr()
+	}
+
+	chars, err := ParseNextDevicePattern(info.Pattern)
+	if err != nil {
+		return "", err
+	}
+
+	used := map[string]bool{}
+	for _, d := range inUse {
+		used[strings.TrimPrefix(d, "/dev/")] = true
+	}
+
+	for _, c := range chars {
+		name := info.Prefix + string(c)
+		if !isDeviceInUse(name, c, used) {
+			return "/dev/" + name, nil
+		}
+	}
+
+	return "", NewNoAvailableDeviceErr(info.Prefix, info.Pattern)
+}
+
+// ValidateNextDeviceInfo returns an ErrBadNextDeviceInfo error naming the
+// offending field if the provided NextDeviceInfo cannot be used to determine
+// the next available device. A nil NextDeviceInfo or one with its Ignore
+// field set to true is always valid.
+func ValidateNextDeviceInfo(info *types.NextDeviceInfo) error {
+	if info == nil || info.Ignore {
+		return nil
+	}
+	if info.Prefix == "" {
+		return NewBadNextDeviceInfoErr(
+			"prefix", info.Prefix, "prefix required when not ignored")
+	}
+	if info.Pattern == "" {
+		return NewBadNextDeviceInfoErr(
+			"pattern", info.Pattern, "pattern required when not ignored")
+	}
+	_, err := ParseNextDevicePattern(info.Pattern)
+	return err
+}
+
+// ParseNextDevicePattern returns the characters described by a NextDeviceInfo
+// pattern. The pattern must be a bracket expression of single characters and
+// ascending character ranges, ex. "[f-p]" or "[b-df-z]".
+func ParseNextDevicePattern(pattern string) ([]rune, error) {
+	badPattern := func(msg string) error {
+		return NewBadNextDeviceInfoErr("pattern", pattern, msg)
+	}
+
+	if len(pattern) < 3 ||
+		pattern[0] != '[' || pattern[len(pattern)-1] != ']' {
+		return nil, badPattern("pattern must be a bracket expression")
+	}
+
+	var (
+		chars []rune
+		seen  = map[rune]bool{}
+		expr  = []rune(pattern[1 : len(pattern)-1])
+	)
+
+	for i := 0; i < len(expr); i++ {
+		lo, hi := expr[i], expr[i]
+		if i+2 < len(expr) && expr[i+1] == '-' {
+			hi = expr[i+2]
+			i = i + 2
+		}
+		if !isDeviceChar(lo) || !isDeviceChar(hi) {
+			return nil, badPattern("pattern must contain letters or digits")
+		}
+		if hi < lo {
+			return nil, badPattern("pattern contains a descending range")
+		}
+		for c := lo; c <= hi; c++ {
+			if !seen[c] {
+				seen[c] = true
+				chars = append(chars, c)
+			}
+		}
+	}
+
+	return chars, nil
+}
+
+func isDeviceChar(c rune) bool {
+	return (c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+func isDeviceInUse(name string, last rune, used map[string]bool) bool {
+	if used[name] {
+		return true
+	}
+	if last >= '0' && last <= '9' {
+		return false
+	}
+	for d := range used {
+		if !strings.HasPrefix(d, name) {
+			continue
+		}
+		if strings.Trim(d[len(name):], "0123456789") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// SetMountPoints sets the mount point of each volume attachment that has a
+// device name but no mount point, such as the attachment of a block device,
+// from the provided mounts. A mount matches a device if the mount's source
+// is the device's path or, once symbolic links are resolved, the same path,
+// so an EBS volume attached as /dev/xvdf matches its mount of /dev/nvme1n1.
+// Attachments to an instance other than the one with the provided ID are
+// not modified. An empty instance ID matches every attachment.
+func SetMountPoints(
+	vols []*types.Volume,
+	mounts []*types.MountInfo,
+	instanceID string) {
+
+	if len(mounts) == 0 {
+		return
+	}
+
+	mps := map[string]string{}
+	for _, m := range mounts {
+		if _, ok := mps[m.Source]; !ok {
+			mps[m.Source] = m.MountPoint
+		}
+		if p, err := filepath.EvalSymlinks(m.Source); err == nil {
+			if _, ok := mps[p]; !ok {
+				mps[p] = m.MountPoint
+			}
+		}
+	}
+
+	for _, v := range vols {
+		for _, a := range v.Attachments {
+			if a.DeviceName == "" || a.MountPoint != "" {
+				continue
+			}
+			if instanceID != "" && a.InstanceID != nil &&
+				a.InstanceID.ID != instanceID {
+				continue
+			}
+			if mp, ok := mps[a.DeviceName]; ok {
+				a.MountPoint = mp
+			} else if p, err := filepath.EvalSymlinks(a.DeviceName); err == nil {
+				a.MountPoint = mps[p]
+			}
+		}
+	}
+}
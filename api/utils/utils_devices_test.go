@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestGetNextAvailableDevice(t *testing.T) {
+	ebs := &types.NextDeviceInfo{Prefix: "xvd", Pattern: "[f-p]"}
+	tests := []struct {
+		name  string
+		info  *types.NextDeviceInfo
+		inUse []string
+		dev   string
+		err   interface{}
+	}{
+		{"empty", ebs, nil, "/dev/xvdf", nil},
+		{"unrelated", ebs, []string{"/dev/xvda", "/dev/sdf"}, "/dev/xvdf", nil},
+		{"next", ebs, []string{"/dev/xvdf", "/dev/xvdg"}, "/dev/xvdh", nil},
+		{"gap", ebs, []string{"/dev/xvdf", "/dev/xvdh"}, "/dev/xvdg", nil},
+		{"no dev prefix", ebs, []string{"xvdf"}, "/dev/xvdg", nil},
+		{"partition", ebs, []string{"/dev/xvdf1"}, "/dev/xvdg", nil},
+		{"exhausted", ebs, []string{
+			"xvdf", "xvdg", "xvdh", "xvdi", "xvdj", "xvdk",
+			"xvdl", "xvdm", "xvdn", "xvdo", "xvdp"},
+			"", &types.ErrNoAvailableDevice{}},
+		{"multiple ranges",
+			&types.NextDeviceInfo{Prefix: "sd", Pattern: "[b-cf-g]"},
+			[]string{"sdb", "sdc"}, "/dev/sdf", nil},
+		{"digits",
+			&types.NextDeviceInfo{Prefix: "loop", Pattern: "[0-3]"},
+			[]string{"loop0", "loop10"}, "/dev/loop1", nil},
+		{"ignored",
+			&types.NextDeviceInfo{Ignore: true}, nil,
+			"", &types.ErrNextDeviceIgnored{}},
+		{"nil info", nil, nil, "", &types.ErrBadNextDeviceInfo{}},
+		{"bad pattern",
+			&types.NextDeviceInfo{Prefix: "sd", Pattern: `\d+`}, nil,
+			"", &types.ErrBadNextDeviceInfo{}},
+		{"descending range",
+			&types.NextDeviceInfo{Prefix: "sd", Pattern: "[p-f]"}, nil,
+			"", &types.ErrBadNextDeviceInfo{}},
+	}
+
+	for _, tt := range tests {
+		dev, err := GetNextAvailableDevice(tt.info, tt.inUse)
+		if tt.err != nil {
+			assert.IsType(t, tt.err, err, tt.name)
+			continue
+		}
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.dev, dev, tt.name)
+	}
+}
+
+func TestValidateNextDeviceInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		info  *types.NextDeviceInfo
+		field string
+	}{
+		{"nil", nil, ""},
+		{"ignored", &types.NextDeviceInfo{Ignore: true}, ""},
+		{"ignored bad pattern",
+			&types.NextDeviceInfo{Ignore: true, Pattern: `\d+`}, ""},
+		{"valid", &types.NextDeviceInfo{Prefix: "xvd", Pattern: "[f-p]"}, ""},
+		{"missing prefix", &types.NextDeviceInfo{Pattern: "[f-p]"}, "prefix"},
+		{"missing pattern", &types.NextDeviceInfo{Prefix: "xvd"}, "pattern"},
+		{"bad pattern",
+			&types.NextDeviceInfo{Prefix: "xvd", Pattern: "[a-z]+"}, "pattern"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateNextDeviceInfo(tt.info)
+		if tt.field == "" {
+			assert.NoError(t, err, tt.name)
+			continue
+		}
+		if assert.IsType(t, &types.ErrBadNextDeviceInfo{}, err, tt.name) {
+			assert.Contains(t, err.Error(), tt.field, tt.name)
+		}
+	}
+}
+
+func TestSetMountPoints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "libstorage-mounts")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	// xvdg is a link to nvme1n1, as NVMe devices are on Nitro instances
+	nvme := filepath.Join(dir, "nvme1n1")
+	xvdg := filepath.Join(dir, "xvdg")
+	assert.NoError(t, ioutil.WriteFile(nvme, nil, 0644))
+	if err := os.Symlink(nvme, xvdg); err != nil {
+		t.Skipf("error creating symlink: %v", err)
+	}
+
+	iid := &types.InstanceID{ID: "i-000", Driver: "ebs"}
+	other := &types.InstanceID{ID: "i-001", Driver: "ebs"}
+	vols := []*types.Volume{
+		{ID: "vol-000", Attachments: []*types.VolumeAttachment{
+			{DeviceName: "/dev/xvdf", InstanceID: iid}}},
+		{ID: "vol-001", Attachments: []*types.VolumeAttachment{
+			{DeviceName: xvdg, InstanceID: iid}}},
+		{ID: "vol-002", Attachments: []*types.VolumeAttachment{
+			{DeviceName: "/dev/xvdh", InstanceID: iid}}},
+		{ID: "vol-003", Attachments: []*types.VolumeAttachment{
+			{DeviceName: "/dev/xvdi", InstanceID: other}}},
+		{ID: "vol-004", Attachments: []*types.VolumeAttachment{
+			{DeviceName: "bucket", MountPoint: "/mnt/bucket",
+				InstanceID: iid}}},
+		{ID: "vol-005"},
+	}
+	mounts := []*types.MountInfo{
+		{Source: "/dev/xvdf", MountPoint: "/mnt/vol-000"},
+		{Source: nvme, MountPoint: "/mnt/vol-001"},
+		{Source: "/dev/xvdi", MountPoint: "/mnt/vol-003"},
+		{Source: "bucket", MountPoint: "/mnt/other"},
+	}
+
+	SetMountPoints(vols, mounts, "i-000")
+	assert.Equal(t, "/mnt/vol-000", vols[0].MountPoint())
+	assert.Equal(t, "/mnt/vol-001", vols[1].MountPoint())
+	assert.Equal(t, "", vols[2].MountPoint(), "not mounted")
+	assert.Equal(t, "", vols[3].MountPoint(), "other instance")
+	assert.Equal(t, "/mnt/bucket", vols[4].MountPoint(), "already set")
+	assert.Equal(t, "", vols[5].MountPoint())
+}
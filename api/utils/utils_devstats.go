@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// diskStatsSectorSize is the size of the sectors counted by /proc/diskstats,
+// which is always 512 bytes regardless of the device's sector size.
+const diskStatsSectorSize = 512
+
+// LocalDeviceStats returns the I/O counters of the local devices, keyed by
+// the IDs of the volumes to which the devices belong. Every volume in the
+// device map is present in the result, and a device for which no statistics
+// are available has zero counters.
+func LocalDeviceStats(
+	ctx types.Context,
+	ld *types.LocalDevices) (map[string]*types.DeviceStats, error) {
+
+	all, err := readDiskStats()
+	if err != nil {
+		return nil, err
+	}
+	return localDeviceStats(ld, all), nil
+}
+
+func localDeviceStats(
+	ld *types.LocalDevices,
+	all map[string]*types.DeviceStats) map[string]*types.DeviceStats {
+
+	stats := map[string]*types.DeviceStats{}
+	if ld == nil {
+		return stats
+	}
+	for volumeID, device := range ld.DeviceMap {
+		if s, ok := all[resolveDevicePath(device)]; ok {
+			stats[volumeID] = s
+		} else {
+			stats[volumeID] = &types.DeviceStats{}
+		}
+	}
+	return stats
+}
+
+// ParseDiskStats parses the contents of /proc/diskstats and returns the
+// counters of each device, keyed by the device's path, ex. "/dev/xvdf".
+func ParseDiskStats(r io.Reader) (map[string]*types.DeviceStats, error) {
+	stats := map[string]*types.DeviceStats{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// major minor name reads merged sectors ms writes merged sectors ...
+		f := strings.Fields(scanner.Text())
+		if len(f) < 10 {
+			continue
+		}
+		var n [4]uint64
+		for i, j := range []int{3, 5, 7, 9} {
+			v, err := strconv.ParseUint(f[j], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = v
+		}
+		stats[path.Join("/dev", f[2])] = &types.DeviceStats{
+			ReadOps:    n[0],
+			ReadBytes:  n[1] * diskStatsSectorSize,
+			WriteOps:   n[2],
+			WriteBytes: n[3] * diskStatsSectorSize,
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
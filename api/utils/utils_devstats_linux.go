@@ -0,0 +1,33 @@
+// +build linux
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const procDiskStats = "/proc/diskstats"
+
+func readDiskStats() (map[string]*types.DeviceStats, error) {
+	f, err := os.Open(procDiskStats)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	return ParseDiskStats(f)
+}
+
+// resolveDevicePath returns the path of the device to which a symlink, such
+// as "/dev/disk/by-id/...", refers.
+func resolveDevicePath(device string) string {
+	if p, err := filepath.EvalSymlinks(device); err == nil {
+		return p
+	}
+	return device
+}
@@ -0,0 +1,15 @@
+// +build !linux
+
+package utils
+
+import (
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func readDiskStats() (map[string]*types.DeviceStats, error) {
+	return nil, nil
+}
+
+func resolveDevicePath(device string) string {
+	return device
+}
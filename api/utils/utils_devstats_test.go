@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const testDiskStats = `
+ 202       0 xvda 9620 23 433986 6160 4432 2945 187656 10144 0 6932 16300
+ 202      80 xvdf 120 0 2048 40 64 8 1024 28 0 56 68
+   7       0 loop0 0 0 0 0 0 0 0 0 0 0 0
+`
+
+func TestParseDiskStats(t *testing.T) {
+	stats, err := ParseDiskStats(strings.NewReader(testDiskStats))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Len(t, stats, 3)
+	assert.Equal(t, &types.DeviceStats{
+		ReadOps:    120,
+		ReadBytes:  2048 * 512,
+		WriteOps:   64,
+		WriteBytes: 1024 * 512,
+	}, stats["/dev/xvdf"])
+	assert.Equal(t, &types.DeviceStats{}, stats["/dev/loop0"])
+
+	_, err = ParseDiskStats(strings.NewReader(
+		"202 80 xvdf 1 0 x 0 1 0 1 0 0 0 0"))
+	assert.Error(t, err)
+}
+
+func TestLocalDeviceStats(t *testing.T) {
+	all, err := ParseDiskStats(strings.NewReader(testDiskStats))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	stats := localDeviceStats(&types.LocalDevices{
+		Driver: "ebs",
+		DeviceMap: map[string]string{
+			"vol-000": "/dev/xvdf",
+			"vol-001": "/dev/xvdz",
+			"bucket":  "/mnt/bucket",
+		},
+	}, all)
+	assert.Len(t, stats, 3)
+	assert.Equal(t, uint64(120), stats["vol-000"].ReadOps)
+	assert.Equal(t, &types.DeviceStats{}, stats["vol-001"])
+	assert.Equal(t, &types.DeviceStats{}, stats["bucket"])
+
+	assert.Empty(t, localDeviceStats(nil, all))
+}
@@ -0,0 +1,289 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// DriverCapabilities returns the features supported by the provided driver.
+// If the driver does not implement StorageDriverWithCapabilities then the
+// capabilities are inferred from the optional interfaces the driver does
+// implement.
+func DriverCapabilities(
+	ctx types.Context,
+	d types.StorageDriver) (*types.DriverCapabilities, error) {
+
+	if cd, ok := underlying(d).(types.StorageDriverWithCapabilities); ok {
+		return cd.Capabilities(ctx)
+	}
+
+	caps := &types.DriverCapabilities{}
+	if _, ok := underlying(d).(types.StorageDriverVolResize); ok {
+		caps.SupportsResize = true
+	}
+	if _, ok := underlying(d).(types.StorageDriverVolClone); ok {
+		caps.SupportsClone = true
+	}
+	return caps, nil
+}
+
+// Ping verifies the provided driver's backend is reachable. If the driver
+// does not implement StorageDriverWithPing then the driver's volumes are
+// listed instead. Ping returns when the context is done even if the driver
+// has not yet responded.
+func Ping(ctx types.Context, d types.StorageDriver) error {
+
+	errs := make(chan error, 1)
+	go func() {
+		if pd, ok := underlying(d).(types.StorageDriverWithPing); ok {
+			errs <- pd.Ping(ctx)
+			return
+		}
+		_, err := d.Volumes(ctx, &types.VolumesOpts{Opts: NewStore()})
+		errs <- err
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StorageCapacity returns the capacity available to the provided driver. If
+// the driver does not implement StorageDriverWithCapacity then ErrUnsupported
+// is returned.
+func StorageCapacity(
+	ctx types.Context,
+	d types.StorageDriver,
+	availabilityZone string,
+	opts types.Store) (*types.StorageCapacity, error) {
+
+	cd, ok := underlying(d).(types.StorageDriverWithCapacity)
+	if !ok {
+		return nil, NewUnsupportedErr(d.Name(), "storage capacity")
+	}
+	if opts == nil {
+		opts = NewStore()
+	}
+	return cd.StorageCapacity(ctx, availabilityZone, opts)
+}
+
+// VolumeTypes returns the types of volumes the provided driver can create.
+// If the driver does not implement StorageDriverVolTypes then
+// ErrUnsupported is returned.
+func VolumeTypes(
+	ctx types.Context,
+	d types.StorageDriver,
+	opts types.Store) ([]*types.VolumeType, error) {
+
+	// a CachedStorageDriver implements VolumeTypes in order to cache them
+	td, ok := d.(types.StorageDriverVolTypes)
+	if !ok {
+		td, ok = underlying(d).(types.StorageDriverVolTypes)
+	}
+	if !ok {
+		return nil, NewUnsupportedErr(d.Name(), "volume types")
+	}
+	if opts == nil {
+		opts = NewStore()
+	}
+	return td.VolumeTypes(ctx, opts)
+}
+
+// MaxVolumeCount returns the maximum number of volumes that may be attached
+// to the instance identified by the context's instance ID. If the driver
+// does not implement StorageDriverWithMaxVolumes then ErrUnsupported is
+// returned.
+func MaxVolumeCount(
+	ctx types.Context,
+	d types.StorageDriver,
+	opts types.Store) (int, error) {
+
+	md, ok := underlying(d).(types.StorageDriverWithMaxVolumes)
+	if !ok {
+		return 0, NewUnsupportedErr(d.Name(), "max volume count")
+	}
+	if opts == nil {
+		opts = NewStore()
+	}
+	return md.MaxVolumeCount(ctx, opts)
+}
+
+// CheckAttachLimit returns an ErrLimitExceeded error if the instance
+// identified by the context's instance ID has the maximum number of volumes
+// attached. The limit is not checked if the volume is already attached to
+// the instance or if the driver does not implement
+// StorageDriverWithMaxVolumes. An ErrMissingInstanceID error is returned if
+// the context has no instance ID.
+//
+// The attached volumes are counted before the volume is attached, so two
+// volumes attached to the same instance at once may both pass the check and
+// exceed the limit. A caller that attaches volumes concurrently must
+// serialize the check and the attachment for each instance; a libStorage
+// server does so since it runs a service's operations one at a time. The
+// storage platform still rejects an attachment beyond its own limit.
+func CheckAttachLimit(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID string) error {
+
+	if _, ok := underlying(d).(types.StorageDriverWithMaxVolumes); !ok {
+		return nil
+	}
+	iid, ok := context.InstanceID(ctx)
+	if !ok || iid == nil || iid.ID == "" {
+		return NewMissingInstanceIDError(d.Name())
+	}
+	max, err := MaxVolumeCount(ctx, d, nil)
+	if err != nil {
+		return err
+	}
+	atts, err := InstanceAttachments(ctx, d, "")
+	if err != nil {
+		return err
+	}
+	for _, a := range atts {
+		if a.VolumeID == volumeID {
+			return nil
+		}
+	}
+	if len(atts) >= max {
+		return NewLimitExceededErr(iid.ID, max)
+	}
+	return nil
+}
+
+// ValidateVolumeType verifies the type, size, and IOPS requested in the
+// create options are offered by the provided driver. An
+// ErrInvalidVolumeType error is returned if they are not. The options are
+// not validated if no type is requested or if the driver does not implement
+// StorageDriverVolTypes.
+func ValidateVolumeType(
+	ctx types.Context,
+	d types.StorageDriver,
+	opts *types.VolumeCreateOpts) error {
+
+	if opts == nil || opts.Type == nil || *opts.Type == "" {
+		return nil
+	}
+	vts, err := VolumeTypes(ctx, d, opts.Opts)
+	if err != nil {
+		if _, ok := err.(*types.ErrUnsupported); ok {
+			return nil
+		}
+		return err
+	}
+
+	var vt *types.VolumeType
+	for _, v := range vts {
+		if v.Name == *opts.Type {
+			vt = v
+			break
+		}
+	}
+	if vt == nil {
+		names := make([]string, len(vts))
+		for i, v := range vts {
+			names[i] = v.Name
+		}
+		return NewInvalidVolumeTypeErr(goof.Fields{
+			"type":       *opts.Type,
+			"validTypes": names,
+		}, "unknown volume type")
+	}
+
+	if opts.Size != nil && *opts.Size > 0 {
+		if err := checkVolumeTypeRange(
+			vt, "size", "Size", *opts.Size, vt.MinSize, vt.MaxSize); err != nil {
+			return err
+		}
+	}
+
+	if opts.IOPS != nil && *opts.IOPS > 0 {
+		if !vt.SupportsIOPS {
+			return NewInvalidVolumeTypeErr(goof.Fields{
+				"type":       vt.Name,
+				"iops":       *opts.IOPS,
+				"constraint": "supportsIOPS",
+			}, "volume type does not support provisioned iops")
+		}
+		if err := checkVolumeTypeRange(
+			vt, "iops", "IOPS", *opts.IOPS, vt.MinIOPS, vt.MaxIOPS); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkVolumeTypeRange returns an ErrInvalidVolumeType error if the value of
+// the property is less than min or greater than max. The error's constraint
+// field names the violated VolumeType field, such as minSize or maxIOPS. A
+// zero min or max is not checked.
+func checkVolumeTypeRange(
+	vt *types.VolumeType,
+	property, constraintSuffix string,
+	value, min, max int64) error {
+
+	var (
+		constraint string
+		limit      int64
+		msg        string
+	)
+	switch {
+	case min > 0 && value < min:
+		constraint, limit = "min", min
+		msg = "volume %s less than minimum for type"
+	case max > 0 && value > max:
+		constraint, limit = "max", max
+		msg = "volume %s greater than maximum for type"
+	default:
+		return nil
+	}
+	return NewInvalidVolumeTypeErr(goof.Fields{
+		"type":       vt.Name,
+		property:     value,
+		"constraint": constraint + constraintSuffix,
+		"limit":      limit,
+	}, fmt.Sprintf(msg, property))
+}
+
+// underlying returns the driver on which the provided driver's optional
+// interfaces are implemented, unwrapping decorators such as
+// LockedStorageDriver.
+func underlying(d types.StorageDriver) types.StorageDriver {
+	return types.UnwrapStorageDriver(d)
+}
+
+// eachStorageDriver invokes f for the driver and for each driver beneath it,
+// outermost first.
+func eachStorageDriver(d types.StorageDriver, f func(types.StorageDriver)) {
+	for d != nil {
+		f(d)
+		m, ok := d.(types.StorageDriverManager)
+		if !ok {
+			return
+		}
+		d = m.Driver()
+	}
+}
+
+// CloseDriver releases the provided driver's resources. If the driver does
+// not implement DriverWithClose then it holds no resources and nil is
+// returned. A storage driver's decorators are unwrapped so that the
+// underlying driver is closed.
+func CloseDriver(d types.Driver) error {
+	if sd, ok := d.(types.StorageDriver); ok {
+		d = underlying(sd)
+	}
+	if cd, ok := d.(types.DriverWithClose); ok {
+		return cd.Close()
+	}
+	return nil
+}
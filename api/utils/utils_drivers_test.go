@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestCloseDriver(t *testing.T) {
+	d := newTestDriver()
+	assert.NoError(t, CloseDriver(&testOptionalDriver{d}))
+	assert.True(t, d.closed)
+
+	assert.NoError(t, CloseDriver(newTestDriver()))
+}
+
+func TestNewClosedErr(t *testing.T) {
+	err := NewClosedErr("s3fs")
+	assert.IsType(t, &types.ErrClosed{}, err)
+	assert.Equal(t, "driver is closed", err.Error())
+}
+
+// newTestVolTypesDriver returns a driver that offers two volume types.
+func newTestVolTypesDriver() *testOptionalDriver {
+	d := &testOptionalDriver{newTestDriver()}
+	d.volTypes = []*types.VolumeType{
+		{Name: "hdd", MinSize: 500, MaxSize: 16384},
+		{Name: "iops", MinSize: 4, MinIOPS: 100, MaxIOPS: 20000,
+			SupportsIOPS: true},
+	}
+	return d
+}
+
+func TestValidateVolumeType(t *testing.T) {
+	ctx := context.Background()
+	d := newTestVolTypesDriver()
+
+	size := func(v int64) *int64 { return &v }
+	vtype := func(v string) *string { return &v }
+
+	assert.NoError(t, ValidateVolumeType(ctx, d, nil))
+	assert.NoError(t, ValidateVolumeType(
+		ctx, d, &types.VolumeCreateOpts{Size: size(1)}))
+	assert.NoError(t, ValidateVolumeType(
+		ctx, d, &types.VolumeCreateOpts{Type: vtype("hdd"), Size: size(500)}))
+	assert.NoError(t, ValidateVolumeType(
+		ctx, d, &types.VolumeCreateOpts{Type: vtype("iops"), IOPS: size(100)}))
+
+	for _, tc := range []struct {
+		opts *types.VolumeCreateOpts
+		msg  string
+	}{
+		{&types.VolumeCreateOpts{Type: vtype("ssd")},
+			"unknown volume type"},
+		{&types.VolumeCreateOpts{Type: vtype("hdd"), Size: size(100)},
+			"volume size less than minimum for type"},
+		{&types.VolumeCreateOpts{Type: vtype("hdd"), Size: size(20000)},
+			"volume size greater than maximum for type"},
+		{&types.VolumeCreateOpts{Type: vtype("hdd"), IOPS: size(100)},
+			"volume type does not support provisioned iops"},
+		{&types.VolumeCreateOpts{Type: vtype("iops"), IOPS: size(50)},
+			"volume iops less than minimum for type"},
+		{&types.VolumeCreateOpts{Type: vtype("iops"), IOPS: size(50000)},
+			"volume iops greater than maximum for type"},
+	} {
+		err := ValidateVolumeType(ctx, d, tc.opts)
+		assert.IsType(t, &types.ErrInvalidVolumeType{}, err)
+		assert.EqualError(t, err, tc.msg)
+	}
+
+	assert.NoError(t, ValidateVolumeType(
+		ctx, newTestDriver(),
+		&types.VolumeCreateOpts{Type: vtype("ssd")}))
+}
+
+func TestVolumeTypesCached(t *testing.T) {
+	ctx := context.Background()
+	d := newTestVolTypesDriver()
+	cd := NewCachedStorageDriver(d, time.Minute, 0)
+
+	vts, err := VolumeTypes(ctx, cd, nil)
+	assert.NoError(t, err)
+	assert.Len(t, vts, 2)
+	vts[0].Name = "modified"
+
+	vts, err = VolumeTypes(ctx, cd, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "hdd", vts[0].Name)
+	assert.Equal(t, 1, d.count("VolumeTypes"))
+
+	_, err = VolumeTypes(
+		ctx, NewCachedStorageDriver(newTestDriver(), time.Minute, 0), nil)
+	assert.IsType(t, &types.ErrUnsupported{}, err)
+}
+
+func TestVolumeCreateValidatesType(t *testing.T) {
+	ctx := context.Background()
+	d := newTestVolTypesDriver()
+
+	vtype := "hdd"
+	size := int64(100)
+	_, err := VolumeCreate(ctx, d, "vol-1", &types.VolumeCreateOpts{
+		Type: &vtype,
+		Size: &size,
+	})
+	assert.IsType(t, &types.ErrInvalidVolumeType{}, err)
+	assert.Equal(t, 0, d.count("VolumeCreate"))
+
+	size = 500
+	_, err = VolumeCreate(ctx, d, "vol-1", &types.VolumeCreateOpts{
+		Type: &vtype,
+		Size: &size,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, d.count("VolumeCreate"))
+}
+
+func TestCheckAttachLimit(t *testing.T) {
+	ctx := context.WithInstanceID(
+		context.Background(), &types.InstanceID{ID: "i-000", Driver: "test"})
+	att := func(instanceID string) []*types.VolumeAttachment {
+		return []*types.VolumeAttachment{{
+			InstanceID: &types.InstanceID{ID: instanceID, Driver: "test"},
+		}}
+	}
+	d := &testOptionalDriver{newTestDriver()}
+	d.maxVols = 2
+	d.vols = []*types.Volume{
+		{ID: "vol-000", Attachments: att("i-000")},
+		{ID: "vol-001", Attachments: att("i-001")},
+		{ID: "vol-002"},
+		{ID: "vol-003"},
+	}
+
+	assert.NoError(t, CheckAttachLimit(ctx, d, "vol-002"))
+
+	d.vols[1].Attachments = att("i-000")
+	err := CheckAttachLimit(ctx, d, "vol-002")
+	if assert.Error(t, err) {
+		assert.IsType(t, &types.ErrLimitExceeded{}, err)
+	}
+
+	// the volume is already attached to the instance
+	assert.NoError(t, CheckAttachLimit(ctx, d, "vol-001"))
+
+	// the context has no instance ID
+	err = CheckAttachLimit(context.Background(), d, "vol-002")
+	assert.IsType(t, &types.ErrMissingInstanceID{}, err)
+	err = CheckAttachLimit(context.WithInstanceID(
+		context.Background(), nil), d, "vol-002")
+	assert.IsType(t, &types.ErrMissingInstanceID{}, err)
+
+	// the driver does not report a limit
+	assert.NoError(t, CheckAttachLimit(ctx, d.testDriver, "vol-002"))
+	_, err = MaxVolumeCount(ctx, d.testDriver, nil)
+	assert.IsType(t, &types.ErrUnsupported{}, err)
+}
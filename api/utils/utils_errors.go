@@ -1,6 +1,11 @@
 package utils
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
 	"github.com/akutz/goof"
 
 	"github.com/codedellemc/libstorage/api/types"
@@ -17,6 +22,38 @@ func NewUnsupportedForClientTypeError(
 		}, "unsupported op for client type")}
 }
 
+// NewUnsupportedErr returns a new ErrUnsupported error.
+func NewUnsupportedErr(driver, feature string) error {
+	return &types.ErrUnsupported{Goof: goof.WithFields(goof.Fields{
+		"driver":  driver,
+		"feature": feature,
+	}, "unsupported by driver")}
+}
+
+// NewVolumeAttachedErr returns a new ErrVolumeAttached error.
+func NewVolumeAttachedErr(volumeID string) error {
+	return &types.ErrVolumeAttached{
+		Goof: goof.WithField(
+			"volumeID", volumeID, "volume must be detached"),
+	}
+}
+
+// NewVolumeNotAttachedErr returns a new ErrVolumeNotAttached error.
+func NewVolumeNotAttachedErr(volumeID string) error {
+	return &types.ErrVolumeNotAttached{
+		Goof: goof.WithField(
+			"volumeID", volumeID, "volume not attached"),
+	}
+}
+
+// NewVolumeAlreadyAttachedErr returns a new ErrVolumeAlreadyAttached error.
+func NewVolumeAlreadyAttachedErr(volumeID string) error {
+	return &types.ErrVolumeAlreadyAttached{
+		Goof: goof.WithField(
+			"volumeID", volumeID, "volume already attached to a host"),
+	}
+}
+
 // NewBadAdminTokenError returns a new ErrBadAdminToken error.
 func NewBadAdminTokenError(token string) error {
 	return &types.ErrBadAdminToken{
@@ -31,6 +68,51 @@ func NewNotFoundError(resourceID string) error {
 	}
 }
 
+// NewVolumeNotFoundErr returns a new ErrVolumeNotFound error. The inner error
+// is the error, if any, returned by the storage platform.
+func NewVolumeNotFoundErr(volumeID string, inner error) error {
+	if inner == nil {
+		return &types.ErrVolumeNotFound{
+			Goof: goof.WithField("volumeID", volumeID, "volume not found"),
+		}
+	}
+	return &types.ErrVolumeNotFound{
+		Goof: goof.WithFieldE("volumeID", volumeID, "volume not found", inner),
+	}
+}
+
+// NewSnapshotNotFoundErr returns a new ErrSnapshotNotFound error. The inner
+// error is the error, if any, returned by the storage platform.
+func NewSnapshotNotFoundErr(snapshotID string, inner error) error {
+	if inner == nil {
+		return &types.ErrSnapshotNotFound{
+			Goof: goof.WithField(
+				"snapshotID", snapshotID, "snapshot not found"),
+		}
+	}
+	return &types.ErrSnapshotNotFound{
+		Goof: goof.WithFieldE(
+			"snapshotID", snapshotID, "snapshot not found", inner),
+	}
+}
+
+// NewAlreadyExistsErr returns a new ErrAlreadyExists error.
+func NewAlreadyExistsErr(name string) error {
+	return &types.ErrAlreadyExists{
+		Goof: goof.WithField("name", name, "resource already exists"),
+	}
+}
+
+// NewConflictErr returns a new ErrConflict error.
+func NewConflictErr(name string, conflicts []string) error {
+	return &types.ErrConflict{
+		Goof: goof.WithFields(goof.Fields{
+			"name":      name,
+			"conflicts": conflicts,
+		}, "resource exists with conflicting properties"),
+	}
+}
+
 // NewMissingInstanceIDError returns a new ErrMissingInstanceID error.
 func NewMissingInstanceIDError(service string) error {
 	return &types.ErrMissingInstanceID{
@@ -45,6 +127,21 @@ func NewMissingLocalDevicesError(service string) error {
 	}
 }
 
+// NewMissingConfigKeysErr returns a new ErrMissingConfigKeys error.
+func NewMissingConfigKeysErr(keys []string) error {
+	return &types.ErrMissingConfigKeys{
+		Goof: goof.WithField("keys", keys, fmt.Sprintf(
+			"missing required config keys: %s", strings.Join(keys, ", "))),
+	}
+}
+
+// NewClosedErr returns a new ErrClosed error.
+func NewClosedErr(driver string) error {
+	return &types.ErrClosed{
+		Goof: goof.WithField("driver", driver, "driver is closed"),
+	}
+}
+
 // NewStoreKeyErr returns a new ErrStoreKey error.
 func NewStoreKeyErr(key string) error {
 	return &types.ErrStoreKey{
@@ -71,3 +168,186 @@ func NewBadFilterErr(filter string, err error) error {
 	return &types.ErrBadFilter{Goof: goof.WithFieldE(
 		"filter", filter, "bad filter", err)}
 }
+
+// NewNoAvailableDeviceErr returns a new ErrNoAvailableDevice error.
+func NewNoAvailableDeviceErr(prefix, pattern string) error {
+	return &types.ErrNoAvailableDevice{Goof: goof.WithFields(goof.Fields{
+		"prefix":  prefix,
+		"pattern": pattern,
+	}, "no available device")}
+}
+
+// NewNextDeviceIgnoredErr returns a new ErrNextDeviceIgnored error.
+func NewNextDeviceIgnoredErr() error {
+	return &types.ErrNextDeviceIgnored{
+		Goof: goof.New("next device info is ignored"),
+	}
+}
+
+// NewBadNextDeviceInfoErr returns a new ErrBadNextDeviceInfo error. The field
+// is the name of the NextDeviceInfo field that is invalid.
+func NewBadNextDeviceInfoErr(field, value, msg string) error {
+	return &types.ErrBadNextDeviceInfo{Goof: goof.WithFields(goof.Fields{
+		"field": field,
+		"value": value,
+	}, msg)}
+}
+
+// NewUnsupportedFSTypeErr returns a new ErrUnsupportedFSType error.
+func NewUnsupportedFSTypeErr(fsType string) error {
+	return &types.ErrUnsupportedFSType{
+		Goof: goof.WithField("fsType", fsType, "unsupported file system type"),
+	}
+}
+
+// NewMountFailedErr returns a new ErrMountFailed error.
+func NewMountFailedErr(devicePath, mountPoint string, err error) error {
+	return &types.ErrMountFailed{Goof: goof.WithFieldsE(goof.Fields{
+		"devicePath": devicePath,
+		"mountPoint": mountPoint,
+	}, "error mounting device", err)}
+}
+
+// NewAlreadyMountedErr returns a new ErrAlreadyMounted error.
+func NewAlreadyMountedErr(devicePath, mountPoint string) error {
+	return &types.ErrAlreadyMounted{Goof: goof.WithFields(goof.Fields{
+		"devicePath": devicePath,
+		"mountPoint": mountPoint,
+	}, "already mounted")}
+}
+
+// NewNotMountedErr returns a new ErrNotMounted error.
+func NewNotMountedErr(mountPoint string) error {
+	return &types.ErrNotMounted{
+		Goof: goof.WithField("mountPoint", mountPoint, "not mounted"),
+	}
+}
+
+// NewInvalidVolumeTypeErr returns a new ErrInvalidVolumeType error.
+func NewInvalidVolumeTypeErr(fields goof.Fields, msg string) error {
+	return &types.ErrInvalidVolumeType{Goof: goof.WithFields(fields, msg)}
+}
+
+// NewLimitExceededErr returns a new ErrLimitExceeded error.
+func NewLimitExceededErr(instanceID string, max int) error {
+	return &types.ErrLimitExceeded{Goof: goof.WithFields(goof.Fields{
+		"instanceID": instanceID,
+		"max":        max,
+	}, "instance has the maximum number of volumes attached")}
+}
+
+// NewInvalidArtifactErr returns a new ErrInvalidArtifact error. The inner
+// error is the error, if any, encountered reading the artifact.
+func NewInvalidArtifactErr(sourceURI, msg string, inner error) error {
+	if inner == nil {
+		return &types.ErrInvalidArtifact{
+			Goof: goof.WithField("sourceURI", sourceURI, msg),
+		}
+	}
+	return &types.ErrInvalidArtifact{
+		Goof: goof.WithFieldE("sourceURI", sourceURI, msg, inner),
+	}
+}
+
+// NewTemporaryErr returns a new ErrTemporary error.
+func NewTemporaryErr(err error) error {
+	return &types.ErrTemporary{Goof: goof.WithError("temporary error", err)}
+}
+
+// NewRateLimitedErr returns a new ErrRateLimited error.
+func NewRateLimitedErr(op string, delay time.Duration) error {
+	return &types.ErrRateLimited{Goof: goof.WithFields(goof.Fields{
+		"operation": op,
+		"delay":     delay,
+	}, "rate limited")}
+}
+
+// NewLockTimeoutErr returns a new ErrLockTimeout error.
+func NewLockTimeoutErr(key string, err error) error {
+	return &types.ErrLockTimeout{
+		Goof: goof.WithFieldE("key", key, "timed out acquiring lock", err),
+	}
+}
+
+// IsTemporaryErr returns a flag indicating whether or not the operation that
+// produced the provided error may succeed if it is retried. Errors with a
+// Temporary function that returns true, such as ErrTemporary, HTTP errors
+// with a 429 or 5xx status, and storage platform errors, such as those from
+// AWS, with a throttling error code or a 429 or 5xx status code are
+// considered temporary. The errors wrapped by the provided error are checked
+// as well.
+func IsTemporaryErr(err error) bool {
+	for err != nil {
+		switch terr := err.(type) {
+		case interface {
+			Temporary() bool
+		}:
+			return terr.Temporary()
+		case interface {
+			Status() int
+		}:
+			return isTemporaryStatus(terr.Status())
+		case interface {
+			StatusCode() int
+		}:
+			if c, ok := err.(interface {
+				Code() string
+			}); ok && throttlingErrCodes[c.Code()] {
+				return true
+			}
+			return isTemporaryStatus(terr.StatusCode())
+		}
+		err = innerErr(err)
+	}
+	return false
+}
+
+// throttlingErrCodes are the error codes with which storage platforms, such
+// as AWS, reject requests because of rate limits.
+var throttlingErrCodes = map[string]bool{
+	"Throttling":               true,
+	"ThrottlingException":      true,
+	"RequestLimitExceeded":     true,
+	"RequestThrottled":         true,
+	"TooManyRequestsException": true,
+	"SlowDown":                 true,
+}
+
+func isTemporaryStatus(status int) bool {
+	return status == http.StatusTooManyRequests ||
+		status >= http.StatusInternalServerError
+}
+
+// innerErr returns the error wrapped by the provided error, if any.
+func innerErr(err error) error {
+	switch terr := err.(type) {
+	case interface {
+		Unwrap() error
+	}:
+		return terr.Unwrap()
+	case interface {
+		Fields() map[string]interface{}
+	}:
+		inner, _ := terr.Fields()["inner"].(error)
+		return inner
+	}
+	return nil
+}
+
+// IsNotFoundErr returns a flag indicating whether or not the provided error
+// indicates a resource could not be found. The ErrNotFound,
+// ErrVolumeNotFound, and ErrSnapshotNotFound errors as well as HTTP errors
+// with a 404 status are considered not found errors.
+func IsNotFoundErr(err error) bool {
+	switch terr := err.(type) {
+	case *types.ErrNotFound,
+		*types.ErrVolumeNotFound,
+		*types.ErrSnapshotNotFound:
+		return true
+	case interface {
+		Status() int
+	}:
+		return terr.Status() == http.StatusNotFound
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/akutz/goof"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTemporaryErr(t *testing.T) {
+	assert.True(t, IsTemporaryErr(NewTemporaryErr(goof.New("throttled"))))
+	assert.True(t, IsTemporaryErr(
+		goof.NewHTTPError(goof.New("throttled"), http.StatusTooManyRequests)))
+	assert.True(t, IsTemporaryErr(
+		goof.NewHTTPError(goof.New("failed"), http.StatusServiceUnavailable)))
+	assert.False(t, IsTemporaryErr(
+		goof.NewHTTPError(goof.New("missing"), http.StatusNotFound)))
+	assert.False(t, IsTemporaryErr(NewVolumeNotFoundErr("vol-000", nil)))
+	assert.False(t, IsTemporaryErr(goof.New("failed")))
+	assert.False(t, IsTemporaryErr(nil))
+
+	// platform errors with status codes, such as AWS request failures
+	assert.True(t, IsTemporaryErr(&testRequestFailure{"InternalError", 500}))
+	assert.True(t, IsTemporaryErr(&testRequestFailure{"Throttling", 400}))
+	assert.False(t, IsTemporaryErr(&testRequestFailure{"InvalidVolume", 400}))
+
+	// wrapped errors
+	assert.True(t, IsTemporaryErr(goof.WithError(
+		"error getting volume", &testRequestFailure{"Unavailable", 503})))
+	assert.True(t, IsTemporaryErr(goof.WithError(
+		"error getting volume", NewTemporaryErr(goof.New("throttled")))))
+	assert.False(t, IsTemporaryErr(goof.WithError(
+		"error getting volume", goof.New("failed"))))
+}
+
+// testRequestFailure is an error with an error code and a status code like
+// the errors returned by the AWS SDK.
+type testRequestFailure struct {
+	code   string
+	status int
+}
+
+func (e *testRequestFailure) Error() string {
+	return e.code
+}
+
+func (e *testRequestFailure) Code() string {
+	return e.code
+}
+
+func (e *testRequestFailure) StatusCode() int {
+	return e.status
+}
+
+func TestIsNotFoundErr(t *testing.T) {
+	assert.True(t, IsNotFoundErr(NewNotFoundError("vol-000")))
+	assert.True(t, IsNotFoundErr(NewVolumeNotFoundErr("vol-000", nil)))
+	assert.True(t, IsNotFoundErr(
+		NewSnapshotNotFoundErr("snap-000", goof.New("missing"))))
+	assert.False(t, IsNotFoundErr(NewAlreadyExistsErr("vol-000")))
+	assert.True(t, IsNotFoundErr(
+		goof.NewHTTPError(goof.New("missing"), http.StatusNotFound)))
+	assert.False(t, IsNotFoundErr(
+		goof.NewHTTPError(goof.New("failed"), http.StatusInternalServerError)))
+	assert.False(t, IsNotFoundErr(goof.New("failed")))
+	assert.False(t, IsNotFoundErr(nil))
+}
+
+func TestErrUnwrap(t *testing.T) {
+	type unwrapper interface {
+		Unwrap() error
+	}
+	inner := goof.New("missing")
+
+	for _, err := range []error{
+		NewVolumeNotFoundErr("vol-000", inner),
+		NewSnapshotNotFoundErr("snap-000", inner),
+		NewMountFailedErr("/dev/xvdf", "/mnt", inner),
+		NewInvalidArtifactErr("file:///snap.tar", "bad artifact", inner),
+		NewTemporaryErr(inner),
+		NewLockTimeoutErr("vol-000", inner),
+	} {
+		if u, ok := err.(unwrapper); assert.True(t, ok, "%T", err) {
+			assert.Equal(t, inner, u.Unwrap(), "%T", err)
+		}
+	}
+
+	assert.Nil(t, NewVolumeNotFoundErr("vol-000", nil).(unwrapper).Unwrap())
+}
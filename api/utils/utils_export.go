@@ -0,0 +1,305 @@
+package utils
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const (
+	// SnapshotArtifactMetadata is the name of the tar entry of a snapshot
+	// artifact that holds the snapshot's JSON document.
+	SnapshotArtifactMetadata = "snapshot.json"
+
+	// SnapshotArtifactData is the name of the tar entry of a snapshot
+	// artifact that holds the snapshot's contents.
+	SnapshotArtifactData = "data"
+)
+
+// ExportWriter writes an exported snapshot to its destination. The data is
+// not guaranteed to be visible at the destination until Close returns nil.
+type ExportWriter interface {
+	io.WriteCloser
+
+	// Abort discards the data written so far. Neither Write nor Close may be
+	// called after Abort.
+	Abort() error
+}
+
+// ExportWriterFunc returns a writer for the destination URL.
+type ExportWriterFunc func(
+	ctx types.Context, destination *url.URL) (ExportWriter, error)
+
+// ImportReaderFunc returns a reader for the source URL.
+type ImportReaderFunc func(
+	ctx types.Context, source *url.URL) (io.ReadCloser, error)
+
+var (
+	exportWritersRWL sync.RWMutex
+	exportWriters    = map[string]ExportWriterFunc{
+		"file": newFileExportWriter,
+	}
+
+	importReadersRWL sync.RWMutex
+	importReaders    = map[string]ImportReaderFunc{
+		"file": newFileImportReader,
+	}
+)
+
+// RegisterExportWriter registers the function that returns writers for
+// destination URIs with the provided scheme. The file scheme is registered
+// by default.
+func RegisterExportWriter(scheme string, f ExportWriterFunc) {
+	exportWritersRWL.Lock()
+	defer exportWritersRWL.Unlock()
+	exportWriters[strings.ToLower(scheme)] = f
+}
+
+// NewExportWriter returns a writer for the destination URI. An
+// ErrUnsupported error is returned if no writer is registered for the URI's
+// scheme.
+func NewExportWriter(
+	ctx types.Context, destinationURI string) (ExportWriter, error) {
+
+	u, err := url.Parse(destinationURI)
+	if err != nil {
+		return nil, goof.WithFieldE(
+			"destinationURI", destinationURI,
+			"invalid export destination", err)
+	}
+
+	exportWritersRWL.RLock()
+	f, ok := exportWriters[strings.ToLower(u.Scheme)]
+	exportWritersRWL.RUnlock()
+	if !ok {
+		return nil, NewUnsupportedErr(
+			"export", "destination scheme "+u.Scheme)
+	}
+	return f(ctx, u)
+}
+
+// SnapshotExport writes the contents of a snapshot to the destination URI.
+// If the driver does not implement StorageDriverSnapExport then
+// ErrUnsupported is returned.
+func SnapshotExport(
+	ctx types.Context,
+	d types.StorageDriver,
+	snapshotID, destinationURI string,
+	opts types.Store) error {
+
+	ed, ok := underlying(d).(types.StorageDriverSnapExport)
+	if !ok {
+		return NewUnsupportedErr(d.Name(), "snapshot export")
+	}
+	if opts == nil {
+		opts = NewStore()
+	}
+	return ed.SnapshotExport(ctx, snapshotID, destinationURI, opts)
+}
+
+// RegisterImportReader registers the function that returns readers for
+// source URIs with the provided scheme. The file scheme is registered by
+// default.
+func RegisterImportReader(scheme string, f ImportReaderFunc) {
+	importReadersRWL.Lock()
+	defer importReadersRWL.Unlock()
+	importReaders[strings.ToLower(scheme)] = f
+}
+
+// NewImportReader returns a reader for the source URI. An ErrUnsupported
+// error is returned if no reader is registered for the URI's scheme.
+func NewImportReader(
+	ctx types.Context, sourceURI string) (io.ReadCloser, error) {
+
+	u, err := url.Parse(sourceURI)
+	if err != nil {
+		return nil, goof.WithFieldE(
+			"sourceURI", sourceURI, "invalid import source", err)
+	}
+
+	importReadersRWL.RLock()
+	f, ok := importReaders[strings.ToLower(u.Scheme)]
+	importReadersRWL.RUnlock()
+	if !ok {
+		return nil, NewUnsupportedErr("import", "source scheme "+u.Scheme)
+	}
+	return f(ctx, u)
+}
+
+// SnapshotImport creates a snapshot from an exported snapshot. If the
+// driver does not implement StorageDriverSnapImport then ErrUnsupported is
+// returned.
+func SnapshotImport(
+	ctx types.Context,
+	d types.StorageDriver,
+	sourceURI, snapshotName string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	id, ok := underlying(d).(types.StorageDriverSnapImport)
+	if !ok {
+		return nil, NewUnsupportedErr(d.Name(), "snapshot import")
+	}
+	if opts == nil {
+		opts = NewStore()
+	}
+	return id.SnapshotImport(ctx, sourceURI, snapshotName, opts)
+}
+
+// WriteSnapshotArtifact writes a snapshot artifact to w. The artifact is a
+// tar stream with the snapshot's JSON document followed by size bytes of
+// the snapshot's contents read from data. Drivers without a native export
+// format use the artifact so that exported snapshots are portable.
+func WriteSnapshotArtifact(
+	w io.Writer,
+	snap *types.Snapshot,
+	size int64,
+	data io.Reader) error {
+
+	buf, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tw := tar.NewWriter(w)
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    SnapshotArtifactMetadata,
+		Mode:    0644,
+		Size:    int64(len(buf)),
+		ModTime: now,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(buf); err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    SnapshotArtifactData,
+		Mode:    0644,
+		Size:    size,
+		ModTime: now,
+	}); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(tw, data, size); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// ReadSnapshotArtifact reads the header of a snapshot artifact written by
+// WriteSnapshotArtifact. It returns the exported snapshot, the size of the
+// snapshot's contents, and a reader of the contents. An ErrInvalidArtifact
+// error is returned if r is not a snapshot artifact. The source URI is used
+// only to describe errors.
+func ReadSnapshotArtifact(
+	r io.Reader,
+	sourceURI string) (*types.Snapshot, int64, io.Reader, error) {
+
+	tr := tar.NewReader(r)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, 0, nil, NewInvalidArtifactErr(
+			sourceURI, "error reading snapshot artifact", err)
+	}
+	if hdr.Name != SnapshotArtifactMetadata {
+		return nil, 0, nil, NewInvalidArtifactErr(
+			sourceURI, "snapshot artifact missing "+SnapshotArtifactMetadata,
+			nil)
+	}
+	snap := &types.Snapshot{}
+	if err := json.NewDecoder(tr).Decode(snap); err != nil {
+		return nil, 0, nil, NewInvalidArtifactErr(
+			sourceURI, "invalid snapshot artifact metadata", err)
+	}
+	if snap.ID == "" {
+		return nil, 0, nil, NewInvalidArtifactErr(
+			sourceURI, "snapshot artifact metadata missing id", nil)
+	}
+
+	hdr, err = tr.Next()
+	if err != nil {
+		return nil, 0, nil, NewInvalidArtifactErr(
+			sourceURI, "error reading snapshot artifact", err)
+	}
+	if hdr.Name != SnapshotArtifactData ||
+		hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+		return nil, 0, nil, NewInvalidArtifactErr(
+			sourceURI, "snapshot artifact missing "+SnapshotArtifactData, nil)
+	}
+
+	return snap, hdr.Size, tr, nil
+}
+
+// fileExportWriter writes to a temporary file beside the destination path
+// and renames the file on Close so that an incomplete export is never
+// mistaken for a complete one.
+type fileExportWriter struct {
+	*os.File
+	path string
+}
+
+func newFileExportWriter(
+	ctx types.Context, destination *url.URL) (ExportWriter, error) {
+
+	if destination.Host != "" && destination.Host != "localhost" {
+		return nil, goof.WithField(
+			"host", destination.Host, "file export must be to the local host")
+	}
+	path := destination.Path
+	if path == "" || strings.HasSuffix(path, "/") {
+		return nil, goof.WithField(
+			"path", path, "file export destination must be a file")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(path + ".partial")
+	if err != nil {
+		return nil, err
+	}
+	return &fileExportWriter{File: f, path: path}, nil
+}
+
+func (w *fileExportWriter) Close() error {
+	if err := w.File.Close(); err != nil {
+		os.Remove(w.File.Name())
+		return err
+	}
+	return os.Rename(w.File.Name(), w.path)
+}
+
+func (w *fileExportWriter) Abort() error {
+	w.File.Close()
+	return os.Remove(w.File.Name())
+}
+
+func newFileImportReader(
+	ctx types.Context, source *url.URL) (io.ReadCloser, error) {
+
+	if source.Host != "" && source.Host != "localhost" {
+		return nil, goof.WithField(
+			"host", source.Host, "file import must be from the local host")
+	}
+	f, err := os.Open(source.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, NewNotFoundError(source.String())
+		}
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestWriteSnapshotArtifact(t *testing.T) {
+	snap := &types.Snapshot{ID: "snap-000", VolumeID: "vol-000", Size: 1}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteSnapshotArtifact(
+		buf, snap, 5, strings.NewReader("hello world")))
+
+	tr := tar.NewReader(buf)
+	hdr, err := tr.Next()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, SnapshotArtifactMetadata, hdr.Name)
+	s := &types.Snapshot{}
+	assert.NoError(t, json.NewDecoder(tr).Decode(s))
+	assert.Equal(t, snap, s)
+
+	hdr, err = tr.Next()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, SnapshotArtifactData, hdr.Name)
+	data, err := ioutil.ReadAll(tr)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	// the data is shorter than the size
+	assert.Error(t, WriteSnapshotArtifact(
+		&bytes.Buffer{}, snap, 5, strings.NewReader("hi")))
+}
+
+func TestReadSnapshotArtifact(t *testing.T) {
+	snap := &types.Snapshot{ID: "snap-000", VolumeID: "vol-000", Size: 1}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteSnapshotArtifact(
+		buf, snap, 5, strings.NewReader("hello")))
+	artifact := buf.Bytes()
+
+	s, size, data, err := ReadSnapshotArtifact(
+		bytes.NewReader(artifact), "file:///snap-000.tar")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, snap, s)
+	assert.EqualValues(t, 5, size)
+	buf2, err := ioutil.ReadAll(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(buf2))
+
+	// a file that is not a tar archive
+	_, _, _, err = ReadSnapshotArtifact(
+		strings.NewReader("hello"), "file:///snap-000.tar")
+	assert.IsType(t, &types.ErrInvalidArtifact{}, err)
+
+	// a tar archive without the snapshot's metadata
+	buf.Reset()
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "data", Mode: 0644, Size: 5})
+	tw.Write([]byte("hello"))
+	tw.Close()
+	_, _, _, err = ReadSnapshotArtifact(buf, "file:///snap-000.tar")
+	assert.IsType(t, &types.ErrInvalidArtifact{}, err)
+
+	// a truncated artifact
+	_, _, _, err = ReadSnapshotArtifact(
+		bytes.NewReader(artifact[:600]), "file:///snap-000.tar")
+	assert.IsType(t, &types.ErrInvalidArtifact{}, err)
+}
+
+func TestNewExportWriterFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "libstorage-export")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+
+	path := filepath.Join(dir, "backups", "snap-000.tar")
+	w, err := NewExportWriter(ctx, "file://"+path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_, err = w.Write([]byte("hello"))
+	assert.NoError(t, err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "visible before close")
+	assert.NoError(t, w.Close())
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	path = filepath.Join(dir, "snap-001.tar")
+	w, err = NewExportWriter(ctx, "file://"+path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	w.Write([]byte("hello"))
+	assert.NoError(t, w.Abort())
+	files, _ := filepath.Glob(filepath.Join(dir, "snap-001*"))
+	assert.Empty(t, files)
+}
+
+func TestNewExportWriterUnsupported(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewExportWriter(ctx, "ftp://host/snap-000.tar")
+	assert.IsType(t, &types.ErrUnsupported{}, err)
+
+	_, err = NewExportWriter(ctx, "file://remote/snap-000.tar")
+	assert.Error(t, err)
+
+	err = SnapshotExport(
+		ctx, newTestDriver(), "snap-000", "file:///tmp/snap.tar", nil)
+	assert.IsType(t, &types.ErrUnsupported{}, err)
+
+	_, err = NewImportReader(ctx, "ftp://host/snap-000.tar")
+	assert.IsType(t, &types.ErrUnsupported{}, err)
+
+	_, err = NewImportReader(ctx, "file:///libstorage/no/such/snap.tar")
+	assert.IsType(t, &types.ErrNotFound{}, err)
+
+	_, err = SnapshotImport(
+		ctx, newTestDriver(), "file:///tmp/snap.tar", "snap-000", nil)
+	assert.IsType(t, &types.ErrUnsupported{}, err)
+}
@@ -0,0 +1,105 @@
+// +build !windows
+
+package utils
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// mkfsArgs are the arguments that force mkfs to create a file system of the
+// corresponding type even if the device already has one.
+var mkfsArgs = map[string][]string{
+	"ext4": {"-F"},
+	"xfs":  {"-f"},
+}
+
+// blkidNotFound is the exit status with which blkid exits if the device has
+// no file system.
+const blkidNotFound = 2
+
+// FormatAndMount formats a device with the file system type and mounts it at
+// the mount point. The device is only formatted if blkid does not detect a
+// file system on it, which prevents a volume that was formatted by an
+// earlier attachment from being erased. If force is true the device is
+// always formatted. If the device already has a file system of a different
+// type then it is mounted with the existing type.
+//
+// An ErrUnsupportedFSType error is returned if the file system type is not
+// ext4 or xfs, and an ErrMountFailed error is returned if the device cannot
+// be mounted.
+func FormatAndMount(
+	ctx types.Context,
+	devicePath, mountPoint, fsType string,
+	force bool) error {
+
+	args, ok := mkfsArgs[fsType]
+	if !ok {
+		return NewUnsupportedFSTypeErr(fsType)
+	}
+
+	existingFSType, err := blkidFSType(devicePath)
+	if err != nil {
+		return err
+	}
+
+	ctx.WithFields(log.Fields{
+		"devicePath":     devicePath,
+		"fsType":         fsType,
+		"existingFSType": existingFSType,
+		"force":          force,
+	}).Debug("probed device file system")
+
+	if force || existingFSType == "" {
+		args = append(args, devicePath)
+		out, err := exec.Command("mkfs."+fsType, args...).CombinedOutput()
+		if err != nil {
+			return goof.WithFieldsE(goof.Fields{
+				"devicePath": devicePath,
+				"fsType":     fsType,
+				"output":     string(bytes.TrimSpace(out)),
+			}, "error creating filesystem", err)
+		}
+	} else {
+		fsType = existingFSType
+	}
+
+	if err := os.MkdirAll(mountPoint, 0755); err != nil {
+		return NewMountFailedErr(devicePath, mountPoint, err)
+	}
+
+	out, err := exec.Command(
+		"mount", "-t", fsType, devicePath, mountPoint).CombinedOutput()
+	if err != nil {
+		return NewMountFailedErr(devicePath, mountPoint, goof.WithFieldE(
+			"output", string(bytes.TrimSpace(out)), "mount failed", err))
+	}
+
+	return nil
+}
+
+// blkidFSType returns the type of the file system on the device. An empty
+// string is returned if the device has no file system.
+func blkidFSType(devicePath string) (string, error) {
+	out, err := exec.Command(
+		"blkid", "-p", "-s", "TYPE", "-o", "value", devicePath).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			ws, ok := exitErr.Sys().(syscall.WaitStatus)
+			if ok && ws.ExitStatus() == blkidNotFound {
+				return "", nil
+			}
+		}
+		return "", goof.WithFieldE(
+			"devicePath", devicePath, "error probing file system", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
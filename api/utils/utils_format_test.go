@@ -0,0 +1,103 @@
+// +build !windows
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// fakeFormatTools writes fake blkid, mkfs, and mount programs to a temporary
+// directory and prepends the directory to the PATH. The device's file system
+// type is stored in the file "fstype" and each mkfs and mount invocation is
+// appended to the file "log" in the same directory.
+func fakeFormatTools(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "formatandmount")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	scripts := map[string]string{
+		"blkid": fmt.Sprintf(
+			"[ -f %[1]s/fstype ] || exit 2\ncat %[1]s/fstype\n", dir),
+		"mount": fmt.Sprintf(
+			"echo mount \"$@\" >> %[1]s/log\n"+
+				"[ \"$4\" != %[1]s/bad ] && "+
+				"[ \"$2\" != %[1]s/unshared ]\n", dir),
+	}
+	for _, fsType := range []string{"ext4", "xfs"} {
+		scripts["mkfs."+fsType] = fmt.Sprintf(
+			"echo mkfs.%[2]s \"$@\" >> %[1]s/log\n"+
+				"echo %[2]s > %[1]s/fstype\n", dir, fsType)
+	}
+	for name, script := range scripts {
+		if !assert.NoError(t, ioutil.WriteFile(
+			path.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755)) {
+			t.FailNow()
+		}
+	}
+
+	oldPath := os.Getenv("PATH")
+	os.Setenv("PATH", dir+":"+oldPath)
+	return dir, func() {
+		os.Setenv("PATH", oldPath)
+		os.RemoveAll(dir)
+	}
+}
+
+func readFormatLog(t *testing.T, dir string) []string {
+	buf, _ := ioutil.ReadFile(path.Join(dir, "log"))
+	os.Remove(path.Join(dir, "log"))
+	return strings.Split(strings.TrimSpace(string(buf)), "\n")
+}
+
+func TestFormatAndMount(t *testing.T) {
+	dir, cleanup := fakeFormatTools(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	dev := "/dev/xvdf"
+	mnt := path.Join(dir, "mnt")
+
+	// the device has no file system and is formatted
+	assert.NoError(t, FormatAndMount(ctx, dev, mnt, "ext4", false))
+	assert.Equal(t, []string{
+		"mkfs.ext4 -F /dev/xvdf",
+		"mount -t ext4 /dev/xvdf " + mnt,
+	}, readFormatLog(t, dir))
+
+	// the device has a file system and is not formatted again
+	assert.NoError(t, FormatAndMount(ctx, dev, mnt, "xfs", false))
+	assert.Equal(t, []string{
+		"mount -t ext4 /dev/xvdf " + mnt,
+	}, readFormatLog(t, dir))
+
+	// the device is formatted anyway
+	assert.NoError(t, FormatAndMount(ctx, dev, mnt, "xfs", true))
+	assert.Equal(t, []string{
+		"mkfs.xfs -f /dev/xvdf",
+		"mount -t xfs /dev/xvdf " + mnt,
+	}, readFormatLog(t, dir))
+}
+
+func TestFormatAndMountErrors(t *testing.T) {
+	dir, cleanup := fakeFormatTools(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := FormatAndMount(ctx, "/dev/xvdf", path.Join(dir, "mnt"), "fat", false)
+	assert.IsType(t, &types.ErrUnsupportedFSType{}, err)
+
+	err = FormatAndMount(ctx, "/dev/xvdf", path.Join(dir, "bad"), "ext4", false)
+	assert.IsType(t, &types.ErrMountFailed{}, err)
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// DefaultInstanceCacheTTL is the length of time an instance is cached when
+// no TTL is configured.
+const DefaultInstanceCacheTTL = 5 * time.Minute
+
+// InstanceCacheTTL gets the configured instance cache TTL. An empty or
+// invalid value results in DefaultInstanceCacheTTL.
+func InstanceCacheTTL(val string) time.Duration {
+	if val == "" {
+		return DefaultInstanceCacheTTL
+	}
+	dur, err := time.ParseDuration(val)
+	if err != nil {
+		return DefaultInstanceCacheTTL
+	}
+	return dur
+}
+
+// InstanceCache memoizes instances by their instance IDs. An instance's
+// identity rarely changes during the lifetime of a process, so caching
+// spares the storage platform's metadata service the same request on every
+// call. An InstanceCache is safe for concurrent use.
+type InstanceCache struct {
+	sync.RWMutex
+	ttl     time.Duration
+	entries map[string]*instanceCacheEntry
+}
+
+type instanceCacheEntry struct {
+	instance *types.Instance
+	expires  time.Time
+}
+
+// NewInstanceCache returns a new instance cache. A TTL less than or equal to
+// zero disables caching.
+func NewInstanceCache(ttl time.Duration) *InstanceCache {
+	return &InstanceCache{
+		ttl:     ttl,
+		entries: map[string]*instanceCacheEntry{},
+	}
+}
+
+// Get returns the cached instance for the key if it has not expired.
+// Otherwise the instance is retrieved with the provided function and, if
+// there is no error, cached. The returned instance is a copy and may be
+// modified by the caller.
+func (c *InstanceCache) Get(
+	key string,
+	f func() (*types.Instance, error)) (*types.Instance, error) {
+
+	if c == nil || c.ttl <= 0 {
+		return f()
+	}
+
+	c.RLock()
+	e, ok := c.entries[key]
+	c.RUnlock()
+	if ok && time.Now().Before(e.expires) {
+		i := *e.instance
+		return &i, nil
+	}
+
+	instance, err := f()
+	if err != nil || instance == nil {
+		return instance, err
+	}
+
+	i := *instance
+	c.Lock()
+	c.entries[key] = &instanceCacheEntry{
+		instance: &i,
+		expires:  time.Now().Add(c.ttl),
+	}
+	c.Unlock()
+
+	return instance, nil
+}
+
+// Refresh retrieves the instance for the key with the provided function
+// without consulting the cache and, if there is no error, replaces the
+// cached instance. If there is an error then the cached instance is removed
+// so that it is not used again. The returned instance is a copy and may be
+// modified by the caller.
+func (c *InstanceCache) Refresh(
+	key string,
+	f func() (*types.Instance, error)) (*types.Instance, error) {
+
+	if c == nil || c.ttl <= 0 {
+		return f()
+	}
+
+	instance, err := f()
+	if err != nil || instance == nil {
+		c.Lock()
+		delete(c.entries, key)
+		c.Unlock()
+		return instance, err
+	}
+
+	i := *instance
+	c.Lock()
+	c.entries[key] = &instanceCacheEntry{
+		instance: &i,
+		expires:  time.Now().Add(c.ttl),
+	}
+	c.Unlock()
+
+	return instance, nil
+}
+
+// Purge removes all of the cached instances.
+func (c *InstanceCache) Purge() {
+	c.Lock()
+	c.entries = map[string]*instanceCacheEntry{}
+	c.Unlock()
+}
+
+// ResolveAvailabilityZone returns the requested availability zone if it is
+// not empty. Otherwise the availability zone of the instance identified by
+// the context's instance ID is returned so that a new volume is created in
+// the same zone as the instance to which it will be attached. An empty
+// string is returned if the driver does not report the instance's
+// availability zone.
+func ResolveAvailabilityZone(
+	ctx types.Context,
+	d types.StorageDriver,
+	requested string) (string, error) {
+
+	if requested != "" {
+		return requested, nil
+	}
+	if _, ok := context.InstanceID(ctx); !ok {
+		return "", NewMissingInstanceIDError(d.Name())
+	}
+	instance, err := d.InstanceInspect(ctx, NewStore())
+	if err != nil {
+		return "", err
+	}
+	if instance == nil {
+		return "", nil
+	}
+	return instance.AvailabilityZone, nil
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akutz/goof"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestInstanceCacheTTL(t *testing.T) {
+	assert.Equal(t, DefaultInstanceCacheTTL, InstanceCacheTTL(""))
+	assert.Equal(t, DefaultInstanceCacheTTL, InstanceCacheTTL("invalid"))
+	assert.Equal(t, time.Duration(0), InstanceCacheTTL("0"))
+	assert.Equal(t, 30*time.Second, InstanceCacheTTL("30s"))
+}
+
+func TestInstanceCache(t *testing.T) {
+	var (
+		calls int
+		mu    sync.Mutex
+	)
+	inspect := func() (*types.Instance, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return &types.Instance{Name: "node1"}, nil
+	}
+
+	c := NewInstanceCache(time.Minute)
+	var wg sync.WaitGroup
+	for x := 0; x < 10; x++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			i, err := c.Get("vfs=iid-000", inspect)
+			assert.NoError(t, err)
+			assert.Equal(t, "node1", i.Name)
+		}()
+	}
+	wg.Wait()
+
+	i, err := c.Get("vfs=iid-000", inspect)
+	assert.NoError(t, err)
+	i.Name = "modified"
+	callsAfterWarm := calls
+
+	i, err = c.Get("vfs=iid-000", inspect)
+	assert.NoError(t, err)
+	assert.Equal(t, "node1", i.Name)
+	assert.Equal(t, callsAfterWarm, calls)
+
+	_, err = c.Get("vfs=iid-001", inspect)
+	assert.NoError(t, err)
+	assert.Equal(t, callsAfterWarm+1, calls)
+
+	c.Purge()
+	_, err = c.Get("vfs=iid-000", inspect)
+	assert.NoError(t, err)
+	assert.Equal(t, callsAfterWarm+2, calls)
+}
+
+func TestInstanceCacheExpires(t *testing.T) {
+	calls := 0
+	inspect := func() (*types.Instance, error) {
+		calls++
+		return &types.Instance{}, nil
+	}
+
+	c := NewInstanceCache(10 * time.Millisecond)
+	c.Get("key", inspect)
+	c.Get("key", inspect)
+	assert.Equal(t, 1, calls)
+
+	time.Sleep(20 * time.Millisecond)
+	c.Get("key", inspect)
+	assert.Equal(t, 2, calls)
+}
+
+func TestInstanceCacheDisabled(t *testing.T) {
+	calls := 0
+	inspect := func() (*types.Instance, error) {
+		calls++
+		return &types.Instance{}, nil
+	}
+
+	c := NewInstanceCache(0)
+	c.Get("key", inspect)
+	c.Get("key", inspect)
+	assert.Equal(t, 2, calls)
+}
+
+func TestInstanceCacheError(t *testing.T) {
+	calls := 0
+	inspect := func() (*types.Instance, error) {
+		calls++
+		return nil, goof.New("metadata service unavailable")
+	}
+
+	c := NewInstanceCache(time.Minute)
+	_, err := c.Get("key", inspect)
+	assert.Error(t, err)
+	_, err = c.Get("key", inspect)
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestInstanceCacheRefresh(t *testing.T) {
+	var (
+		calls int
+		fail  bool
+	)
+	inspect := func() (*types.Instance, error) {
+		calls++
+		if fail {
+			return nil, goof.New("metadata service unavailable")
+		}
+		return &types.Instance{Name: fmt.Sprintf("node%d", calls)}, nil
+	}
+
+	c := NewInstanceCache(time.Minute)
+	i, _ := c.Get("key", inspect)
+	assert.Equal(t, "node1", i.Name)
+	i, _ = c.Get("key", inspect)
+	assert.Equal(t, "node1", i.Name)
+
+	// the cached instance is bypassed and replaced
+	i, err := c.Refresh("key", inspect)
+	assert.NoError(t, err)
+	assert.Equal(t, "node2", i.Name)
+	i, _ = c.Get("key", inspect)
+	assert.Equal(t, "node2", i.Name)
+	assert.Equal(t, 2, calls)
+
+	// a failed refresh removes the stale instance
+	fail = true
+	_, err = c.Refresh("key", inspect)
+	assert.Error(t, err)
+	fail = false
+	i, _ = c.Get("key", inspect)
+	assert.Equal(t, "node4", i.Name)
+
+	c = NewInstanceCache(0)
+	c.Refresh("key", inspect)
+	c.Refresh("key", inspect)
+	assert.Equal(t, 6, calls)
+}
+
+func TestResolveAvailabilityZone(t *testing.T) {
+	d := newTestDriver()
+	d.instance.AvailabilityZone = "us-east-1a"
+
+	az, err := ResolveAvailabilityZone(
+		context.Background(), d, "us-east-1b")
+	assert.NoError(t, err)
+	assert.Equal(t, "us-east-1b", az)
+
+	_, err = ResolveAvailabilityZone(context.Background(), d, "")
+	assert.IsType(t, &types.ErrMissingInstanceID{}, err)
+
+	ctx := context.WithInstanceID(
+		context.Background(), &types.InstanceID{ID: "i-1", Driver: "test"})
+	az, err = ResolveAvailabilityZone(ctx, d, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "us-east-1a", az)
+}
@@ -0,0 +1,273 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// DefaultLockTTL is the TTL of a distributed volume lock if
+// libstorage.server.locker.ttl is not set.
+const DefaultLockTTL = 5 * time.Minute
+
+// VolumeLocks is a set of locks keyed by volume ID. The locks are local to
+// the process; they do not prevent another process or host from operating
+// on the same volume. The zero value is ready to use, and a VolumeLocks is
+// safe for concurrent use.
+type VolumeLocks struct {
+	sync.Mutex
+	locks map[string]*volumeLock
+}
+
+type volumeLock struct {
+	c    chan struct{}
+	refs int
+}
+
+// Lock acquires the lock for the volume, waiting until it is available or
+// the context is done. The returned function releases the lock. It is safe
+// to call more than once, so callers should defer it immediately in order to
+// release the lock when the operation fails or panics.
+func (l *VolumeLocks) Lock(
+	ctx types.Context,
+	volumeID string) (func(), error) {
+
+	l.Mutex.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*volumeLock{}
+	}
+	vl, ok := l.locks[volumeID]
+	if !ok {
+		vl = &volumeLock{c: make(chan struct{}, 1)}
+		l.locks[volumeID] = vl
+	}
+	vl.refs++
+	l.Mutex.Unlock()
+
+	select {
+	case vl.c <- struct{}{}:
+	case <-ctx.Done():
+		l.release(volumeID, vl)
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-vl.c
+			l.release(volumeID, vl)
+		})
+	}, nil
+}
+
+// release removes the volume's lock once nothing holds or waits on it.
+func (l *VolumeLocks) release(volumeID string, vl *volumeLock) {
+	l.Mutex.Lock()
+	defer l.Mutex.Unlock()
+	if vl.refs--; vl.refs == 0 {
+		delete(l.locks, volumeID)
+	}
+}
+
+// LockedStorageDriver is a StorageDriver that serializes the operations that
+// mutate a volume, such as VolumeAttach and VolumeRemove, on a per-volume
+// basis. Operations on different volumes, as well as reads such as
+// VolumeInspect, are not serialized. Acquiring a volume's lock honors the
+// operation's context.
+//
+// The locks are local to the LockedStorageDriver; they do not prevent other
+// processes, or other drivers in the same process, from operating on the
+// volume at the same time.
+//
+// The optional interfaces a driver implements are not visible through a
+// LockedStorageDriver. The functions in this package that invoke those that
+// modify a volume, such as VolumeResize, acquire the volume's lock before
+// using the underlying driver.
+type LockedStorageDriver struct {
+	types.StorageDriver
+	locks VolumeLocks
+}
+
+// NewLockedStorageDriver returns a StorageDriver that serializes the
+// operations that mutate the same volume.
+func NewLockedStorageDriver(d types.StorageDriver) types.StorageDriver {
+	return &LockedStorageDriver{StorageDriver: d}
+}
+
+// Driver returns the underlying driver.
+func (d *LockedStorageDriver) Driver() types.StorageDriver {
+	return d.StorageDriver
+}
+
+// VolumeCopy copies an existing volume.
+func (d *LockedStorageDriver) VolumeCopy(
+	ctx types.Context,
+	volumeID, volumeName string,
+	opts types.Store) (*types.Volume, error) {
+
+	unlock, err := d.locks.Lock(ctx, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return d.StorageDriver.VolumeCopy(ctx, volumeID, volumeName, opts)
+}
+
+// VolumeSnapshot snapshots a volume.
+func (d *LockedStorageDriver) VolumeSnapshot(
+	ctx types.Context,
+	volumeID, snapshotName string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	unlock, err := d.locks.Lock(ctx, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return d.StorageDriver.VolumeSnapshot(ctx, volumeID, snapshotName, opts)
+}
+
+// VolumeRemove removes a volume.
+func (d *LockedStorageDriver) VolumeRemove(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeRemoveOpts) error {
+
+	unlock, err := d.locks.Lock(ctx, volumeID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return d.StorageDriver.VolumeRemove(ctx, volumeID, opts)
+}
+
+// VolumeAttach attaches a volume.
+func (d *LockedStorageDriver) VolumeAttach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeAttachOpts) (*types.Volume, string, error) {
+
+	unlock, err := d.locks.Lock(ctx, volumeID)
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+	return d.StorageDriver.VolumeAttach(ctx, volumeID, opts)
+}
+
+// VolumeDetach detaches a volume.
+func (d *LockedStorageDriver) VolumeDetach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeDetachOpts) (*types.Volume, error) {
+
+	unlock, err := d.locks.Lock(ctx, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return d.StorageDriver.VolumeDetach(ctx, volumeID, opts)
+}
+
+// lockVolume acquires the volume's lock from each LockedStorageDriver that
+// wraps the driver. The returned function releases the locks.
+func lockVolume(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID string) (func(), error) {
+
+	var (
+		err     error
+		unlocks []func()
+	)
+	unlockAll := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+	eachStorageDriver(d, func(d types.StorageDriver) {
+		ld, ok := d.(*LockedStorageDriver)
+		if !ok || err != nil {
+			return
+		}
+		var unlock func()
+		if unlock, err = ld.locks.Lock(ctx, volumeID); err == nil {
+			unlocks = append(unlocks, unlock)
+		}
+	})
+	if err != nil {
+		unlockAll()
+		return nil, err
+	}
+	return unlockAll, nil
+}
+
+// NoopLocker is a Locker that acquires every lock immediately. It is used
+// when a distributed locker is not configured.
+type NoopLocker struct{}
+
+// Name returns the name of the locker.
+func (l NoopLocker) Name() string {
+	return "noop"
+}
+
+// Init initializes the locker.
+func (l NoopLocker) Init(ctx types.Context, config gofig.Config) error {
+	return nil
+}
+
+// Acquire acquires the lock.
+func (l NoopLocker) Acquire(
+	ctx types.Context, key string, ttl time.Duration) error {
+	return nil
+}
+
+// Release releases the lock.
+func (l NoopLocker) Release(ctx types.Context, key string) error {
+	return nil
+}
+
+// VolumeLockKey returns the key of the distributed lock for a volume. The
+// key includes the name of the driver so that services on different hosts
+// that use the same driver share the lock.
+func VolumeLockKey(driverName, volumeID string) string {
+	return fmt.Sprintf("libstorage/%s/volumes/%s", driverName, volumeID)
+}
+
+// AcquireVolumeLock acquires the volume's lock from the locker. An
+// ErrLockTimeout error is returned if the lock cannot be acquired before the
+// context is done. The returned function releases the lock; an error
+// releasing the lock is logged, and the lock then expires with its TTL. The
+// lock is released even if the context is done by then, such as when the
+// operation the lock guards timed out.
+func AcquireVolumeLock(
+	ctx types.Context,
+	locker types.Locker,
+	driverName, volumeID string,
+	ttl time.Duration) (func(), error) {
+
+	key := VolumeLockKey(driverName, volumeID)
+	if err := locker.Acquire(ctx, key, ttl); err != nil {
+		if ctx.Err() != nil {
+			return nil, NewLockTimeoutErr(key, err)
+		}
+		return nil, err
+	}
+	ctx.WithField("key", key).Debug("acquired volume lock")
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			rctx, cancel := cleanupContext(ctx)
+			defer cancel()
+			if err := locker.Release(rctx, key); err != nil {
+				ctx.WithField("key", key).WithError(err).Warn(
+					"error releasing volume lock")
+			}
+		})
+	}, nil
+}
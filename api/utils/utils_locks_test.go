@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// newTestLockedDriver returns a driver with the volumes vol-000 and vol-001
+// whose operations take long enough to overlap if they are not serialized.
+// Removing the volume "panic" panics.
+func newTestLockedDriver() *testOptionalDriver {
+	d := &testOptionalDriver{newTestDriver()}
+	d.vols = []*types.Volume{{ID: "vol-000"}, {ID: "vol-001"}}
+	d.delay = 5 * time.Millisecond
+	d.before = func(ctx types.Context, op, id string) error {
+		if id == "panic" {
+			panic(id)
+		}
+		return nil
+	}
+	return d
+}
+
+func TestLockedStorageDriver(t *testing.T) {
+	td := newTestLockedDriver()
+	d := NewLockedStorageDriver(td)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for _, volumeID := range []string{"vol-000", "vol-001"} {
+		for i := 0; i < 4; i++ {
+			wg.Add(2)
+			go func(volumeID string) {
+				defer wg.Done()
+				_, _, err := d.VolumeAttach(ctx, volumeID, nil)
+				assert.NoError(t, err)
+			}(volumeID)
+			go func(volumeID string) {
+				defer wg.Done()
+				_, err := d.VolumeDetach(ctx, volumeID, nil)
+				assert.NoError(t, err)
+			}(volumeID)
+		}
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, td.busiest("vol-000"))
+	assert.Equal(t, 1, td.busiest("vol-001"))
+	assert.Empty(t, d.(*LockedStorageDriver).locks.locks)
+}
+
+func TestLockedStorageDriverOptional(t *testing.T) {
+	td := newTestLockedDriver()
+	d := NewCachedStorageDriver(
+		NewLockedStorageDriver(td), time.Minute, 0)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := VolumeRename(ctx, d, "vol-000", "renamed", nil)
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := d.VolumeDetach(ctx, "vol-000", nil)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// the renames, which the decorators do not implement, were serialized
+	// with the detaches
+	assert.Equal(t, 1, td.busiest("vol-000"))
+}
+
+func TestLockedStorageDriverPanic(t *testing.T) {
+	d := NewLockedStorageDriver(newTestLockedDriver())
+	ctx := context.Background()
+
+	assert.Panics(t, func() { d.VolumeRemove(ctx, "panic", nil) })
+
+	// the lock was released when the operation panicked
+	ctx, cancel := context.WithDefaultTimeout(ctx, time.Second)
+	defer cancel()
+	assert.Panics(t, func() { d.VolumeRemove(ctx, "panic", nil) })
+}
+
+func TestVolumeLocksContext(t *testing.T) {
+	var locks VolumeLocks
+	unlock, err := locks.Lock(context.Background(), "vol-000")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	ctx, cancel := context.WithDefaultTimeout(
+		context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = locks.Lock(ctx, "vol-000")
+	assert.Equal(t, ctx.Err(), err)
+
+	// other volumes are not locked
+	unlock2, err := locks.Lock(ctx, "vol-001")
+	assert.NoError(t, err)
+	unlock2()
+
+	unlock()
+	unlock()
+	assert.Empty(t, locks.locks)
+}
+
+// testLocker is a Locker backed by VolumeLocks that records the keys it
+// releases.
+type testLocker struct {
+	NoopLocker
+	locks    VolumeLocks
+	unlocks  map[string]func()
+	released []string
+}
+
+func (l *testLocker) Acquire(
+	ctx types.Context, key string, ttl time.Duration) error {
+
+	unlock, err := l.locks.Lock(ctx, key)
+	if err != nil {
+		return err
+	}
+	l.unlocks[key] = unlock
+	return nil
+}
+
+func (l *testLocker) Release(ctx types.Context, key string) error {
+	l.unlocks[key]()
+	l.released = append(l.released, key)
+	return nil
+}
+
+func TestAcquireVolumeLock(t *testing.T) {
+	l := &testLocker{unlocks: map[string]func(){}}
+	ctx := context.Background()
+
+	release, err := AcquireVolumeLock(ctx, l, "ebs", "vol-000", time.Minute)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	tctx, cancel := context.WithDefaultTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = AcquireVolumeLock(tctx, l, "ebs", "vol-000", time.Minute)
+	if assert.Error(t, err) {
+		assert.IsType(t, &types.ErrLockTimeout{}, err)
+		assert.True(t, IsTemporaryErr(err))
+	}
+
+	release()
+	release()
+	assert.Equal(t, []string{"libstorage/ebs/volumes/vol-000"}, l.released)
+
+	release, err = AcquireVolumeLock(ctx, NoopLocker{}, "ebs", "vol-000", 0)
+	assert.NoError(t, err)
+	release()
+}
+
+// testCtxLocker is a Locker that, like a locker backed by a distributed
+// store, fails to release a lock if the context is done.
+type testCtxLocker struct {
+	NoopLocker
+	released []string
+}
+
+func (l *testCtxLocker) Release(ctx types.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.released = append(l.released, key)
+	return nil
+}
+
+func TestAcquireVolumeLockCanceled(t *testing.T) {
+	l := &testCtxLocker{}
+	ctx, cancel := context.WithDefaultTimeout(context.Background(), time.Minute)
+
+	release, err := AcquireVolumeLock(ctx, l, "ebs", "vol-000", time.Minute)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// the operation the lock guards times out before the lock is released
+	cancel()
+	release()
+	assert.Equal(t, []string{"libstorage/ebs/volumes/vol-000"}, l.released)
+}
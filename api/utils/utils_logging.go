@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"net/http"
+	"net/http/httputil"
+
 	log "github.com/Sirupsen/logrus"
 	gofig "github.com/akutz/gofig/types"
 
@@ -75,3 +78,56 @@ func ParseLoggingConfig(
 
 	return logConfig, nil
 }
+
+// Redacted replaces the value of a credential when it is logged.
+const Redacted = types.Redacted
+
+// RegisterConfigSecrets registers the values of the configuration's secret
+// properties, such as ebs.secretKey, with types.RegisterSecret so that they
+// are redacted from logs and errors.
+func RegisterConfigSecrets(config gofig.Config) {
+	registerConfigSecrets("", config.AllSettings())
+}
+
+func registerConfigSecrets(key string, val interface{}) {
+	switch tv := val.(type) {
+	case string:
+		if types.IsSecretConfigKey(key) {
+			types.RegisterSecret(tv)
+		}
+	case map[string]interface{}:
+		for k, v := range tv {
+			registerConfigSecrets(k, v)
+		}
+	case map[interface{}]interface{}:
+		for k, v := range tv {
+			if ks, ok := k.(string); ok {
+				registerConfigSecrets(ks, v)
+			}
+		}
+	}
+}
+
+// redactedHeaders are the HTTP headers that carry credentials.
+var redactedHeaders = []string{types.AuthorizationHeader}
+
+// DumpRequest returns the wire representation of the request, as
+// httputil.DumpRequest does, with the values of the headers that carry
+// credentials replaced by Redacted. The request's headers are not modified.
+func DumpRequest(req *http.Request, body bool) ([]byte, error) {
+	r := *req
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	for _, k := range redactedHeaders {
+		if r.Header.Get(k) != "" {
+			r.Header.Set(k, Redacted)
+		}
+	}
+	buf, err := httputil.DumpRequest(&r, body)
+
+	// DumpRequest replaces the body it reads with a copy
+	req.Body = r.Body
+	return buf, err
+}
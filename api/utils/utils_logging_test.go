@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestDumpRequestRedactsCredentials(t *testing.T) {
+	req, err := http.NewRequest(
+		http.MethodPost, "http://localhost/volumes",
+		bytes.NewBufferString(`{"name":"vol-1"}`))
+	assert.NoError(t, err)
+	req.Header.Set(types.AuthorizationHeader, "Bearer secret-token")
+
+	buf, err := DumpRequest(req, true)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(buf), "secret-token")
+	assert.Contains(t, string(buf), Redacted)
+	assert.Contains(t, string(buf), `{"name":"vol-1"}`)
+
+	// the request is unchanged
+	assert.Equal(t,
+		"Bearer secret-token", req.Header.Get(types.AuthorizationHeader))
+	body, err := ioutil.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"vol-1"}`, string(body))
+}
@@ -0,0 +1,281 @@
+package utils
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// MountVolume mounts a volume that is attached to the client's instance at
+// the mount point, which is created if it does not exist. The device is
+// read from the volume's attachment and mounted with the client's OS
+// driver. The OS driver mounts block devices itself and defers to the
+// executor for drivers, such as S3FS, that mount their volumes with FUSE.
+//
+// If the file system type is empty then the device's existing file system
+// is used. The options are passed to the mount command, except for the
+// following options, which are applied by MountVolume:
+//
+//   - bind, rbind: the volume must already be mounted, and its mount is
+//     bind mounted at the mount point instead of the device being mounted
+//     again. rbind includes the mounts beneath the volume's mount point.
+//   - propagation=TYPE: after the volume is mounted, the propagation type
+//     of the mount is set to TYPE, which is one of private, rprivate,
+//     shared, rshared, slave, or rslave. A propagation type may also be
+//     specified on its own, such as rshared. If the propagation type
+//     cannot be set then the volume is unmounted and the error returned.
+//
+// An ErrVolumeNotAttached error is returned if the volume is not attached to
+// the client's instance. An ErrAlreadyMounted error is returned if the
+// volume's device is already mounted, unless a bind mount is requested, or
+// if the mount point is in use.
+func MountVolume(
+	ctx types.Context,
+	client types.Client,
+	volumeID, mountPoint, fsType string,
+	opts []string) error {
+
+	mo, err := parseMountVolumeOpts(opts)
+	if err != nil {
+		return err
+	}
+
+	store := NewStore()
+	devicePath, err := attachedDevicePath(ctx, client, volumeID, store)
+	if err != nil {
+		return err
+	}
+
+	mounts, err := client.OS().Mounts(ctx, "", "", store)
+	if err != nil {
+		return err
+	}
+	var bindSource string
+	for _, m := range mounts {
+		if m.MountPoint == mountPoint {
+			return NewAlreadyMountedErr(m.Source, mountPoint)
+		}
+		if sameDevice(m.Source, devicePath) {
+			if mo.bind == "" {
+				return NewAlreadyMountedErr(devicePath, m.MountPoint)
+			}
+			if bindSource == "" {
+				bindSource = m.MountPoint
+			}
+		}
+	}
+	if mo.bind != "" && bindSource == "" {
+		return goof.WithFields(goof.Fields{
+			"volumeID":   volumeID,
+			"devicePath": devicePath,
+		}, "volume must be mounted before it is bind mounted")
+	}
+
+	if err := os.MkdirAll(mountPoint, 0755); err != nil {
+		return NewMountFailedErr(devicePath, mountPoint, err)
+	}
+
+	ctx.WithFields(log.Fields{
+		"volumeID":    volumeID,
+		"devicePath":  devicePath,
+		"mountPoint":  mountPoint,
+		"fsType":      fsType,
+		"bindSource":  bindSource,
+		"propagation": mo.propagation,
+	}).Debug("mounting volume")
+
+	if bindSource != "" {
+		err = bindMount(bindSource, mountPoint, mo.bind == "rbind")
+	} else {
+		err = client.OS().Mount(
+			ctx, devicePath, mountPoint,
+			&types.DeviceMountOpts{
+				MountOptions: strings.Join(mo.options, ","),
+				FsType:       fsType,
+				Opts:         store,
+			})
+	}
+	if err != nil {
+		return err
+	}
+
+	if mo.propagation != "" {
+		if err := setMountPropagation(
+			mountPoint, mo.propagation); err != nil {
+			// the volume is mounted without the requested propagation, so
+			// it is unmounted rather than left in an unexpected state
+			cctx, cancel := cleanupContext(ctx)
+			defer cancel()
+			if uerr := client.OS().Unmount(
+				cctx, mountPoint, store); uerr != nil {
+				ctx.WithField("mountPoint", mountPoint).WithError(uerr).Warn(
+					"error unmounting volume after setting propagation failed")
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// UnmountVolume unmounts the volume mounted at the mount point with the
+// client's OS driver. An ErrNotMounted error is returned if nothing is
+// mounted at the mount point.
+func UnmountVolume(
+	ctx types.Context,
+	client types.Client,
+	mountPoint string) error {
+
+	store := NewStore()
+	mounted, err := client.OS().IsMounted(ctx, mountPoint, store)
+	if err != nil {
+		return err
+	}
+	if !mounted {
+		return NewNotMountedErr(mountPoint)
+	}
+	return client.OS().Unmount(ctx, mountPoint, store)
+}
+
+// mountPropagations are the valid propagation types of a mount.
+var mountPropagations = []string{
+	"private", "rprivate", "shared", "rshared", "slave", "rslave",
+}
+
+// mountVolumeOpts are the options of MountVolume.
+type mountVolumeOpts struct {
+	// options are the options passed to the mount command.
+	options []string
+
+	// bind is "bind" or "rbind" if a bind mount is requested.
+	bind string
+
+	// propagation is the propagation type of the mount.
+	propagation string
+}
+
+// parseMountVolumeOpts separates the options that MountVolume applies
+// itself from those that are passed to the mount command. An error is
+// returned if a propagation type is invalid or if more than one is
+// specified.
+func parseMountVolumeOpts(opts []string) (*mountVolumeOpts, error) {
+
+	mo := &mountVolumeOpts{}
+	setPropagation := func(v string) error {
+		if !isMountPropagation(v) {
+			return goof.WithFields(goof.Fields{
+				"propagation": v,
+				"validValues": mountPropagations,
+			}, "invalid mount propagation")
+		}
+		if mo.propagation != "" && mo.propagation != v {
+			return goof.WithFields(goof.Fields{
+				"propagation1": mo.propagation,
+				"propagation2": v,
+			}, "conflicting mount propagations")
+		}
+		mo.propagation = v
+		return nil
+	}
+
+	for _, o := range opts {
+		var err error
+		switch {
+		case o == "bind" || o == "rbind":
+			mo.bind = o
+		case strings.HasPrefix(o, "propagation="):
+			err = setPropagation(strings.TrimPrefix(o, "propagation="))
+		case isMountPropagation(o):
+			err = setPropagation(o)
+		default:
+			mo.options = append(mo.options, o)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return mo, nil
+}
+
+func isMountPropagation(v string) bool {
+	for _, p := range mountPropagations {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
+
+// attachedDevicePath returns the path of the device of the volume's
+// attachment to the client's instance.
+func attachedDevicePath(
+	ctx types.Context,
+	client types.Client,
+	volumeID string,
+	opts types.Store) (string, error) {
+
+	vol, err := client.Storage().VolumeInspect(
+		ctx, volumeID,
+		&types.VolumeInspectOpts{Attachments: types.VolAttReqTrue, Opts: opts})
+	if err != nil {
+		return "", err
+	}
+	if vol == nil {
+		return "", NewVolumeNotFoundErr(volumeID, nil)
+	}
+
+	inst, err := client.Storage().InstanceInspect(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	if inst == nil || inst.InstanceID == nil {
+		return "", NewMissingInstanceIDError(client.Storage().Name())
+	}
+	for _, a := range vol.Attachments {
+		if a.InstanceID == nil || a.InstanceID.ID != inst.InstanceID.ID {
+			continue
+		}
+		if a.DeviceName == "" {
+			break
+		}
+		return devicePathForName(a.DeviceName), nil
+	}
+	return "", NewVolumeNotAttachedErr(volumeID)
+}
+
+// devicePathForName returns the path of the device with the provided name.
+// A name such as "xvdf" is returned as "/dev/xvdf" if that device exists.
+// Other names, such as the path of a device or the name of an S3 bucket,
+// are returned unchanged.
+func devicePathForName(deviceName string) string {
+	if strings.HasPrefix(deviceName, "/") {
+		return deviceName
+	}
+	devPath := path.Join("/dev", deviceName)
+	if _, err := os.Stat(devPath); err == nil {
+		return devPath
+	}
+	return deviceName
+}
+
+// sameDevice returns a flag indicating whether or not the paths are the same
+// device once symbolic links are resolved.
+func sameDevice(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ra, err := filepath.EvalSymlinks(a)
+	if err != nil {
+		return false
+	}
+	rb, err := filepath.EvalSymlinks(b)
+	if err != nil {
+		return false
+	}
+	return ra == rb
+}
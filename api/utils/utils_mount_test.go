@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// testMountClient is a client whose storage driver reports the attachments
+// of its volumes and whose OS driver records mounts in memory.
+type testMountClient struct {
+	types.Client
+	storage *testDriver
+	os      *testMountOSDriver
+}
+
+func (c *testMountClient) Storage() types.StorageDriver {
+	return c.storage
+}
+
+func (c *testMountClient) OS() types.OSDriver {
+	return c.os
+}
+
+// newTestMountDriver returns a driver with the volumes.
+func newTestMountDriver(vols ...*types.Volume) *testDriver {
+	d := newTestDriver()
+	d.vols = vols
+	return d
+}
+
+// testMountOSDriver is an OS driver that records mounts in memory.
+type testMountOSDriver struct {
+	types.OSDriver
+	mounts []*types.MountInfo
+	opts   []*types.DeviceMountOpts
+}
+
+func (d *testMountOSDriver) Mounts(
+	ctx types.Context,
+	deviceName, mountPoint string,
+	opts types.Store) ([]*types.MountInfo, error) {
+	return d.mounts, nil
+}
+
+func (d *testMountOSDriver) Mount(
+	ctx types.Context,
+	deviceName, mountPoint string,
+	opts *types.DeviceMountOpts) error {
+
+	d.mounts = append(d.mounts, &types.MountInfo{
+		Source:     deviceName,
+		MountPoint: mountPoint,
+	})
+	d.opts = append(d.opts, opts)
+	return nil
+}
+
+func (d *testMountOSDriver) IsMounted(
+	ctx types.Context,
+	mountPoint string,
+	opts types.Store) (bool, error) {
+
+	for _, m := range d.mounts {
+		if m.MountPoint == mountPoint {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *testMountOSDriver) Unmount(
+	ctx types.Context,
+	mountPoint string,
+	opts types.Store) error {
+
+	for i, m := range d.mounts {
+		if m.MountPoint == mountPoint {
+			d.mounts = append(d.mounts[:i], d.mounts[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func TestMountVolume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "libstorage-mount")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	iid := &types.InstanceID{ID: "i-000", Driver: "test"}
+	other := &types.InstanceID{ID: "i-001", Driver: "test"}
+	client := &testMountClient{
+		storage: newTestMountDriver(
+			&types.Volume{ID: "vol-000", Attachments: []*types.VolumeAttachment{
+				{DeviceName: "/dev/xvdf", InstanceID: iid}}},
+			&types.Volume{ID: "vol-001", Attachments: []*types.VolumeAttachment{
+				{DeviceName: "/dev/xvdg", InstanceID: other}}},
+			&types.Volume{ID: "bucket", Attachments: []*types.VolumeAttachment{
+				{DeviceName: "bucket", InstanceID: iid}}},
+		),
+		os: &testMountOSDriver{},
+	}
+	ctx := context.Background()
+	mp := filepath.Join(dir, "vol-000")
+
+	assert.NoError(t, MountVolume(
+		ctx, client, "vol-000", mp, "ext4", []string{"noatime", "ro"}))
+	if assert.Len(t, client.os.mounts, 1) {
+		assert.Equal(t, "/dev/xvdf", client.os.mounts[0].Source)
+		assert.Equal(t, "ext4", client.os.opts[0].FsType)
+		assert.Equal(t, "noatime,ro", client.os.opts[0].MountOptions)
+	}
+	_, err = os.Stat(mp)
+	assert.NoError(t, err, "mount point created")
+
+	// the device is already mounted
+	err = MountVolume(
+		ctx, client, "vol-000", filepath.Join(dir, "other"), "", nil)
+	assert.IsType(t, &types.ErrAlreadyMounted{}, err)
+
+	// the mount point is in use
+	err = MountVolume(ctx, client, "bucket", mp, "", nil)
+	assert.IsType(t, &types.ErrAlreadyMounted{}, err)
+
+	// the volume is attached to another instance
+	err = MountVolume(
+		ctx, client, "vol-001", filepath.Join(dir, "vol-001"), "", nil)
+	assert.IsType(t, &types.ErrVolumeNotAttached{}, err)
+
+	// a volume that is not a block device is mounted by name
+	assert.NoError(t, MountVolume(
+		ctx, client, "bucket", filepath.Join(dir, "bucket"), "", nil))
+	assert.Equal(t, "bucket", client.os.mounts[1].Source)
+
+	assert.NoError(t, UnmountVolume(ctx, client, mp))
+	assert.Len(t, client.os.mounts, 1)
+	err = UnmountVolume(ctx, client, mp)
+	assert.IsType(t, &types.ErrNotMounted{}, err)
+}
+
+func TestParseMountVolumeOpts(t *testing.T) {
+	mo, err := parseMountVolumeOpts(
+		[]string{"noatime", "bind", "propagation=rshared", "ro"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"noatime", "ro"}, mo.options)
+		assert.Equal(t, "bind", mo.bind)
+		assert.Equal(t, "rshared", mo.propagation)
+	}
+
+	mo, err = parseMountVolumeOpts([]string{"rslave", "propagation=rslave"})
+	if assert.NoError(t, err) {
+		assert.Empty(t, mo.options)
+		assert.Equal(t, "rslave", mo.propagation)
+	}
+
+	_, err = parseMountVolumeOpts([]string{"propagation=shard"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid mount propagation")
+	}
+
+	_, err = parseMountVolumeOpts([]string{"private", "propagation=rshared"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "conflicting mount propagations")
+	}
+}
+
+func TestAttachedDevicePathNoInstanceID(t *testing.T) {
+	iid := &types.InstanceID{ID: "i-000", Driver: "test"}
+	storage := newTestMountDriver(
+		&types.Volume{ID: "vol-000", Attachments: []*types.VolumeAttachment{
+			{DeviceName: "/dev/xvdf", InstanceID: iid}}},
+	)
+	client := &testMountClient{storage: storage, os: &testMountOSDriver{}}
+	ctx := context.Background()
+
+	for _, inst := range []*types.Instance{nil, {Name: "node1"}} {
+		storage.instance = inst
+		_, err := attachedDevicePath(ctx, client, "vol-000", nil)
+		assert.IsType(t, &types.ErrMissingInstanceID{}, err)
+	}
+}
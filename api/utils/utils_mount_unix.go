@@ -0,0 +1,44 @@
+// +build !windows
+
+package utils
+
+import (
+	"bytes"
+	"os/exec"
+
+	"github.com/akutz/goof"
+)
+
+// bindMount mounts the directory at the mount point with the mount command.
+// If recursive is true then the mounts beneath the directory are also
+// mounted.
+func bindMount(source, mountPoint string, recursive bool) error {
+	flag := "--bind"
+	if recursive {
+		flag = "--rbind"
+	}
+	out, err := exec.Command(
+		"mount", flag, source, mountPoint).CombinedOutput()
+	if err != nil {
+		return NewMountFailedErr(source, mountPoint, goof.WithFieldE(
+			"output", string(bytes.TrimSpace(out)), "bind mount failed", err))
+	}
+	return nil
+}
+
+// setMountPropagation sets the propagation type of the mount at the mount
+// point with the mount command. The type cannot be set when the device is
+// mounted because the kernel ignores the other mount flags when a
+// propagation flag is present.
+func setMountPropagation(mountPoint, propagation string) error {
+	out, err := exec.Command(
+		"mount", "--make-"+propagation, mountPoint).CombinedOutput()
+	if err != nil {
+		return goof.WithFieldsE(goof.Fields{
+			"mountPoint":  mountPoint,
+			"propagation": propagation,
+			"output":      string(bytes.TrimSpace(out)),
+		}, "error setting mount propagation", err)
+	}
+	return nil
+}
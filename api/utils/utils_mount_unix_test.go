@@ -0,0 +1,89 @@
+// +build !windows
+
+package utils
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestMountVolumeBindAndPropagation(t *testing.T) {
+	dir, cleanup := fakeFormatTools(t)
+	defer cleanup()
+
+	iid := &types.InstanceID{ID: "i-000", Driver: "test"}
+	client := &testMountClient{
+		storage: newTestMountDriver(
+			&types.Volume{ID: "vol-000", Attachments: []*types.VolumeAttachment{
+				{DeviceName: "/dev/xvdf", InstanceID: iid}}},
+		),
+		os: &testMountOSDriver{},
+	}
+	ctx := context.Background()
+	hostMnt := path.Join(dir, "host")
+	ctrMnt := path.Join(dir, "container")
+
+	// a bind mount requires the volume to be mounted
+	err := MountVolume(ctx, client, "vol-000", ctrMnt, "", []string{"bind"})
+	assert.Error(t, err)
+
+	assert.NoError(t, MountVolume(
+		ctx, client, "vol-000", hostMnt, "ext4",
+		[]string{"propagation=rshared"}))
+	assert.Equal(t, []string{
+		"mount --make-rshared " + hostMnt,
+	}, readFormatLog(t, dir))
+	assert.Equal(t, "", client.os.opts[0].MountOptions)
+
+	assert.NoError(t, MountVolume(
+		ctx, client, "vol-000", ctrMnt, "", []string{"rbind", "rslave"}))
+	assert.Equal(t, []string{
+		"mount --rbind " + hostMnt + " " + ctrMnt,
+		"mount --make-rslave " + ctrMnt,
+	}, readFormatLog(t, dir))
+	assert.Len(t, client.os.mounts, 1, "bind mounts bypass the OS driver")
+
+	// an invalid propagation type is rejected before anything is mounted
+	err = MountVolume(
+		ctx, client, "vol-000", path.Join(dir, "other"), "",
+		[]string{"bind", "propagation=shard"})
+	assert.Error(t, err)
+	assert.Equal(t, []string{""}, readFormatLog(t, dir))
+}
+
+func TestMountVolumePropagationFailed(t *testing.T) {
+	dir, cleanup := fakeFormatTools(t)
+	defer cleanup()
+
+	iid := &types.InstanceID{ID: "i-000", Driver: "test"}
+	client := &testMountClient{
+		storage: newTestMountDriver(
+			&types.Volume{ID: "vol-000", Attachments: []*types.VolumeAttachment{
+				{DeviceName: "/dev/xvdf", InstanceID: iid}}},
+		),
+		os: &testMountOSDriver{},
+	}
+	ctx := context.Background()
+	mnt := path.Join(dir, "unshared")
+
+	// the fake mount command fails to set the propagation of the mount point
+	err := MountVolume(
+		ctx, client, "vol-000", mnt, "ext4", []string{"rshared"})
+	assert.Error(t, err)
+	assert.Equal(t, []string{
+		"mount --make-rshared " + mnt,
+	}, readFormatLog(t, dir))
+	assert.Len(t, client.os.opts, 1, "the volume was mounted")
+	assert.Empty(t, client.os.mounts, "the volume was unmounted")
+
+	// the volume can be mounted again once the mount point is fixed
+	assert.NoError(t, MountVolume(
+		ctx, client, "vol-000", path.Join(dir, "host"), "ext4",
+		[]string{"rshared"}))
+	assert.Len(t, client.os.mounts, 1)
+}
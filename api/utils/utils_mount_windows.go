@@ -0,0 +1,11 @@
+// +build windows
+
+package utils
+
+func bindMount(source, mountPoint string, recursive bool) error {
+	return NewUnsupportedErr("windows", "bind mounts")
+}
+
+func setMountPropagation(mountPoint, propagation string) error {
+	return NewUnsupportedErr("windows", "mount propagation")
+}
@@ -0,0 +1,248 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// VolumesPaged returns a single page of volumes. If the driver implements
+// StorageDriverPaged then the driver's native paging is used. Otherwise the
+// paging is simulated over the full, sorted list of volumes and the page
+// token identifies the last volume in the previous page.
+func VolumesPaged(
+	ctx types.Context,
+	d types.StorageDriver,
+	listOpts *types.ListOpts,
+	opts *types.VolumesOpts) (*types.VolumePage, error) {
+
+	if listOpts == nil {
+		listOpts = &types.ListOpts{}
+	}
+	if opts == nil {
+		opts = &types.VolumesOpts{Opts: NewStore()}
+	}
+	if err := validateListOpts(listOpts); err != nil {
+		return nil, err
+	}
+
+	if pd, ok := underlying(d).(types.StorageDriverPaged); ok {
+		return pd.VolumesPaged(ctx, listOpts, opts)
+	}
+
+	vols, err := d.Volumes(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return PageVolumes(vols, listOpts), nil
+}
+
+// SnapshotsPaged returns a single page of snapshots. If the driver implements
+// StorageDriverPaged then the driver's native paging is used. Otherwise the
+// paging is simulated over the full, sorted list of snapshots and the page
+// token identifies the last snapshot in the previous page.
+func SnapshotsPaged(
+	ctx types.Context,
+	d types.StorageDriver,
+	listOpts *types.ListOpts,
+	opts types.Store) (*types.SnapshotPage, error) {
+
+	if listOpts == nil {
+		listOpts = &types.ListOpts{}
+	}
+	if opts == nil {
+		opts = NewStore()
+	}
+	if err := validateListOpts(listOpts); err != nil {
+		return nil, err
+	}
+
+	if pd, ok := underlying(d).(types.StorageDriverPaged); ok {
+		return pd.SnapshotsPaged(ctx, listOpts, opts)
+	}
+
+	snaps, err := d.Snapshots(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return PageSnapshots(snaps, listOpts), nil
+}
+
+// PageVolumes returns a single page from a full list of volumes. The volumes
+// are sorted as requested by the ListOpts.
+func PageVolumes(
+	vols []*types.Volume, listOpts *types.ListOpts) *types.VolumePage {
+
+	keys := make([]pageKey, len(vols))
+	for i, v := range vols {
+		keys[i] = pageKey{id: v.ID}
+		switch listOpts.SortBy {
+		case types.ListSortByName:
+			keys[i].s = v.Name
+		case types.ListSortByCreateTime:
+			keys[i].n = v.CreateTime
+		case types.ListSortBySize:
+			keys[i].n = v.Size
+		}
+	}
+
+	start, end, next := page(keys, func(i, j int) {
+		vols[i], vols[j] = vols[j], vols[i]
+	}, listOpts)
+	return &types.VolumePage{Items: vols[start:end], NextPageToken: next}
+}
+
+// PageSnapshots returns a single page from a full list of snapshots. The
+// snapshots are sorted as requested by the ListOpts.
+func PageSnapshots(
+	snaps []*types.Snapshot, listOpts *types.ListOpts) *types.SnapshotPage {
+
+	keys := make([]pageKey, len(snaps))
+	for i, s := range snaps {
+		keys[i] = pageKey{id: s.ID}
+		switch listOpts.SortBy {
+		case types.ListSortByName:
+			keys[i].s = s.Name
+		case types.ListSortByCreateTime:
+			keys[i].n = s.StartTime
+		case types.ListSortBySize:
+			keys[i].n = s.VolumeSize
+		}
+	}
+
+	start, end, next := page(keys, func(i, j int) {
+		snaps[i], snaps[j] = snaps[j], snaps[i]
+	}, listOpts)
+	return &types.SnapshotPage{Items: snaps[start:end], NextPageToken: next}
+}
+
+// validateListOpts returns an error if the ListOpts request an unknown sort
+// key or order.
+func validateListOpts(listOpts *types.ListOpts) error {
+	switch listOpts.SortBy {
+	case "",
+		types.ListSortByID,
+		types.ListSortByName,
+		types.ListSortByCreateTime,
+		types.ListSortBySize:
+	default:
+		return goof.WithField("sortBy", listOpts.SortBy, "invalid sort key")
+	}
+	switch listOpts.SortOrder {
+	case "", types.ListSortAsc, types.ListSortDesc:
+	default:
+		return goof.WithField(
+			"sortOrder", listOpts.SortOrder, "invalid sort order")
+	}
+	return nil
+}
+
+// pageKey is the key by which an item in a page is sorted. Only the field
+// for the requested sort key is set in addition to the item's ID, which
+// breaks ties so the order is deterministic.
+type pageKey struct {
+	n  int64
+	s  string
+	id string
+}
+
+func (k pageKey) compare(o pageKey) int {
+	switch {
+	case k.n != o.n:
+		if k.n < o.n {
+			return -1
+		}
+		return 1
+	case k.s != o.s:
+		return strings.Compare(k.s, o.s)
+	default:
+		return strings.Compare(k.id, o.id)
+	}
+}
+
+// pageToken returns the token for the page that begins after the item with
+// this key. Items sorted by ID use the ID as the token so that tokens remain
+// compatible with earlier releases.
+func (k pageKey) pageToken(sortBy string) string {
+	if sortBy == "" || sortBy == types.ListSortByID {
+		return k.id
+	}
+	return fmt.Sprintf("%d,%s,%s",
+		k.n, url.QueryEscape(k.s), url.QueryEscape(k.id))
+}
+
+func parsePageToken(token string) pageKey {
+	parts := strings.Split(token, ",")
+	if len(parts) != 3 {
+		return pageKey{id: token}
+	}
+	k := pageKey{}
+	k.n, _ = strconv.ParseInt(parts[0], 10, 64)
+	k.s, _ = url.QueryUnescape(parts[1])
+	k.id, _ = url.QueryUnescape(parts[2])
+	return k
+}
+
+// pageSorter sorts the keys of a list of items and uses the swap function to
+// sort the items in the same order.
+type pageSorter struct {
+	keys []pageKey
+	swap func(i, j int)
+	desc bool
+}
+
+func (p *pageSorter) Len() int { return len(p.keys) }
+
+func (p *pageSorter) Swap(i, j int) {
+	p.keys[i], p.keys[j] = p.keys[j], p.keys[i]
+	p.swap(i, j)
+}
+
+func (p *pageSorter) Less(i, j int) bool {
+	if p.desc {
+		return p.keys[i].compare(p.keys[j]) > 0
+	}
+	return p.keys[i].compare(p.keys[j]) < 0
+}
+
+// page sorts the items and returns the start and end indices of the
+// requested page along with the token for the next page.
+func page(
+	keys []pageKey,
+	swap func(i, j int),
+	listOpts *types.ListOpts) (int, int, string) {
+
+	desc := listOpts.SortOrder == types.ListSortDesc
+	sort.Sort(&pageSorter{keys: keys, swap: swap, desc: desc})
+
+	start := 0
+	if listOpts.PageToken != "" {
+		token := parsePageToken(listOpts.PageToken)
+		start = sort.Search(len(keys), func(i int) bool {
+			if desc {
+				return keys[i].compare(token) < 0
+			}
+			return keys[i].compare(token) > 0
+		})
+	}
+	end := pageEnd(start, len(keys), listOpts.MaxResults)
+
+	next := ""
+	if end < len(keys) && end > start {
+		next = keys[end-1].pageToken(listOpts.SortBy)
+	}
+	return start, end, next
+}
+
+func pageEnd(start, length int, maxResults int64) int {
+	if maxResults <= 0 || int64(length-start) <= maxResults {
+		return length
+	}
+	return start + int(maxResults)
+}
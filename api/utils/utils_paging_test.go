@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestPageVolumes(t *testing.T) {
+	vols := []*types.Volume{
+		{ID: "vol-003"},
+		{ID: "vol-001"},
+		{ID: "vol-002"},
+	}
+
+	page := PageVolumes(vols, &types.ListOpts{MaxResults: 2})
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "vol-001", page.Items[0].ID)
+	assert.Equal(t, "vol-002", page.Items[1].ID)
+	assert.Equal(t, "vol-002", page.NextPageToken)
+
+	page = PageVolumes(vols, &types.ListOpts{
+		MaxResults: 2, PageToken: page.NextPageToken})
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "vol-003", page.Items[0].ID)
+	assert.Empty(t, page.NextPageToken)
+
+	page = PageVolumes(vols, &types.ListOpts{})
+	assert.Len(t, page.Items, 3)
+	assert.Empty(t, page.NextPageToken)
+}
+
+func TestPageSnapshots(t *testing.T) {
+	snaps := []*types.Snapshot{
+		{ID: "snap-002"},
+		{ID: "snap-001"},
+	}
+
+	page := PageSnapshots(snaps, &types.ListOpts{MaxResults: 1})
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "snap-001", page.Items[0].ID)
+	assert.Equal(t, "snap-001", page.NextPageToken)
+
+	page = PageSnapshots(snaps, &types.ListOpts{
+		MaxResults: 1, PageToken: page.NextPageToken})
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "snap-002", page.Items[0].ID)
+	assert.Empty(t, page.NextPageToken)
+
+	page = PageSnapshots(snaps, &types.ListOpts{PageToken: "snap-002"})
+	assert.Empty(t, page.Items)
+	assert.Empty(t, page.NextPageToken)
+}
+
+func TestPageVolumesSorted(t *testing.T) {
+	vols := []*types.Volume{
+		{ID: "vol-001", Name: "b", Size: 20},
+		{ID: "vol-002", Name: "a,1", Size: 10},
+		{ID: "vol-003", Name: "b", Size: 30},
+		{ID: "vol-004", Name: "c", Size: 10},
+	}
+	ids := func(page *types.VolumePage) []string {
+		s := []string{}
+		for _, v := range page.Items {
+			s = append(s, v.ID)
+		}
+		return s
+	}
+
+	listOpts := &types.ListOpts{MaxResults: 2, SortBy: types.ListSortByName}
+	page := PageVolumes(vols, listOpts)
+	assert.Equal(t, []string{"vol-002", "vol-001"}, ids(page))
+	listOpts.PageToken = page.NextPageToken
+	page = PageVolumes(vols, listOpts)
+	assert.Equal(t, []string{"vol-003", "vol-004"}, ids(page))
+	assert.Empty(t, page.NextPageToken)
+
+	listOpts = &types.ListOpts{
+		MaxResults: 3,
+		SortBy:     types.ListSortBySize,
+		SortOrder:  types.ListSortDesc,
+	}
+	page = PageVolumes(vols, listOpts)
+	assert.Equal(t, []string{"vol-003", "vol-001", "vol-004"}, ids(page))
+	listOpts.PageToken = page.NextPageToken
+	page = PageVolumes(vols, listOpts)
+	assert.Equal(t, []string{"vol-002"}, ids(page))
+}
+
+func TestVolumesPagedInvalidSort(t *testing.T) {
+	d := newTestDriver()
+	_, err := VolumesPaged(context.Background(), d,
+		&types.ListOpts{SortBy: "color"}, nil)
+	assert.Error(t, err)
+	_, err = VolumesPaged(context.Background(), d,
+		&types.ListOpts{SortOrder: "sideways"}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,337 @@
+package utils
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// RateLimit is the rate at which a group of operations may be invoked. A
+// rate less than or equal to zero does not limit the operations.
+type RateLimit struct {
+	// Rate is the number of operations permitted per second.
+	Rate float64
+
+	// Burst is the number of operations that may be invoked at once after a
+	// period of inactivity. A burst less than one results in a burst of one.
+	Burst int
+}
+
+// ParseRateLimits returns the read and mutation rate limits from the
+// config's libstorage.rateLimit properties. An error is returned if a
+// property is not a number.
+func ParseRateLimits(config gofig.Config) (reads, mutations RateLimit, err error) {
+	if reads, err = parseRateLimit(
+		config,
+		types.ConfigRateLimitReads,
+		types.ConfigRateLimitReadBurst); err != nil {
+		return
+	}
+	mutations, err = parseRateLimit(
+		config,
+		types.ConfigRateLimitMutations,
+		types.ConfigRateLimitMutationBurst)
+	return
+}
+
+func parseRateLimit(
+	config gofig.Config, rateKey, burstKey string) (RateLimit, error) {
+
+	var rl RateLimit
+	if v := config.GetString(rateKey); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return rl, goof.WithFieldE(rateKey, v, "invalid rate limit", err)
+		}
+		rl.Rate = rate
+	}
+	if v := config.GetString(burstKey); v != "" {
+		burst, err := strconv.Atoi(v)
+		if err != nil {
+			return rl, goof.WithFieldE(burstKey, v, "invalid rate limit", err)
+		}
+		rl.Burst = burst
+	}
+	return rl, nil
+}
+
+// tokenBucket is a goroutine-safe token bucket. Tokens are added at a fixed
+// rate up to the bucket's capacity, and each operation takes one token.
+type tokenBucket struct {
+	sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rl RateLimit) *tokenBucket {
+	if rl.Rate <= 0 {
+		return nil
+	}
+	burst := float64(rl.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rl.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait takes a token from the bucket, waiting until one is available. If the
+// context's deadline would pass before a token is available then an
+// ErrRateLimited error is returned immediately and no token is taken.
+func (b *tokenBucket) wait(ctx types.Context, op string) error {
+	if b == nil {
+		return nil
+	}
+
+	b.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		b.Unlock()
+		return nil
+	}
+
+	delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		b.Unlock()
+		return NewRateLimitedErr(op, delay)
+	}
+
+	// reserve the token so that concurrent callers queue behind this one
+	b.tokens--
+	b.Unlock()
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		b.Lock()
+		b.tokens++
+		b.Unlock()
+		return ctx.Err()
+	}
+}
+
+// RateLimitedStorageDriver is a StorageDriver that limits the rate at which
+// the underlying driver's operations are invoked in order to stay within the
+// storage platform's API quotas. Reads, such as VolumeInspect, and
+// mutations, such as VolumeCreate, are limited separately. A
+// RateLimitedStorageDriver is safe for concurrent use.
+//
+// The optional interfaces a driver implements are not visible through a
+// RateLimitedStorageDriver. The functions in this package that invoke them,
+// such as VolumeResize, use the underlying driver, so those operations are
+// not rate limited.
+type RateLimitedStorageDriver struct {
+	types.StorageDriver
+	reads     *tokenBucket
+	mutations *tokenBucket
+}
+
+// NewRateLimitedStorageDriver returns a StorageDriver that limits the rate
+// of the provided driver's reads and mutations. If neither group is limited
+// then the provided driver is returned as is.
+func NewRateLimitedStorageDriver(
+	d types.StorageDriver,
+	reads, mutations RateLimit) types.StorageDriver {
+
+	if reads.Rate <= 0 && mutations.Rate <= 0 {
+		return d
+	}
+	return &RateLimitedStorageDriver{
+		StorageDriver: d,
+		reads:         newTokenBucket(reads),
+		mutations:     newTokenBucket(mutations),
+	}
+}
+
+// Driver returns the underlying driver.
+func (d *RateLimitedStorageDriver) Driver() types.StorageDriver {
+	return d.StorageDriver
+}
+
+// InstanceInspect returns an instance.
+func (d *RateLimitedStorageDriver) InstanceInspect(
+	ctx types.Context,
+	opts types.Store) (*types.Instance, error) {
+
+	if err := d.reads.wait(ctx, "InstanceInspect"); err != nil {
+		return nil, err
+	}
+	return d.StorageDriver.InstanceInspect(ctx, opts)
+}
+
+// Volumes returns all volumes or a filtered list of volumes.
+func (d *RateLimitedStorageDriver) Volumes(
+	ctx types.Context,
+	opts *types.VolumesOpts) ([]*types.Volume, error) {
+
+	if err := d.reads.wait(ctx, "Volumes"); err != nil {
+		return nil, err
+	}
+	return d.StorageDriver.Volumes(ctx, opts)
+}
+
+// VolumeInspect inspects a single volume.
+func (d *RateLimitedStorageDriver) VolumeInspect(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeInspectOpts) (*types.Volume, error) {
+
+	if err := d.reads.wait(ctx, "VolumeInspect"); err != nil {
+		return nil, err
+	}
+	return d.StorageDriver.VolumeInspect(ctx, volumeID, opts)
+}
+
+// VolumeCreate creates a new volume.
+func (d *RateLimitedStorageDriver) VolumeCreate(
+	ctx types.Context,
+	name string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	if err := d.mutations.wait(ctx, "VolumeCreate"); err != nil {
+		return nil, err
+	}
+	return d.StorageDriver.VolumeCreate(ctx, name, opts)
+}
+
+// VolumeCreateFromSnapshot creates a new volume from an existing snapshot.
+func (d *RateLimitedStorageDriver) VolumeCreateFromSnapshot(
+	ctx types.Context,
+	snapshotID, volumeName string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	if err := d.mutations.wait(ctx, "VolumeCreateFromSnapshot"); err != nil {
+		return nil, err
+	}
+	return d.StorageDriver.VolumeCreateFromSnapshot(
+		ctx, snapshotID, volumeName, opts)
+}
+
+// VolumeCopy copies an existing volume.
+func (d *RateLimitedStorageDriver) VolumeCopy(
+	ctx types.Context,
+	volumeID, volumeName string,
+	opts types.Store) (*types.Volume, error) {
+
+	if err := d.mutations.wait(ctx, "VolumeCopy"); err != nil {
+		return nil, err
+	}
+	return d.StorageDriver.VolumeCopy(ctx, volumeID, volumeName, opts)
+}
+
+// VolumeSnapshot snapshots a volume.
+func (d *RateLimitedStorageDriver) VolumeSnapshot(
+	ctx types.Context,
+	volumeID, snapshotName string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	if err := d.mutations.wait(ctx, "VolumeSnapshot"); err != nil {
+		return nil, err
+	}
+	return d.StorageDriver.VolumeSnapshot(ctx, volumeID, snapshotName, opts)
+}
+
+// VolumeRemove removes a volume.
+func (d *RateLimitedStorageDriver) VolumeRemove(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeRemoveOpts) error {
+
+	if err := d.mutations.wait(ctx, "VolumeRemove"); err != nil {
+		return err
+	}
+	return d.StorageDriver.VolumeRemove(ctx, volumeID, opts)
+}
+
+// VolumeAttach attaches a volume.
+func (d *RateLimitedStorageDriver) VolumeAttach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeAttachOpts) (*types.Volume, string, error) {
+
+	if err := d.mutations.wait(ctx, "VolumeAttach"); err != nil {
+		return nil, "", err
+	}
+	return d.StorageDriver.VolumeAttach(ctx, volumeID, opts)
+}
+
+// VolumeDetach detaches a volume.
+func (d *RateLimitedStorageDriver) VolumeDetach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeDetachOpts) (*types.Volume, error) {
+
+	if err := d.mutations.wait(ctx, "VolumeDetach"); err != nil {
+		return nil, err
+	}
+	return d.StorageDriver.VolumeDetach(ctx, volumeID, opts)
+}
+
+// Snapshots returns all snapshots.
+func (d *RateLimitedStorageDriver) Snapshots(
+	ctx types.Context,
+	opts types.Store) ([]*types.Snapshot, error) {
+
+	if err := d.reads.wait(ctx, "Snapshots"); err != nil {
+		return nil, err
+	}
+	return d.StorageDriver.Snapshots(ctx, opts)
+}
+
+// SnapshotInspect inspects a single snapshot.
+func (d *RateLimitedStorageDriver) SnapshotInspect(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	if err := d.reads.wait(ctx, "SnapshotInspect"); err != nil {
+		return nil, err
+	}
+	return d.StorageDriver.SnapshotInspect(ctx, snapshotID, opts)
+}
+
+// SnapshotCopy copies an existing snapshot.
+func (d *RateLimitedStorageDriver) SnapshotCopy(
+	ctx types.Context,
+	snapshotID, snapshotName, destinationID string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	if err := d.mutations.wait(ctx, "SnapshotCopy"); err != nil {
+		return nil, err
+	}
+	return d.StorageDriver.SnapshotCopy(
+		ctx, snapshotID, snapshotName, destinationID, opts)
+}
+
+// SnapshotRemove removes a snapshot.
+func (d *RateLimitedStorageDriver) SnapshotRemove(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) error {
+
+	if err := d.mutations.wait(ctx, "SnapshotRemove"); err != nil {
+		return err
+	}
+	return d.StorageDriver.SnapshotRemove(ctx, snapshotID, opts)
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	gofigCore "github.com/akutz/gofig"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// newTestRateLimitedDriver returns a driver with the volume vol-000.
+func newTestRateLimitedDriver() *testDriver {
+	d := newTestDriver()
+	d.vols = []*types.Volume{{ID: "vol-000"}}
+	return d
+}
+
+func TestNewRateLimitedStorageDriverNoLimit(t *testing.T) {
+	td := newTestRateLimitedDriver()
+	d := NewRateLimitedStorageDriver(td, RateLimit{}, RateLimit{})
+	assert.Equal(t, td, d)
+}
+
+func TestRateLimitedStorageDriverBurst(t *testing.T) {
+	td := newTestRateLimitedDriver()
+	d := NewRateLimitedStorageDriver(
+		td, RateLimit{Rate: 0.001, Burst: 2}, RateLimit{})
+	ctx, cancel := context.WithDefaultTimeout(
+		context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		_, err := d.VolumeInspect(ctx, "vol-000", nil)
+		assert.NoError(t, err)
+	}
+
+	// the bucket is empty and the next token is not available before the
+	// context's deadline
+	_, err := d.VolumeInspect(ctx, "vol-000", nil)
+	if assert.Error(t, err) {
+		assert.IsType(t, &types.ErrRateLimited{}, err)
+		assert.True(t, IsTemporaryErr(err))
+	}
+	assert.Equal(t, 2, td.count("VolumeInspect"))
+
+	// mutations are not limited
+	for i := 0; i < 5; i++ {
+		_, err := d.VolumeDetach(ctx, "vol-000", nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 5, td.count("VolumeDetach"))
+}
+
+func TestRateLimitedStorageDriverWait(t *testing.T) {
+	td := newTestRateLimitedDriver()
+	d := NewRateLimitedStorageDriver(
+		td, RateLimit{}, RateLimit{Rate: 100, Burst: 1})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := d.VolumeDetach(ctx, "vol-000", nil)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// one detach uses the burst and the other three each wait ~10ms
+	assert.True(t, time.Since(start) >= 25*time.Millisecond)
+	assert.Equal(t, 4, td.count("VolumeDetach"))
+}
+
+func TestParseRateLimits(t *testing.T) {
+	config := gofigCore.New()
+	reads, mutations, err := ParseRateLimits(config)
+	assert.NoError(t, err)
+	assert.Equal(t, RateLimit{}, reads)
+	assert.Equal(t, RateLimit{}, mutations)
+
+	config.Set(types.ConfigRateLimitReads, "20")
+	config.Set(types.ConfigRateLimitReadBurst, "40")
+	config.Set(types.ConfigRateLimitMutations, "0.5")
+	reads, mutations, err = ParseRateLimits(config)
+	assert.NoError(t, err)
+	assert.Equal(t, RateLimit{Rate: 20, Burst: 40}, reads)
+	assert.Equal(t, RateLimit{Rate: 0.5}, mutations)
+
+	config.Set(types.ConfigRateLimitMutationBurst, "many")
+	_, _, err = ParseRateLimits(config)
+	assert.Error(t, err)
+}
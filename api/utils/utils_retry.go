@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the number of times an operation is
+	// attempted when no maximum is configured.
+	DefaultRetryMaxAttempts = 3
+
+	// DefaultRetryBaseDelay is the delay before the first retry when no base
+	// delay is configured.
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+
+	// maxRetryDelay is the longest Retry will wait between attempts.
+	maxRetryDelay = 30 * time.Second
+)
+
+const (
+	// RetryOpInstanceInspect is the name of the operation that inspects an
+	// instance.
+	RetryOpInstanceInspect = "instanceInspect"
+
+	// RetryOpVolumes is the name of the operation that lists volumes.
+	RetryOpVolumes = "volumes"
+
+	// RetryOpVolumeInspect is the name of the operation that inspects a
+	// volume.
+	RetryOpVolumeInspect = "volumeInspect"
+
+	// RetryOpSnapshots is the name of the operation that lists snapshots.
+	RetryOpSnapshots = "snapshots"
+
+	// RetryOpSnapshotInspect is the name of the operation that inspects a
+	// snapshot.
+	RetryOpSnapshotInspect = "snapshotInspect"
+)
+
+// RetryableOps are the names of the idempotent operations that may be
+// retried.
+var RetryableOps = []string{
+	RetryOpInstanceInspect,
+	RetryOpVolumes,
+	RetryOpVolumeInspect,
+	RetryOpSnapshots,
+	RetryOpSnapshotInspect,
+}
+
+// RetryOpts are the options used to retry operations that fail with a
+// temporary error.
+type RetryOpts struct {
+	// MaxAttempts is the maximum number of times the operation is attempted,
+	// including the first attempt. A value less than or equal to one
+	// disables retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. The delay doubles with
+	// each subsequent retry.
+	BaseDelay time.Duration
+
+	// Ops are the names of the operations that are retried. A nil map
+	// retries all of the RetryableOps.
+	Ops map[string]bool
+}
+
+// Retries returns a flag indicating whether or not the operation with the
+// provided name is retried.
+func (o *RetryOpts) Retries(op string) bool {
+	if o.Ops != nil {
+		return o.Ops[op]
+	}
+	for _, rop := range RetryableOps {
+		if op == rop {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRetryOps returns the set of operations named in ops. An error is
+// returned if an operation is not one of the RetryableOps. A nil map is
+// returned if ops is empty.
+func ParseRetryOps(ops []string) (map[string]bool, error) {
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	m := map[string]bool{}
+	for _, op := range ops {
+		op = strings.TrimSpace(op)
+		found := false
+		for _, rop := range RetryableOps {
+			if strings.EqualFold(op, rop) {
+				m[rop] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, goof.WithFields(goof.Fields{
+				"op":           op,
+				"retryableOps": RetryableOps,
+			}, "operation cannot be retried")
+		}
+	}
+	return m, nil
+}
+
+// NewRetryOpts returns new retry options. A maximum number of attempts less
+// than zero results in DefaultRetryMaxAttempts, and an empty or invalid base
+// delay results in DefaultRetryBaseDelay.
+func NewRetryOpts(maxAttempts int, baseDelay string) *RetryOpts {
+	opts := &RetryOpts{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+	}
+	if opts.MaxAttempts < 0 {
+		opts.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if baseDelay != "" {
+		if dur, err := time.ParseDuration(baseDelay); err == nil {
+			opts.BaseDelay = dur
+		}
+	}
+	return opts
+}
+
+var (
+	retryRand  = rand.New(rand.NewSource(time.Now().UnixNano()))
+	retryRandL sync.Mutex
+)
+
+// Retry invokes the provided function until it succeeds, it returns an error
+// that is not temporary according to IsTemporaryErr, or the maximum number of
+// attempts is reached. The delay between attempts grows exponentially from
+// the base delay and is randomized by up to half in order to keep multiple
+// clients from retrying in lockstep. If the context is done while waiting to
+// retry then the context's error is returned. Only idempotent operations
+// should be retried.
+func Retry(ctx types.Context, opts *RetryOpts, f func() error) error {
+
+	if opts == nil {
+		opts = NewRetryOpts(-1, "")
+	}
+
+	delay := opts.BaseDelay
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil ||
+			attempt >= opts.MaxAttempts ||
+			!IsTemporaryErr(err) {
+			return err
+		}
+
+		retryRandL.Lock()
+		wait := delay/2 + time.Duration(retryRand.Int63n(int64(delay/2)+1))
+		retryRandL.Unlock()
+
+		ctx.WithFields(log.Fields{
+			"attempt": attempt,
+			"wait":    wait,
+		}).WithError(err).Debug("retrying after temporary error")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if delay = delay * 2; delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
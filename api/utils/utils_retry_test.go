@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akutz/goof"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+
+	apictx "github.com/codedellemc/libstorage/api/context"
+)
+
+func TestNewRetryOpts(t *testing.T) {
+	opts := NewRetryOpts(-1, "")
+	assert.Equal(t, DefaultRetryMaxAttempts, opts.MaxAttempts)
+	assert.Equal(t, DefaultRetryBaseDelay, opts.BaseDelay)
+
+	opts = NewRetryOpts(5, "1s")
+	assert.Equal(t, 5, opts.MaxAttempts)
+	assert.Equal(t, time.Second, opts.BaseDelay)
+
+	opts = NewRetryOpts(0, "invalid")
+	assert.Equal(t, 0, opts.MaxAttempts)
+	assert.Equal(t, DefaultRetryBaseDelay, opts.BaseDelay)
+}
+
+func TestRetryOps(t *testing.T) {
+	opts := NewRetryOpts(-1, "")
+	for _, op := range RetryableOps {
+		assert.True(t, opts.Retries(op), op)
+	}
+	assert.False(t, opts.Retries("volumeCreate"))
+
+	ops, err := ParseRetryOps([]string{"volumes", " SnapshotInspect"})
+	if assert.NoError(t, err) {
+		opts.Ops = ops
+		assert.True(t, opts.Retries(RetryOpVolumes))
+		assert.True(t, opts.Retries(RetryOpSnapshotInspect))
+		assert.False(t, opts.Retries(RetryOpInstanceInspect))
+	}
+
+	ops, err = ParseRetryOps(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, ops)
+
+	_, err = ParseRetryOps([]string{"volumes", "volumeRemove"})
+	assert.Error(t, err)
+}
+
+func TestRetry(t *testing.T) {
+	opts := &RetryOpts{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	calls := 0
+	err := Retry(apictx.Background(), opts, func() error {
+		calls++
+		if calls < 3 {
+			return NewTemporaryErr(goof.New("throttled"))
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = Retry(apictx.Background(), opts, func() error {
+		calls++
+		return NewTemporaryErr(goof.New("throttled"))
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = Retry(apictx.Background(), opts, func() error {
+		calls++
+		return NewVolumeNotFoundErr("vol-000", nil)
+	})
+	assert.IsType(t, NewVolumeNotFoundErr("vol-000", nil), err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(apictx.Background())
+	opts := &RetryOpts{MaxAttempts: 3, BaseDelay: time.Hour}
+
+	calls := 0
+	err := Retry(apictx.New(ctx), opts, func() error {
+		calls++
+		cancel()
+		return NewTemporaryErr(goof.New("throttled"))
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, calls)
+}
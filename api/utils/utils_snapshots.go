@@ -0,0 +1,217 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+var snapshotExpireTimeRX = regexp.MustCompile(
+	`\s*\[` + types.SnapshotExpireTimeTag + `=(\d+)\]`)
+
+// SnapshotExpireTimeDescription returns the provided description with the
+// expire time appended to it. Drivers that cannot tag snapshots use the
+// result as the new snapshot's description. A zero expire time returns the
+// description unchanged.
+func SnapshotExpireTimeDescription(desc string, expireTime int64) string {
+	if expireTime <= 0 {
+		return desc
+	}
+	return strings.TrimSpace(fmt.Sprintf(
+		"%s [%s=%d]", desc, types.SnapshotExpireTimeTag, expireTime))
+}
+
+// ParseSnapshotExpireTime sets the snapshot's expire time from the provided
+// tags or, if the tags do not include the expire time, from the snapshot's
+// description. The expire time is removed from the description.
+func ParseSnapshotExpireTime(s *types.Snapshot, tags map[string]string) {
+	if v, ok := tags[types.SnapshotExpireTimeTag]; ok {
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			s.ExpireTime = i
+		}
+	}
+	m := snapshotExpireTimeRX.FindStringSubmatch(s.Description)
+	if m == nil {
+		return
+	}
+	if s.ExpireTime == 0 {
+		s.ExpireTime, _ = strconv.ParseInt(m[1], 10, 64)
+	}
+	s.Description = strings.TrimSpace(
+		snapshotExpireTimeRX.ReplaceAllString(s.Description, ""))
+}
+
+// ExpiredSnapshots returns the snapshots whose expire time has passed. If a
+// volume ID is provided then only the volume's snapshots are returned.
+func ExpiredSnapshots(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID string) ([]*types.Snapshot, error) {
+
+	snaps, err := d.Snapshots(ctx, NewStore())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	var expired []*types.Snapshot
+	for _, s := range snaps {
+		if volumeID != "" && s.VolumeID != volumeID {
+			continue
+		}
+		if s.ExpireTime == 0 {
+			ParseSnapshotExpireTime(s, nil)
+		}
+		if s.ExpireTime > 0 && s.ExpireTime <= now {
+			expired = append(expired, s)
+		}
+	}
+	return expired, nil
+}
+
+// SnapshotsOlderThan returns the volume's snapshots that were created more
+// than age ago, sorted from oldest to newest so that a caller may keep the
+// newest snapshots by removing a prefix of the result. A snapshot's age is
+// determined by its StartTime. If any of the volume's snapshots has no
+// StartTime then the storage platform does not report when snapshots are
+// created, and ErrUnsupported is returned.
+func SnapshotsOlderThan(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID string,
+	age time.Duration) ([]*types.Snapshot, error) {
+
+	snaps, err := d.Snapshots(ctx, NewStore())
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-age).Unix()
+	var older []*types.Snapshot
+	for _, s := range snaps {
+		if s.VolumeID != volumeID {
+			continue
+		}
+		if s.StartTime == 0 {
+			return nil, NewUnsupportedErr(d.Name(), "snapshot age filtering")
+		}
+		if s.StartTime < cutoff {
+			older = append(older, s)
+		}
+	}
+	sort.Sort(BySnapshotStartTime(older))
+	return older, nil
+}
+
+// SnapshotSchedulePrefix returns the prefix of the names of the snapshots
+// SnapshotSchedule creates for the volume.
+func SnapshotSchedulePrefix(volumeID string) string {
+	return fmt.Sprintf("%s-scheduled-", volumeID)
+}
+
+// snapshotScheduleErrsSize is the number of errors SnapshotSchedule buffers
+// for a receiver that is not keeping up.
+const snapshotScheduleErrsSize = 16
+
+// SnapshotSchedule starts a goroutine that snapshots the volume every
+// interval until the context is done. After each snapshot the oldest of the
+// volume's scheduled snapshots are removed so that no more than retention
+// remain; a retention less than one keeps all of them. Only snapshots whose
+// names begin with SnapshotSchedulePrefix are removed. An error is returned
+// if the interval is not positive.
+//
+// Errors are received on the returned channel, which is closed once the
+// schedule stops. An error does not stop the schedule. The channel is
+// buffered; errors that occur while the buffer is full are logged and
+// dropped so that a caller that does not receive them cannot stall the
+// schedule.
+func SnapshotSchedule(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID string,
+	interval time.Duration,
+	retention int) (<-chan error, error) {
+
+	if interval <= 0 {
+		return nil, goof.WithField(
+			"interval", interval, "invalid snapshot schedule interval")
+	}
+
+	errs := make(chan error, snapshotScheduleErrsSize)
+	go func() {
+		defer close(errs)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case t := <-ticker.C:
+				for _, err := range snapshotScheduleRun(
+					ctx, d, volumeID, t, retention) {
+					select {
+					case errs <- err:
+					default:
+						ctx.WithField("volumeID", volumeID).WithError(
+							err).Warn("dropped scheduled snapshot error")
+					}
+				}
+			}
+		}
+	}()
+	return errs, nil
+}
+
+func snapshotScheduleRun(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID string,
+	t time.Time,
+	retention int) []error {
+
+	name := fmt.Sprintf("%s%d", SnapshotSchedulePrefix(volumeID), t.Unix())
+	if _, err := d.VolumeSnapshot(ctx, volumeID, name, NewStore()); err != nil {
+		return []error{err}
+	}
+	if retention < 1 {
+		return nil
+	}
+
+	snaps, err := d.Snapshots(ctx, NewStore())
+	if err != nil {
+		return []error{err}
+	}
+
+	prefix := SnapshotSchedulePrefix(volumeID)
+	var scheduled []*types.Snapshot
+	for _, s := range snaps {
+		if s.VolumeID == volumeID && strings.HasPrefix(s.Name, prefix) {
+			scheduled = append(scheduled, s)
+		}
+	}
+	if len(scheduled) <= retention {
+		return nil
+	}
+
+	// the names end with the time at which the snapshots were scheduled, so
+	// they sort from oldest to newest
+	sort.Sort(BySnapshotName(scheduled))
+
+	var errs []error
+	for _, s := range scheduled[:len(scheduled)-retention] {
+		ctx.WithField("snapshotID", s.ID).Debug("pruning scheduled snapshot")
+		if err := d.SnapshotRemove(ctx, s.ID, NewStore()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	netctx "golang.org/x/net/context"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestSnapshotExpireTimeDescription(t *testing.T) {
+	desc := SnapshotExpireTimeDescription("nightly", 1500000000)
+	assert.Equal(t, "nightly [libstorage-expire-time=1500000000]", desc)
+	assert.Equal(t, "nightly", SnapshotExpireTimeDescription("nightly", 0))
+
+	s := &types.Snapshot{Description: desc}
+	ParseSnapshotExpireTime(s, nil)
+	assert.EqualValues(t, 1500000000, s.ExpireTime)
+	assert.Equal(t, "nightly", s.Description)
+
+	s = &types.Snapshot{Description: "weekly"}
+	ParseSnapshotExpireTime(s, map[string]string{
+		types.SnapshotExpireTimeTag: "1600000000",
+	})
+	assert.EqualValues(t, 1600000000, s.ExpireTime)
+	assert.Equal(t, "weekly", s.Description)
+}
+
+func TestExpiredSnapshots(t *testing.T) {
+	past := time.Now().Add(-time.Hour).Unix()
+	future := time.Now().Add(time.Hour).Unix()
+	d := newTestDriver()
+	d.snaps = []*types.Snapshot{
+		{ID: "s1", VolumeID: "v1", ExpireTime: past},
+		{ID: "s2", VolumeID: "v1", ExpireTime: future},
+		{ID: "s3", VolumeID: "v1"},
+		{ID: "s4", VolumeID: "v2",
+			Description: SnapshotExpireTimeDescription("", past)},
+	}
+
+	snaps, err := ExpiredSnapshots(context.Background(), d, "")
+	assert.NoError(t, err)
+	if assert.Len(t, snaps, 2) {
+		assert.Equal(t, "s1", snaps[0].ID)
+		assert.Equal(t, "s4", snaps[1].ID)
+	}
+
+	snaps, err = ExpiredSnapshots(context.Background(), d, "v2")
+	assert.NoError(t, err)
+	if assert.Len(t, snaps, 1) {
+		assert.Equal(t, "s4", snaps[0].ID)
+	}
+}
+
+// newTestScheduleDriver returns a driver with the volume v1.
+func newTestScheduleDriver() *testDriver {
+	d := newTestDriver()
+	d.vols = []*types.Volume{{ID: "v1"}}
+	return d
+}
+
+func TestSnapshotsOlderThan(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	d := newTestDriver()
+	d.snaps = []*types.Snapshot{
+		{ID: "s1", VolumeID: "v1", StartTime: now.Add(-10 * day).Unix()},
+		{ID: "s2", VolumeID: "v1", StartTime: now.Add(-1 * day).Unix()},
+		{ID: "s3", VolumeID: "v1", StartTime: now.Add(-30 * day).Unix()},
+		{ID: "s4", VolumeID: "v2", StartTime: now.Add(-30 * day).Unix()},
+		{ID: "s5", VolumeID: "v3"},
+	}
+	ctx := context.Background()
+
+	snaps, err := SnapshotsOlderThan(ctx, d, "v1", 7*day)
+	assert.NoError(t, err)
+	if assert.Len(t, snaps, 2) {
+		assert.Equal(t, "s3", snaps[0].ID)
+		assert.Equal(t, "s1", snaps[1].ID)
+	}
+
+	snaps, err = SnapshotsOlderThan(ctx, d, "v1", 90*day)
+	assert.NoError(t, err)
+	assert.Len(t, snaps, 0)
+
+	_, err = SnapshotsOlderThan(ctx, d, "v3", day)
+	assert.IsType(t, &types.ErrUnsupported{}, err)
+}
+
+func TestSnapshotScheduleRun(t *testing.T) {
+	d := newTestScheduleDriver()
+	d.snaps = []*types.Snapshot{{ID: "manual", Name: "v1-manual",
+		VolumeID: "v1"}}
+	ctx := context.Background()
+	start := time.Unix(1500000000, 0)
+
+	for i := 0; i < 4; i++ {
+		assert.Empty(t, snapshotScheduleRun(
+			ctx, d, "v1", start.Add(time.Duration(i)*time.Hour), 2))
+	}
+
+	if assert.Len(t, d.snaps, 3) {
+		assert.Equal(t, "manual", d.snaps[0].ID)
+		assert.Equal(t, "v1-scheduled-1500007200", d.snaps[1].Name)
+		assert.Equal(t, "v1-scheduled-1500010800", d.snaps[2].Name)
+	}
+}
+
+func TestSnapshotSchedule(t *testing.T) {
+	d := newTestScheduleDriver()
+	cctx, cancel := netctx.WithCancel(context.Background())
+	ctx := context.New(cctx)
+	errs, err := SnapshotSchedule(ctx, d, "v1", 10*time.Millisecond, 0)
+	assert.NoError(t, err)
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	snaps, _ := d.Snapshots(ctx, nil)
+	assert.NotEmpty(t, snaps)
+}
+
+func TestSnapshotScheduleInvalidInterval(t *testing.T) {
+	d := newTestScheduleDriver()
+	ctx := context.Background()
+	for _, interval := range []time.Duration{0, -time.Second} {
+		errs, err := SnapshotSchedule(ctx, d, "v1", interval, 0)
+		assert.Error(t, err)
+		assert.Nil(t, errs)
+	}
+}
+
+func TestSnapshotScheduleUnreceivedErrors(t *testing.T) {
+	d := newTestScheduleDriver()
+	d.before = func(ctx types.Context, op, id string) error {
+		if op == "VolumeSnapshot" {
+			return fmt.Errorf("snapshot of %s failed", id)
+		}
+		return nil
+	}
+	cctx, cancel := netctx.WithCancel(context.Background())
+	ctx := context.New(cctx)
+	errs, err := SnapshotSchedule(ctx, d, "v1", time.Millisecond, 0)
+	assert.NoError(t, err)
+
+	// nothing receives the errors, yet the schedule keeps running
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if d.count("VolumeSnapshot") > snapshotScheduleErrsSize+2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	assert.True(t, d.count("VolumeSnapshot") > snapshotScheduleErrsSize+2)
+
+	var n int
+	for err := range errs {
+		assert.Error(t, err)
+		n++
+	}
+	assert.Equal(t, snapshotScheduleErrsSize, n)
+}
@@ -32,3 +32,39 @@ func SortByString(strings []string) []string {
 	sort.Sort(ByString(strings))
 	return strings
 }
+
+// BySnapshotID implements sort.Interface for []*types.Snapshot based on the
+// ID field.
+type BySnapshotID []*types.Snapshot
+
+func (a BySnapshotID) Len() int           { return len(a) }
+func (a BySnapshotID) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a BySnapshotID) Less(i, j int) bool { return a[i].ID < a[j].ID }
+
+// SortSnapshotByID sorts the snapshots by their IDs.
+func SortSnapshotByID(snapshots []*types.Snapshot) []*types.Snapshot {
+	sort.Sort(BySnapshotID(snapshots))
+	return snapshots
+}
+
+// BySnapshotName implements sort.Interface for []*types.Snapshot based on
+// the Name field.
+type BySnapshotName []*types.Snapshot
+
+func (a BySnapshotName) Len() int           { return len(a) }
+func (a BySnapshotName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a BySnapshotName) Less(i, j int) bool { return a[i].Name < a[j].Name }
+
+// BySnapshotStartTime implements sort.Interface for []*types.Snapshot based
+// on the StartTime field. Snapshots with the same start time are ordered by
+// ID.
+type BySnapshotStartTime []*types.Snapshot
+
+func (a BySnapshotStartTime) Len() int      { return len(a) }
+func (a BySnapshotStartTime) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a BySnapshotStartTime) Less(i, j int) bool {
+	if a[i].StartTime == a[j].StartTime {
+		return a[i].ID < a[j].ID
+	}
+	return a[i].StartTime < a[j].StartTime
+}
@@ -0,0 +1,587 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// testNextDevice is the key with which testDriver records the attachments
+// that select the next available device.
+const testNextDevice = "nextDevice"
+
+// testDriver is a storage driver that keeps its volumes and snapshots in
+// memory. New volumes and snapshots are identified by their names. Each
+// operation is counted in calls, waits for delay while the volume or
+// snapshot is recorded as busy, and then fails with the error returned by
+// before if it is set. The driver implements only the StorageDriver
+// interface; testOptionalDriver adds the optional interfaces.
+type testDriver struct {
+	sync.Mutex
+	vols      []*types.Volume
+	snaps     []*types.Snapshot
+	instance  *types.Instance
+	volTypes  []*types.VolumeType
+	maxVols   int
+	closed    bool
+	delay     time.Duration
+	before    func(ctx types.Context, op, id string) error
+	calls     map[string]int
+	active    map[string]int
+	maxActive map[string]int
+}
+
+func newTestDriver() *testDriver {
+	return &testDriver{
+		instance: &types.Instance{
+			InstanceID: &types.InstanceID{ID: "i-000", Driver: "test"},
+		},
+		calls:     map[string]int{},
+		active:    map[string]int{},
+		maxActive: map[string]int{},
+	}
+}
+
+// call records a call to the operation on the IDs, the first of which is
+// passed to before.
+func (d *testDriver) call(
+	ctx types.Context, op string, ids ...string) error {
+
+	d.Lock()
+	d.calls[op]++
+	for _, id := range ids {
+		d.active[id]++
+		if d.active[id] > d.maxActive[id] {
+			d.maxActive[id] = d.active[id]
+		}
+	}
+	d.Unlock()
+
+	defer func() {
+		d.Lock()
+		for _, id := range ids {
+			d.active[id]--
+		}
+		d.Unlock()
+	}()
+
+	if d.delay > 0 {
+		time.Sleep(d.delay)
+	}
+	if d.before != nil {
+		var id string
+		if len(ids) > 0 {
+			id = ids[0]
+		}
+		return d.before(ctx, op, id)
+	}
+	return nil
+}
+
+// count returns the number of times the operation was called.
+func (d *testDriver) count(op string) int {
+	d.Lock()
+	defer d.Unlock()
+	return d.calls[op]
+}
+
+// busiest returns the greatest number of concurrent operations on the ID.
+func (d *testDriver) busiest(id string) int {
+	d.Lock()
+	defer d.Unlock()
+	return d.maxActive[id]
+}
+
+func (d *testDriver) volume(volumeID string) (int, error) {
+	for i, v := range d.vols {
+		if v.ID == volumeID {
+			return i, nil
+		}
+	}
+	return -1, NewVolumeNotFoundErr(volumeID, nil)
+}
+
+func (d *testDriver) snapshot(snapshotID string) (int, error) {
+	for i, s := range d.snaps {
+		if s.ID == snapshotID {
+			return i, nil
+		}
+	}
+	return -1, NewSnapshotNotFoundErr(snapshotID, nil)
+}
+
+func (d *testDriver) addVolume(v *types.Volume) (*types.Volume, error) {
+	if _, err := d.volume(v.ID); err == nil {
+		return nil, NewAlreadyExistsErr(v.ID)
+	}
+	d.vols = append(d.vols, v)
+	return copyVolume(v), nil
+}
+
+func (d *testDriver) addSnapshot(s *types.Snapshot) (*types.Snapshot, error) {
+	if _, err := d.snapshot(s.ID); err == nil {
+		return nil, NewAlreadyExistsErr(s.ID)
+	}
+	d.snaps = append(d.snaps, s)
+	return copySnapshot(s), nil
+}
+
+// deviceVolume returns the ID of the volume attached to the device.
+func (d *testDriver) deviceVolume(deviceName string) (string, bool) {
+	for _, v := range d.vols {
+		for _, a := range v.Attachments {
+			if a.DeviceName == deviceName {
+				return v.ID, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (d *testDriver) Name() string {
+	return "test"
+}
+
+func (d *testDriver) Init(ctx types.Context, config gofig.Config) error {
+	return nil
+}
+
+func (d *testDriver) NextDeviceInfo(
+	ctx types.Context) (*types.NextDeviceInfo, error) {
+	return &types.NextDeviceInfo{Prefix: "xvd", Pattern: `\w`}, nil
+}
+
+func (d *testDriver) Type(ctx types.Context) (types.StorageType, error) {
+	return types.Block, nil
+}
+
+func (d *testDriver) InstanceInspect(
+	ctx types.Context,
+	opts types.Store) (*types.Instance, error) {
+
+	if err := d.call(ctx, "InstanceInspect"); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	if d.instance == nil {
+		return nil, nil
+	}
+	i := *d.instance
+	return &i, nil
+}
+
+func (d *testDriver) Volumes(
+	ctx types.Context,
+	opts *types.VolumesOpts) ([]*types.Volume, error) {
+
+	if err := d.call(ctx, "Volumes"); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	vols := make([]*types.Volume, len(d.vols))
+	for i, v := range d.vols {
+		vols[i] = copyVolume(v)
+	}
+	return vols, nil
+}
+
+func (d *testDriver) VolumeInspect(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeInspectOpts) (*types.Volume, error) {
+
+	if err := d.call(ctx, "VolumeInspect", volumeID); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	i, err := d.volume(volumeID)
+	if err != nil {
+		return nil, err
+	}
+	return copyVolume(d.vols[i]), nil
+}
+
+func (d *testDriver) VolumeCreate(
+	ctx types.Context,
+	name string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	if err := d.call(ctx, "VolumeCreate", name); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	return d.addVolume(newTestVolume(name, opts))
+}
+
+func (d *testDriver) VolumeCreateFromSnapshot(
+	ctx types.Context,
+	snapshotID, volumeName string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	err := d.call(ctx, "VolumeCreateFromSnapshot", volumeName, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	i, err := d.snapshot(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	v := newTestVolume(volumeName, opts)
+	if v.Size == 0 {
+		v.Size = d.snaps[i].VolumeSize
+	}
+	return d.addVolume(v)
+}
+
+func (d *testDriver) VolumeCopy(
+	ctx types.Context,
+	volumeID, volumeName string,
+	opts types.Store) (*types.Volume, error) {
+
+	if err := d.call(ctx, "VolumeCopy", volumeID); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	i, err := d.volume(volumeID)
+	if err != nil {
+		return nil, err
+	}
+	v := copyVolume(d.vols[i])
+	v.ID, v.Name, v.Attachments = volumeName, volumeName, nil
+	return d.addVolume(v)
+}
+
+func (d *testDriver) VolumeSnapshot(
+	ctx types.Context,
+	volumeID, snapshotName string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	if err := d.call(ctx, "VolumeSnapshot", volumeID); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	i, err := d.volume(volumeID)
+	if err != nil {
+		return nil, err
+	}
+	return d.addSnapshot(&types.Snapshot{
+		ID:         snapshotName,
+		Name:       snapshotName,
+		VolumeID:   volumeID,
+		VolumeSize: d.vols[i].Size,
+	})
+}
+
+func (d *testDriver) VolumeRemove(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeRemoveOpts) error {
+
+	if err := d.call(ctx, "VolumeRemove", volumeID); err != nil {
+		return err
+	}
+	d.Lock()
+	defer d.Unlock()
+	i, err := d.volume(volumeID)
+	if err != nil {
+		return err
+	}
+	d.vols = append(d.vols[:i], d.vols[i+1:]...)
+	return nil
+}
+
+// VolumeAttach attaches the volume to the requested device, or else to the
+// first device that is not in use. Attaching a volume to the context's
+// instance again replaces its attachment.
+func (d *testDriver) VolumeAttach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeAttachOpts) (*types.Volume, string, error) {
+
+	ids := []string{volumeID}
+	if opts == nil || opts.NextDevice == nil {
+		ids = append(ids, testNextDevice)
+	}
+	if err := d.call(ctx, "VolumeAttach", ids...); err != nil {
+		return nil, "", err
+	}
+	d.Lock()
+	defer d.Unlock()
+	i, err := d.volume(volumeID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var deviceName string
+	if len(ids) == 1 {
+		deviceName = *opts.NextDevice
+		if v, ok := d.deviceVolume(deviceName); ok && v != volumeID {
+			return nil, "", goof.WithFields(goof.Fields{
+				"deviceName": deviceName,
+				"volumeID":   v,
+			}, "device in use")
+		}
+	} else {
+		for c := 'f'; c <= 'z'; c++ {
+			name := fmt.Sprintf("/dev/xvd%c", c)
+			if _, ok := d.deviceVolume(name); !ok {
+				deviceName = name
+				break
+			}
+		}
+	}
+
+	iid, _ := context.InstanceID(ctx)
+	v := d.vols[i]
+	v.Attachments = append(removeTestAttachments(v.Attachments, iid),
+		&types.VolumeAttachment{
+			VolumeID:   volumeID,
+			DeviceName: deviceName,
+			InstanceID: iid,
+		})
+	return copyVolume(v), deviceName, nil
+}
+
+// VolumeDetach detaches the volume from the context's instance, or from
+// every instance if the context does not have an instance ID.
+func (d *testDriver) VolumeDetach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeDetachOpts) (*types.Volume, error) {
+
+	if err := d.call(ctx, "VolumeDetach", volumeID); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	i, err := d.volume(volumeID)
+	if err != nil {
+		return nil, err
+	}
+	iid, _ := context.InstanceID(ctx)
+	v := d.vols[i]
+	if iid == nil {
+		v.Attachments = nil
+	} else {
+		v.Attachments = removeTestAttachments(v.Attachments, iid)
+	}
+	return copyVolume(v), nil
+}
+
+func (d *testDriver) Snapshots(
+	ctx types.Context,
+	opts types.Store) ([]*types.Snapshot, error) {
+
+	if err := d.call(ctx, "Snapshots"); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	snaps := make([]*types.Snapshot, len(d.snaps))
+	for i, s := range d.snaps {
+		snaps[i] = copySnapshot(s)
+	}
+	return snaps, nil
+}
+
+func (d *testDriver) SnapshotInspect(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	if err := d.call(ctx, "SnapshotInspect", snapshotID); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	i, err := d.snapshot(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	return copySnapshot(d.snaps[i]), nil
+}
+
+func (d *testDriver) SnapshotCopy(
+	ctx types.Context,
+	snapshotID, snapshotName, destinationID string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	if err := d.call(ctx, "SnapshotCopy", snapshotID); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	return d.copySnapshot(snapshotID, snapshotName)
+}
+
+func (d *testDriver) copySnapshot(
+	snapshotID, snapshotName string) (*types.Snapshot, error) {
+
+	i, err := d.snapshot(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	s := copySnapshot(d.snaps[i])
+	s.ID, s.Name = snapshotName, snapshotName
+	return d.addSnapshot(s)
+}
+
+func (d *testDriver) SnapshotRemove(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) error {
+
+	if err := d.call(ctx, "SnapshotRemove", snapshotID); err != nil {
+		return err
+	}
+	d.Lock()
+	defer d.Unlock()
+	i, err := d.snapshot(snapshotID)
+	if err != nil {
+		return err
+	}
+	d.snaps = append(d.snaps[:i], d.snaps[i+1:]...)
+	return nil
+}
+
+// testOptionalDriver is a testDriver that also implements the optional
+// storage driver interfaces the package's tests exercise.
+type testOptionalDriver struct {
+	*testDriver
+}
+
+func (d *testOptionalDriver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	size int64,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	if err := d.call(ctx, "VolumeResize", volumeID); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	i, err := d.volume(volumeID)
+	if err != nil {
+		return nil, err
+	}
+	d.vols[i].Size = size
+	return copyVolume(d.vols[i]), nil
+}
+
+func (d *testOptionalDriver) VolumeRename(
+	ctx types.Context,
+	volumeID, newName string,
+	opts types.Store) (*types.Volume, error) {
+
+	if err := d.call(ctx, "VolumeRename", volumeID); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	i, err := d.volume(volumeID)
+	if err != nil {
+		return nil, err
+	}
+	d.vols[i].Name = newName
+	return copyVolume(d.vols[i]), nil
+}
+
+func (d *testOptionalDriver) VolumeTypes(
+	ctx types.Context,
+	opts types.Store) ([]*types.VolumeType, error) {
+
+	if err := d.call(ctx, "VolumeTypes"); err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	return copyVolumeTypes(d.volTypes), nil
+}
+
+func (d *testOptionalDriver) MaxVolumeCount(
+	ctx types.Context,
+	opts types.Store) (int, error) {
+	return d.maxVols, nil
+}
+
+// SnapshotCopyIncremental copies the snapshot and records the base snapshot
+// in the copy's fields.
+func (d *testOptionalDriver) SnapshotCopyIncremental(
+	ctx types.Context,
+	snapshotID, snapshotName, destinationID, baseSnapshotID string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	err := d.call(ctx, "SnapshotCopyIncremental", snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	s, err := d.copySnapshot(snapshotID, snapshotName)
+	if err != nil {
+		return nil, err
+	}
+	s.Fields = map[string]string{"baseSnapshotID": baseSnapshotID}
+	return s, nil
+}
+
+func (d *testOptionalDriver) Close() error {
+	d.Lock()
+	defer d.Unlock()
+	d.closed = true
+	return nil
+}
+
+func newTestVolume(name string, opts *types.VolumeCreateOpts) *types.Volume {
+	v := &types.Volume{ID: name, Name: name}
+	if opts == nil {
+		return v
+	}
+	if opts.Size != nil {
+		v.Size = *opts.Size
+	}
+	if opts.IOPS != nil {
+		v.IOPS = *opts.IOPS
+	}
+	if opts.Type != nil {
+		v.Type = *opts.Type
+	}
+	if opts.AvailabilityZone != nil {
+		v.AvailabilityZone = *opts.AvailabilityZone
+	}
+	v.Tags = copyStringMap(opts.Tags)
+	return v
+}
+
+// removeTestAttachments returns the attachments that are not to the instance.
+func removeTestAttachments(
+	atts []*types.VolumeAttachment,
+	iid *types.InstanceID) []*types.VolumeAttachment {
+
+	var keep []*types.VolumeAttachment
+	for _, a := range atts {
+		if iid == nil && a.InstanceID == nil {
+			continue
+		}
+		if iid != nil && a.InstanceID != nil && a.InstanceID.ID == iid.ID {
+			continue
+		}
+		keep = append(keep, a)
+	}
+	return keep
+}
@@ -0,0 +1,1040 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// VolumeResize grows a volume to the specified size using the provided
+// driver. If the driver does not implement StorageDriverVolResize then
+// ErrUnsupported is returned. An error is also returned if the new size is
+// not greater than the volume's current size.
+func VolumeResize(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID string,
+	size int64,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	rd, ok := underlying(d).(types.StorageDriverVolResize)
+	if !ok {
+		return nil, NewUnsupportedErr(d.Name(), "resize")
+	}
+
+	if opts == nil {
+		opts = &types.VolumeResizeOpts{Opts: NewStore()}
+	}
+
+	vol, err := d.VolumeInspect(
+		ctx, volumeID, &types.VolumeInspectOpts{Opts: opts.Opts})
+	if err != nil {
+		return nil, err
+	}
+
+	if size <= vol.Size {
+		return nil, goof.WithFields(goof.Fields{
+			"volumeID": volumeID,
+			"size":     vol.Size,
+			"newSize":  size,
+		}, "new volume size must be greater than current size")
+	}
+
+	unlock, err := lockVolume(ctx, d, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	defer invalidateVolume(d, volumeID)
+	return rd.VolumeResize(ctx, volumeID, size, opts)
+}
+
+// VolumeClone creates a new volume backed by the data of an existing volume.
+// If the driver implements StorageDriverVolClone then the driver's native
+// clone is used. Otherwise the source volume is snapshotted, a new volume is
+// created from the snapshot, and the intermediate snapshot is removed. The
+// intermediate snapshot is removed even if the context is cancelled part of
+// the way through the operation.
+func VolumeClone(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID, volumeName string,
+	opts *types.VolumeCloneOpts) (*types.Volume, error) {
+
+	if opts == nil {
+		opts = &types.VolumeCloneOpts{Opts: NewStore()}
+	}
+
+	if cd, ok := underlying(d).(types.StorageDriverVolClone); ok {
+		return cd.VolumeClone(ctx, volumeID, volumeName, opts)
+	}
+
+	srcVol, err := d.VolumeInspect(
+		ctx, volumeID, &types.VolumeInspectOpts{Opts: opts.Opts})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	snapName := fmt.Sprintf("%s-clone", volumeName)
+	snap, err := d.VolumeSnapshot(ctx, volumeID, snapName, opts.Opts)
+	if err != nil {
+		return nil, goof.WithFieldE(
+			"volumeID", volumeID, "error snapshotting clone source", err)
+	}
+
+	defer func() {
+		cctx, cancel := cleanupContext(ctx)
+		defer cancel()
+		if err := d.SnapshotRemove(cctx, snap.ID, opts.Opts); err != nil {
+			ctx.WithField("snapshotID", snap.ID).WithError(err).Warn(
+				"error removing intermediate clone snapshot")
+		}
+	}()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	createOpts := &types.VolumeCreateOpts{
+		AvailabilityZone: opts.AvailabilityZone,
+		IOPS:             opts.IOPS,
+		Type:             opts.Type,
+		Size:             &srcVol.Size,
+		Opts:             opts.Opts,
+	}
+	if createOpts.IOPS == nil && srcVol.IOPS > 0 {
+		createOpts.IOPS = &srcVol.IOPS
+	}
+	if createOpts.Type == nil && srcVol.Type != "" {
+		createOpts.Type = &srcVol.Type
+	}
+	if createOpts.AvailabilityZone == nil && srcVol.AvailabilityZone != "" {
+		createOpts.AvailabilityZone = &srcVol.AvailabilityZone
+	}
+
+	return d.VolumeCreateFromSnapshot(ctx, snap.ID, volumeName, createOpts)
+}
+
+// VolumeSetTags adds the provided tags to a volume using the provided driver.
+// If the driver does not implement StorageDriverVolTags then ErrUnsupported
+// is returned.
+func VolumeSetTags(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID string,
+	tags map[string]string,
+	opts types.Store) error {
+
+	td, ok := underlying(d).(types.StorageDriverVolTags)
+	if !ok {
+		return NewUnsupportedErr(d.Name(), "tags")
+	}
+	if opts == nil {
+		opts = NewStore()
+	}
+	unlock, err := lockVolume(ctx, d, volumeID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	defer invalidateVolume(d, volumeID)
+	return td.VolumeSetTags(ctx, volumeID, tags, opts)
+}
+
+// VolumeCreate creates a volume with the provided name and options. If the
+// options request idempotency by name and a volume with the same name already
+// exists, the existing volume is returned if its properties match the
+// requested properties. Otherwise an ErrConflict error is returned.
+//
+// The requested type, size, and IOPS are validated with ValidateVolumeType
+// before the driver is called.
+func VolumeCreate(
+	ctx types.Context,
+	d types.StorageDriver,
+	name string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	if opts == nil {
+		opts = &types.VolumeCreateOpts{Opts: NewStore()}
+	}
+
+	if err := ValidateVolumeType(ctx, d, opts); err != nil {
+		return nil, err
+	}
+
+	if !opts.IdempotentByName {
+		return d.VolumeCreate(ctx, name, opts)
+	}
+
+	vols, err := FindVolumes(
+		ctx, d, &types.VolumeFilter{Name: name},
+		&types.VolumesOpts{Opts: opts.Opts})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vols) == 0 {
+		return d.VolumeCreate(ctx, name, opts)
+	}
+
+	for _, v := range vols {
+		if len(volumeCreateConflicts(v, opts)) == 0 {
+			ctx.WithField("volumeID", v.ID).Debug(
+				"returning existing volume with same name")
+			return v, nil
+		}
+	}
+
+	return nil, NewConflictErr(name, volumeCreateConflicts(vols[0], opts))
+}
+
+// volumeCreateConflicts returns the names of the requested properties that
+// differ from the volume's properties. Properties that are not requested
+// never conflict.
+func volumeCreateConflicts(
+	v *types.Volume, opts *types.VolumeCreateOpts) []string {
+
+	var conflicts []string
+	if opts.AvailabilityZone != nil &&
+		*opts.AvailabilityZone != v.AvailabilityZone {
+		conflicts = append(conflicts, "availabilityZone")
+	}
+	if opts.Encrypted != nil && *opts.Encrypted != v.Encrypted {
+		conflicts = append(conflicts, "encrypted")
+	}
+	if opts.EncryptionKey != nil && *opts.EncryptionKey != v.EncryptionKey {
+		conflicts = append(conflicts, "encryptionKey")
+	}
+	if opts.IOPS != nil && *opts.IOPS != v.IOPS {
+		conflicts = append(conflicts, "iops")
+	}
+	if opts.Throughput != nil && *opts.Throughput != v.Throughput {
+		conflicts = append(conflicts, "throughput")
+	}
+	if opts.Size != nil && *opts.Size != v.Size {
+		conflicts = append(conflicts, "size")
+	}
+	if opts.Type != nil && *opts.Type != v.Type {
+		conflicts = append(conflicts, "type")
+	}
+	for k, tv := range opts.Tags {
+		if vv, ok := v.Tags[k]; !ok || vv != tv {
+			conflicts = append(conflicts, "tags")
+			break
+		}
+	}
+	return conflicts
+}
+
+// VolumesByTag returns the volumes with a tag that matches the provided key
+// and value.
+func VolumesByTag(
+	ctx types.Context,
+	d types.StorageDriver,
+	key, value string,
+	opts *types.VolumesOpts) ([]*types.Volume, error) {
+
+	if opts == nil {
+		opts = &types.VolumesOpts{Opts: NewStore()}
+	}
+
+	vols, err := d.Volumes(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*types.Volume
+	for _, v := range vols {
+		if tv, ok := v.Tags[key]; ok && tv == value {
+			matches = append(matches, v)
+		}
+	}
+	return matches, nil
+}
+
+// FindVolumes returns the volumes that match the provided filter. An empty
+// filter returns all volumes. If the driver implements StorageDriverVolFind
+// then the filter is pushed to the storage platform, otherwise all volumes
+// are listed and the filter is applied locally.
+func FindVolumes(
+	ctx types.Context,
+	d types.StorageDriver,
+	filter *types.VolumeFilter,
+	opts *types.VolumesOpts) ([]*types.Volume, error) {
+
+	if opts == nil {
+		opts = &types.VolumesOpts{Opts: NewStore()}
+	}
+
+	if filter.IsEmpty() {
+		return d.Volumes(ctx, opts)
+	}
+
+	var (
+		vols []*types.Volume
+		err  error
+	)
+	if fd, ok := underlying(d).(types.StorageDriverVolFind); ok {
+		vols, err = fd.FindVolumes(ctx, filter, opts)
+	} else {
+		vols, err = d.Volumes(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*types.Volume
+	for _, v := range vols {
+		if filter.Matches(v) {
+			matches = append(matches, v)
+		}
+	}
+	return matches, nil
+}
+
+// VolumesByID inspects the volumes with the specified IDs. Duplicate IDs are
+// ignored and the volumes are returned in the order in which their IDs first
+// appear. Volumes that cannot be found are omitted from the result. If the
+// driver implements StorageDriverVolsByID then the volumes are inspected with
+// a single call, otherwise they are inspected concurrently by a bounded pool
+// of workers.
+func VolumesByID(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeIDs []string,
+	opts *types.VolumeInspectOpts) ([]*types.Volume, error) {
+
+	if opts == nil {
+		opts = &types.VolumeInspectOpts{Opts: NewStore()}
+	}
+
+	var (
+		ids     []string
+		idIndex = map[string]int{}
+	)
+	for _, id := range volumeIDs {
+		if _, ok := idIndex[id]; ok {
+			continue
+		}
+		idIndex[id] = len(ids)
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	results := make([]*types.Volume, len(ids))
+
+	if bd, ok := underlying(d).(types.StorageDriverVolsByID); ok {
+		vols, err := bd.VolumesByID(ctx, ids, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vols {
+			if v == nil {
+				continue
+			}
+			if i, ok := idIndex[v.ID]; ok {
+				results[i] = v
+			}
+		}
+		return compactVolumes(results), nil
+	}
+
+	var (
+		errOnce sync.Once
+		ferr    error
+	)
+
+	if _, err := forEachIndex(ctx, len(ids), bulkWorkers, func(i int) {
+		v, err := d.VolumeInspect(ctx, ids[i], opts)
+		if err != nil {
+			if !IsNotFoundErr(err) {
+				errOnce.Do(func() { ferr = err })
+			}
+			return
+		}
+		results[i] = v
+	}); err != nil {
+		return nil, err
+	}
+
+	if ferr != nil {
+		return nil, ferr
+	}
+	return compactVolumes(results), nil
+}
+
+func compactVolumes(vols []*types.Volume) []*types.Volume {
+	compacted := make([]*types.Volume, 0, len(vols))
+	for _, v := range vols {
+		if v != nil {
+			compacted = append(compacted, v)
+		}
+	}
+	return compacted
+}
+
+// maxVolumeStatusPollInterval is the longest WaitForVolumeStatus will wait
+// between inspections of a volume.
+const maxVolumeStatusPollInterval = 30 * time.Second
+
+// WaitForVolumeStatus polls a volume until its status matches the desired
+// status or the context is done. The interval between polls starts at
+// pollInterval and grows by half after each poll, up to a maximum of thirty
+// seconds. If the context is done before the volume reaches the desired
+// status then the last observed volume is returned along with ErrTimedOut.
+func WaitForVolumeStatus(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID, desiredStatus string,
+	pollInterval time.Duration) (*types.Volume, error) {
+
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+
+	var (
+		vol      *types.Volume
+		interval = pollInterval
+		opts     = &types.VolumeInspectOpts{Opts: NewStore()}
+	)
+
+	for {
+		v, err := d.VolumeInspect(ctx, volumeID, opts)
+		if err != nil {
+			return vol, err
+		}
+		vol = v
+
+		if vol != nil && vol.Status == desiredStatus {
+			return vol, nil
+		}
+
+		ctx.WithFields(log.Fields{
+			"volumeID":      volumeID,
+			"desiredStatus": desiredStatus,
+			"interval":      interval,
+		}).Debug("waiting for volume status")
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			fields := log.Fields{
+				"volumeID":      volumeID,
+				"desiredStatus": desiredStatus,
+			}
+			if vol != nil {
+				fields["status"] = vol.Status
+			}
+			ctx.WithFields(fields).Debug(
+				"timed out waiting for volume status")
+			return vol, types.ErrTimedOut
+		case <-timer.C:
+		}
+
+		if interval = interval + interval/2; interval > maxVolumeStatusPollInterval {
+			interval = maxVolumeStatusPollInterval
+		}
+	}
+}
+
+// SnapshotRestore rolls a volume back to the contents of a snapshot. The
+// target volume must not be attached to any instance. If the driver
+// implements StorageDriverSnapRestore then the snapshot is restored onto the
+// existing volume. Otherwise a replacement volume with the same name and
+// properties as the target volume is created from the snapshot. An
+// ErrUnsupported error is returned if the driver can do neither.
+func SnapshotRestore(
+	ctx types.Context,
+	d types.StorageDriver,
+	snapshotID, volumeID string,
+	opts types.Store) (*types.Volume, error) {
+
+	if opts == nil {
+		opts = NewStore()
+	}
+
+	vol, err := d.VolumeInspect(
+		ctx, volumeID,
+		&types.VolumeInspectOpts{Attachments: types.VolAttReq, Opts: opts})
+	if err != nil {
+		return nil, err
+	}
+	if vol == nil {
+		return nil, NewNotFoundError(volumeID)
+	}
+	if len(vol.Attachments) > 0 {
+		return nil, NewVolumeAttachedErr(volumeID)
+	}
+
+	if rd, ok := underlying(d).(types.StorageDriverSnapRestore); ok {
+		unlock, err := lockVolume(ctx, d, volumeID)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+		defer invalidateVolume(d, volumeID)
+		return rd.SnapshotRestore(ctx, snapshotID, volumeID, opts)
+	}
+
+	createOpts := &types.VolumeCreateOpts{Opts: opts}
+	if vol.Size > 0 {
+		createOpts.Size = &vol.Size
+	}
+	if vol.IOPS > 0 {
+		createOpts.IOPS = &vol.IOPS
+	}
+	if vol.Type != "" {
+		createOpts.Type = &vol.Type
+	}
+	if vol.AvailabilityZone != "" {
+		createOpts.AvailabilityZone = &vol.AvailabilityZone
+	}
+	if len(vol.Tags) > 0 {
+		createOpts.Tags = vol.Tags
+	}
+
+	newVol, err := d.VolumeCreateFromSnapshot(
+		ctx, snapshotID, vol.Name, createOpts)
+	if err == types.ErrNotImplemented {
+		return nil, NewUnsupportedErr(d.Name(), "snapshot restore")
+	}
+	return newVol, err
+}
+
+// VolumeCreateFromSnapshot creates a volume from a snapshot with the
+// specified size. The snapshot is inspected first and an error is returned if
+// the size is less than the size of the snapshot's volume, instead of leaving
+// the storage platform to reject the request or to silently ignore the size.
+// A size less than or equal to zero results in a volume the size of the
+// snapshot's volume. The type, IOPS, and other properties of the new volume
+// are taken from opts and validated with ValidateVolumeType.
+func VolumeCreateFromSnapshot(
+	ctx types.Context,
+	d types.StorageDriver,
+	snapshotID, name string,
+	size int64,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	if opts == nil {
+		opts = &types.VolumeCreateOpts{}
+	}
+	if opts.Opts == nil {
+		opts.Opts = NewStore()
+	}
+
+	snap, err := d.SnapshotInspect(ctx, snapshotID, opts.Opts)
+	if err != nil {
+		return nil, err
+	}
+	if snap == nil {
+		return nil, NewSnapshotNotFoundErr(snapshotID, nil)
+	}
+
+	if size <= 0 {
+		size = snap.VolumeSize
+	} else if size < snap.VolumeSize {
+		return nil, goof.WithFields(goof.Fields{
+			"snapshotID":   snapshotID,
+			"snapshotSize": snap.VolumeSize,
+			"size":         size,
+		}, "volume size must not be less than snapshot size")
+	}
+
+	createOpts := *opts
+	if size > 0 {
+		createOpts.Size = &size
+	}
+	if err := ValidateVolumeType(ctx, d, &createOpts); err != nil {
+		return nil, err
+	}
+	return d.VolumeCreateFromSnapshot(ctx, snapshotID, name, &createOpts)
+}
+
+// VolumeRename changes the name of a volume. If the driver does not implement
+// StorageDriverVolRename then ErrUnsupported is returned.
+func VolumeRename(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID, newName string,
+	opts types.Store) (*types.Volume, error) {
+
+	rd, ok := underlying(d).(types.StorageDriverVolRename)
+	if !ok {
+		return nil, NewUnsupportedErr(d.Name(), "volume rename")
+	}
+	if opts == nil {
+		opts = NewStore()
+	}
+	unlock, err := lockVolume(ctx, d, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	defer invalidateVolume(d, volumeID)
+	return rd.VolumeRename(ctx, volumeID, newName, opts)
+}
+
+// SnapshotRename changes the name of a snapshot. If the driver does not
+// implement StorageDriverSnapRename then ErrUnsupported is returned.
+func SnapshotRename(
+	ctx types.Context,
+	d types.StorageDriver,
+	snapshotID, newName string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	rd, ok := underlying(d).(types.StorageDriverSnapRename)
+	if !ok {
+		return nil, NewUnsupportedErr(d.Name(), "snapshot rename")
+	}
+	if opts == nil {
+		opts = NewStore()
+	}
+	defer invalidateSnapshot(d, snapshotID)
+	return rd.SnapshotRename(ctx, snapshotID, newName, opts)
+}
+
+// SnapshotCopyIncremental copies a snapshot, transferring only the changes
+// since the base snapshot, an earlier copy at the destination. If the base
+// snapshot is empty then the snapshot is copied normally. If the driver does
+// not implement StorageDriverSnapCopyIncremental then the snapshot is copied
+// in full and the new snapshot's FullCopy flag is set.
+func SnapshotCopyIncremental(
+	ctx types.Context,
+	d types.StorageDriver,
+	snapshotID, snapshotName, destinationID, baseSnapshotID string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	if opts == nil {
+		opts = NewStore()
+	}
+
+	if baseSnapshotID == "" {
+		return d.SnapshotCopy(
+			ctx, snapshotID, snapshotName, destinationID, opts)
+	}
+
+	if cd, ok := underlying(d).(types.StorageDriverSnapCopyIncremental); ok {
+		return cd.SnapshotCopyIncremental(
+			ctx, snapshotID, snapshotName, destinationID, baseSnapshotID, opts)
+	}
+
+	snap, err := d.SnapshotCopy(
+		ctx, snapshotID, snapshotName, destinationID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if snap != nil {
+		snap.FullCopy = true
+	}
+	return snap, nil
+}
+
+// SnapshotProgress returns the percentage, from 0 to 100, of a snapshot that
+// is complete. Drivers that are only aware of whether or not a snapshot is
+// complete report either 0 or 100.
+func SnapshotProgress(
+	ctx types.Context,
+	d types.StorageDriver,
+	snapshotID string,
+	opts types.Store) (int, error) {
+
+	if opts == nil {
+		opts = NewStore()
+	}
+	snap, err := d.SnapshotInspect(ctx, snapshotID, opts)
+	if err != nil {
+		return 0, err
+	}
+	if snap == nil {
+		return 0, NewNotFoundError(snapshotID)
+	}
+	return snap.Progress, nil
+}
+
+// SnapshotsRemove removes the snapshots with the specified IDs. Each snapshot
+// is removed concurrently by a bounded pool of workers, and the returned map
+// contains an entry for every snapshot that could not be removed. Snapshots
+// not yet removed when the context is done are recorded with the context's
+// error. The returned error is reserved for failures that prevent any
+// snapshots from being removed.
+func SnapshotsRemove(
+	ctx types.Context,
+	d types.StorageDriver,
+	snapshotIDs []string,
+	opts types.Store) (map[string]error, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = NewStore()
+	}
+
+	var (
+		errsL sync.Mutex
+		errs  = map[string]error{}
+	)
+
+	n, err := forEachIndex(ctx, len(snapshotIDs), bulkWorkers, func(i int) {
+		if err := d.SnapshotRemove(ctx, snapshotIDs[i], opts); err != nil {
+			errsL.Lock()
+			errs[snapshotIDs[i]] = err
+			errsL.Unlock()
+		}
+	})
+	if err != nil {
+		for _, id := range snapshotIDs[n:] {
+			errs[id] = err
+		}
+	}
+
+	return errs, nil
+}
+
+// SnapshotsCopy copies a batch of snapshots. The copies are performed
+// concurrently by a bounded pool of workers, which is useful when copying
+// snapshots to other regions as each copy may take a long time to complete.
+// The returned results are in the same order as the items. Copies not
+// yet started when the context is done are recorded with the context's
+// error. The returned error is reserved for failures that prevent any
+// snapshots from being copied.
+func SnapshotsCopy(
+	ctx types.Context,
+	d types.StorageDriver,
+	items []*types.SnapshotsCopyItem,
+	opts *types.SnapshotsCopyOpts) ([]*types.SnapshotCopyResult, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &types.SnapshotsCopyOpts{}
+	}
+	if opts.Opts == nil {
+		opts.Opts = NewStore()
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = bulkWorkers
+	}
+
+	var (
+		completedL sync.Mutex
+		completed  int
+		results    = make([]*types.SnapshotCopyResult, len(items))
+	)
+
+	n, err := forEachIndex(ctx, len(items), workers, func(i int) {
+		r := items[i]
+		snap, err := d.SnapshotCopy(
+			ctx, r.SnapshotID, r.SnapshotName, r.DestinationID, opts.Opts)
+		results[i] = &types.SnapshotCopyResult{
+			Item:     r,
+			Snapshot: snap,
+			Error:    err,
+		}
+		if err != nil {
+			ctx.WithField("snapshotID", r.SnapshotID).WithError(err).Error(
+				"error copying snapshot")
+			results[i].Snapshot = nil
+		}
+		if opts.Progress != nil {
+			completedL.Lock()
+			completed++
+			c := completed
+			completedL.Unlock()
+			opts.Progress(results[i], c, len(items))
+		}
+	})
+	if err != nil {
+		for i, r := range items[n:] {
+			results[n+i] = &types.SnapshotCopyResult{Item: r, Error: err}
+		}
+	}
+
+	return results, nil
+}
+
+// VolumesAttach attaches a batch of volumes to the instance identified by
+// the context's instance ID. Items that request a device name are attached
+// first, concurrently by a bounded pool of workers. The remaining items are
+// then attached one at a time so the driver does not select the same next
+// available device twice, or a device that was requested. An item that
+// requests the same volume as an earlier item is ignored, and an item that
+// requests the same device name as an earlier item is not attached.
+//
+// The attachments of the volumes that were attached are returned along with
+// the errors for the volumes that were not, keyed by volume ID. Attachments
+// not yet started when the context is done are recorded with the context's
+// error.
+func VolumesAttach(
+	ctx types.Context,
+	d types.StorageDriver,
+	items []*types.VolumesAttachItem,
+	opts *types.VolumeAttachOpts) ([]*types.VolumeAttachment, map[string]error) {
+
+	if opts == nil {
+		opts = &types.VolumeAttachOpts{}
+	}
+	if opts.Opts == nil {
+		opts.Opts = NewStore()
+	}
+
+	var (
+		resultsL sync.Mutex
+		results  []*types.VolumeAttachment
+		errs     = map[string]error{}
+		volIDs   = map[string]bool{}
+		devNames = map[string]bool{}
+		explicit []*types.VolumesAttachItem
+		auto     []*types.VolumesAttachItem
+	)
+
+	for _, item := range items {
+		if volIDs[item.VolumeID] {
+			continue
+		}
+		volIDs[item.VolumeID] = true
+		if item.DeviceName == "" {
+			auto = append(auto, item)
+			continue
+		}
+		if devNames[item.DeviceName] {
+			errs[item.VolumeID] = goof.WithFields(goof.Fields{
+				"volumeID":   item.VolumeID,
+				"deviceName": item.DeviceName,
+			}, "device name requested by another volume")
+			continue
+		}
+		devNames[item.DeviceName] = true
+		explicit = append(explicit, item)
+	}
+
+	attach := func(item *types.VolumesAttachItem) {
+		attOpts := *opts
+		if item.DeviceName != "" {
+			deviceName := item.DeviceName
+			attOpts.NextDevice = &deviceName
+		}
+
+		vol, _, err := d.VolumeAttach(ctx, item.VolumeID, &attOpts)
+
+		resultsL.Lock()
+		defer resultsL.Unlock()
+		if err != nil {
+			ctx.WithField("volumeID", item.VolumeID).WithError(err).Error(
+				"error attaching volume")
+			errs[item.VolumeID] = err
+			return
+		}
+		results = append(results, volumeAttachment(ctx, vol, item))
+	}
+
+	for _, batch := range []struct {
+		items   []*types.VolumesAttachItem
+		workers int
+	}{
+		{explicit, bulkWorkers},
+		{auto, 1},
+	} {
+		n, err := forEachIndex(
+			ctx, len(batch.items), batch.workers, func(i int) {
+				attach(batch.items[i])
+			})
+		if err != nil {
+			for _, item := range batch.items[n:] {
+				errs[item.VolumeID] = err
+			}
+		}
+	}
+
+	return results, errs
+}
+
+// volumeAttachment returns the volume's attachment to the instance
+// identified by the context's instance ID. If the driver did not return the
+// attachment then one is created from the requested attachment.
+func volumeAttachment(
+	ctx types.Context,
+	vol *types.Volume,
+	item *types.VolumesAttachItem) *types.VolumeAttachment {
+
+	if vol != nil {
+		iid, _ := context.InstanceID(ctx)
+		for _, a := range vol.Attachments {
+			if iid == nil || a.InstanceID == nil || a.InstanceID.ID == iid.ID {
+				return a
+			}
+		}
+	}
+	return &types.VolumeAttachment{
+		VolumeID:   item.VolumeID,
+		DeviceName: item.DeviceName,
+		Status:     types.AttachmentStatusAttached,
+	}
+}
+
+// VolumeDetachAll detaches all of the volumes attached to the specified
+// instance and returns the attachments that were detached. If the instance ID
+// is empty then the instance ID stored in the context is used. Each volume
+// is detached with a context whose instance ID is that of the attachment, so
+// drivers detach the volume from the specified instance rather than from the
+// context's instance. A failure to detach one volume does not prevent the
+// remaining volumes from being detached; instead the failures are aggregated
+// into the returned error.
+func VolumeDetachAll(
+	ctx types.Context,
+	d types.StorageDriver,
+	instanceID string,
+	opts *types.VolumeDetachOpts) ([]*types.VolumeAttachment, error) {
+
+	if instanceID == "" {
+		iid, ok := context.InstanceID(ctx)
+		if !ok || iid == nil || iid.ID == "" {
+			return nil, NewMissingInstanceIDError(d.Name())
+		}
+		instanceID = iid.ID
+	}
+
+	if opts == nil {
+		opts = &types.VolumeDetachOpts{Opts: NewStore()}
+	}
+
+	vols, err := d.Volumes(
+		ctx, &types.VolumesOpts{Attachments: types.VolAttReq, Opts: opts.Opts})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		detached []*types.VolumeAttachment
+		failed   = map[string]string{}
+	)
+
+	for _, v := range vols {
+		for _, a := range v.Attachments {
+			if a.InstanceID == nil || a.InstanceID.ID != instanceID {
+				continue
+			}
+			dctx := context.WithInstanceID(ctx, a.InstanceID)
+			if _, err := d.VolumeDetach(dctx, v.ID, opts); err != nil {
+				ctx.WithField("volumeID", v.ID).WithError(err).Error(
+					"error detaching volume")
+				failed[v.ID] = err.Error()
+			} else {
+				detached = append(detached, a)
+			}
+			break
+		}
+	}
+
+	if len(failed) > 0 {
+		return detached, goof.WithFields(goof.Fields{
+			"instanceID": instanceID,
+			"failed":     failed,
+		}, "error detaching volumes")
+	}
+	return detached, nil
+}
+
+// VolumeExists returns a flag indicating whether or not the volume with the
+// specified ID exists. An error is returned only if the existence of the
+// volume could not be determined.
+func VolumeExists(
+	ctx types.Context,
+	d types.StorageDriver,
+	volumeID string) (bool, error) {
+
+	v, err := d.VolumeInspect(
+		ctx, volumeID, &types.VolumeInspectOpts{Opts: NewStore()})
+	if err != nil {
+		if IsNotFoundErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return v != nil, nil
+}
+
+// SnapshotExists returns a flag indicating whether or not the snapshot with
+// the specified ID exists. An error is returned only if the existence of the
+// snapshot could not be determined.
+func SnapshotExists(
+	ctx types.Context,
+	d types.StorageDriver,
+	snapshotID string) (bool, error) {
+
+	s, err := d.SnapshotInspect(ctx, snapshotID, NewStore())
+	if err != nil {
+		if IsNotFoundErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return s != nil, nil
+}
+
+// InstanceAttachments returns the attachments of the volumes attached to the
+// instance with the specified ID. If the instance ID is empty then the
+// attachments of the instance ID in the context are returned. If the driver
+// does not implement StorageDriverInstanceAttachments then the driver's
+// volumes are listed and their attachments are filtered by instance.
+func InstanceAttachments(
+	ctx types.Context,
+	d types.StorageDriver,
+	instanceID string) ([]*types.VolumeAttachment, error) {
+
+	local := false
+	if instanceID == "" {
+		iid, ok := context.InstanceID(ctx)
+		if !ok || iid == nil || iid.ID == "" {
+			return nil, NewMissingInstanceIDError(d.Name())
+		}
+		instanceID = iid.ID
+		local = true
+	}
+
+	if ad, ok := underlying(d).(types.StorageDriverInstanceAttachments); ok {
+		return ad.InstanceAttachments(ctx, instanceID, NewStore())
+	}
+
+	// device names can only be mapped for the local instance's devices
+	attachments := types.VolAttReq
+	if _, ok := context.LocalDevices(ctx); ok && local {
+		attachments = types.VolAttReqWithDevMapForInstance
+	}
+
+	vols, err := d.Volumes(
+		ctx, &types.VolumesOpts{Attachments: attachments, Opts: NewStore()})
+	if err != nil {
+		return nil, err
+	}
+
+	var atts []*types.VolumeAttachment
+	for _, v := range vols {
+		for _, a := range v.Attachments {
+			if a.InstanceID == nil || a.InstanceID.ID != instanceID {
+				continue
+			}
+			if a.VolumeID == "" {
+				a.VolumeID = v.ID
+			}
+			atts = append(atts, a)
+		}
+	}
+	return atts, nil
+}
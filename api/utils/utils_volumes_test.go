@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestVolumeCreateIdempotentByName(t *testing.T) {
+	ctx := context.Background()
+	size := int64(10)
+	d := newTestDriver()
+	d.vols = []*types.Volume{{ID: "vol-1", Name: "data", Size: size}}
+
+	// match
+	v, err := VolumeCreate(ctx, d, "data", &types.VolumeCreateOpts{
+		Size:             &size,
+		IdempotentByName: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "vol-1", v.ID)
+	assert.Equal(t, 0, d.count("VolumeCreate"))
+
+	// conflict
+	bigger := int64(20)
+	_, err = VolumeCreate(ctx, d, "data", &types.VolumeCreateOpts{
+		Size:             &bigger,
+		IdempotentByName: true,
+	})
+	if assert.IsType(t, &types.ErrConflict{}, err) {
+		assert.Contains(t, err.Error(), "conflicting")
+	}
+	assert.Equal(t, 0, d.count("VolumeCreate"))
+
+	// create new
+	v, err = VolumeCreate(ctx, d, "logs", &types.VolumeCreateOpts{
+		Size:             &size,
+		IdempotentByName: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "logs", v.ID)
+	assert.Equal(t, 1, d.count("VolumeCreate"))
+}
+
+func TestVolumeCreateNotIdempotent(t *testing.T) {
+	d := newTestDriver()
+	d.vols = []*types.Volume{{ID: "vol-1", Name: "data"}}
+	v, err := VolumeCreate(
+		context.Background(), d, "data", &types.VolumeCreateOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, "data", v.ID)
+	assert.Equal(t, 1, d.count("VolumeCreate"))
+}
+
+func TestVolumeResizeUnsupported(t *testing.T) {
+	d := newTestDriver()
+	d.vols = []*types.Volume{{ID: "vol-1", Size: 10}}
+	_, err := VolumeResize(context.Background(), d, "vol-1", 20, nil)
+	assert.IsType(t, &types.ErrUnsupported{}, err)
+}
+
+func TestVolumeSetTagsUnsupported(t *testing.T) {
+	d := newTestDriver()
+	d.vols = []*types.Volume{{ID: "vol-1"}}
+	err := VolumeSetTags(context.Background(), d, "vol-1",
+		map[string]string{"env": "test"}, nil)
+	assert.IsType(t, &types.ErrUnsupported{}, err)
+}
+
+func TestInstanceAttachments(t *testing.T) {
+	iid := &types.InstanceID{ID: "i-1", Driver: "test"}
+	other := &types.InstanceID{ID: "i-2", Driver: "test"}
+	d := newTestDriver()
+	d.vols = []*types.Volume{
+		{ID: "vol-1", Attachments: []*types.VolumeAttachment{
+			{InstanceID: iid, DeviceName: "/dev/xvdb"}}},
+		{ID: "vol-2", Attachments: []*types.VolumeAttachment{
+			{InstanceID: other, DeviceName: "/dev/xvdb"}}},
+		{ID: "vol-3", Attachments: []*types.VolumeAttachment{
+			{InstanceID: iid, VolumeID: "vol-3", DeviceName: "/dev/xvdc"}}},
+		{ID: "vol-4"},
+	}
+
+	_, err := InstanceAttachments(context.Background(), d, "")
+	assert.IsType(t, &types.ErrMissingInstanceID{}, err)
+
+	ctx := context.WithInstanceID(context.Background(), iid)
+	atts, err := InstanceAttachments(ctx, d, "")
+	assert.NoError(t, err)
+	if assert.Len(t, atts, 2) {
+		assert.Equal(t, "vol-1", atts[0].VolumeID)
+		assert.Equal(t, "/dev/xvdb", atts[0].DeviceName)
+		assert.Equal(t, "vol-3", atts[1].VolumeID)
+		assert.Equal(t, "/dev/xvdc", atts[1].DeviceName)
+	}
+
+	atts, err = InstanceAttachments(ctx, d, "i-2")
+	assert.NoError(t, err)
+	if assert.Len(t, atts, 1) {
+		assert.Equal(t, "vol-2", atts[0].VolumeID)
+	}
+}
+
+func TestSnapshotCopyIncremental(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDriver()
+	d.snaps = []*types.Snapshot{{ID: "snap-1", VolumeID: "vol-1"}}
+
+	snap, err := SnapshotCopyIncremental(
+		ctx, d, "snap-1", "copy-1", "us-west-2", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "copy-1", snap.ID)
+	assert.False(t, snap.FullCopy)
+
+	snap, err = SnapshotCopyIncremental(
+		ctx, d, "snap-1", "copy-2", "us-west-2", "snap-0", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "copy-2", snap.ID)
+	assert.True(t, snap.FullCopy)
+	assert.Equal(t, 2, d.count("SnapshotCopy"))
+
+	od := &testOptionalDriver{d}
+	snap, err = SnapshotCopyIncremental(
+		ctx, od, "snap-1", "copy-3", "us-west-2", "snap-0", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "copy-3", snap.ID)
+	assert.False(t, snap.FullCopy)
+	assert.Equal(t, "snap-0", snap.Fields["baseSnapshotID"])
+	assert.Equal(t, 2, d.count("SnapshotCopy"))
+}
+
+func TestVolumesAttach(t *testing.T) {
+	d := newTestDriver()
+	d.delay = 5 * time.Millisecond
+	for i := 1; i <= 6; i++ {
+		d.vols = append(d.vols, &types.Volume{ID: fmt.Sprintf("vol-%d", i)})
+	}
+	atts, errs := VolumesAttach(
+		context.Background(), d,
+		[]*types.VolumesAttachItem{
+			{VolumeID: "vol-1", DeviceName: "/dev/xvdf"},
+			{VolumeID: "vol-2", DeviceName: "/dev/xvdf"},
+			{VolumeID: "vol-3", DeviceName: "/dev/xvdg"},
+			{VolumeID: "vol-1", DeviceName: "/dev/xvdh"},
+			{VolumeID: "vol-4"},
+			{VolumeID: "vol-5"},
+			{VolumeID: "vol-6"},
+			{VolumeID: "vol-bad"},
+		}, nil)
+
+	devices := map[string]string{}
+	for _, a := range atts {
+		devices[a.VolumeID] = a.DeviceName
+	}
+	assert.Equal(t, map[string]string{
+		"vol-1": "/dev/xvdf",
+		"vol-3": "/dev/xvdg",
+		"vol-4": "/dev/xvdh",
+		"vol-5": "/dev/xvdi",
+		"vol-6": "/dev/xvdj",
+	}, devices)
+
+	failed := []string{}
+	for k := range errs {
+		failed = append(failed, k)
+	}
+	sort.Strings(failed)
+	assert.Equal(t, []string{"vol-2", "vol-bad"}, failed)
+	assert.Equal(t, 1, d.busiest(testNextDevice))
+}
+
+func TestVolumesAttachMixed(t *testing.T) {
+	d := newTestDriver()
+	for i := 1; i <= 4; i++ {
+		d.vols = append(d.vols, &types.Volume{ID: fmt.Sprintf("vol-%d", i)})
+	}
+	atts, errs := VolumesAttach(
+		context.Background(), d,
+		[]*types.VolumesAttachItem{
+			{VolumeID: "vol-1"},
+			{VolumeID: "vol-2"},
+			{VolumeID: "vol-3", DeviceName: "/dev/xvdf"},
+			{VolumeID: "vol-4", DeviceName: "/dev/xvdg"},
+		}, nil)
+	assert.Empty(t, errs)
+
+	devices := map[string]string{}
+	for _, a := range atts {
+		devices[a.VolumeID] = a.DeviceName
+	}
+	assert.Equal(t, map[string]string{
+		"vol-1": "/dev/xvdh",
+		"vol-2": "/dev/xvdi",
+		"vol-3": "/dev/xvdf",
+		"vol-4": "/dev/xvdg",
+	}, devices)
+}
+
+func TestVolumeCreateFromSnapshot(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDriver()
+	d.snaps = []*types.Snapshot{{ID: "snap-1", VolumeSize: 8}}
+
+	_, err := VolumeCreateFromSnapshot(ctx, d, "snap-1", "vol-1", 4, nil)
+	assert.Error(t, err)
+	assert.Equal(t, 0, d.count("VolumeCreateFromSnapshot"))
+
+	_, err = VolumeCreateFromSnapshot(ctx, d, "snap-2", "vol-2", 16, nil)
+	assert.IsType(t, &types.ErrSnapshotNotFound{}, err)
+
+	vol, err := VolumeCreateFromSnapshot(ctx, d, "snap-1", "vol-3", 0, nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 8, vol.Size)
+
+	volType := "gp2"
+	iops := int64(100)
+	vol, err = VolumeCreateFromSnapshot(
+		ctx, d, "snap-1", "vol-4", 16,
+		&types.VolumeCreateOpts{Type: &volType, IOPS: &iops})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 16, vol.Size)
+	assert.Equal(t, "gp2", vol.Type)
+	assert.EqualValues(t, 100, vol.IOPS)
+}
+
+func TestVolumeClone(t *testing.T) {
+	d := newTestDriver()
+	d.vols = []*types.Volume{{ID: "vol-1", Size: 8}}
+	vol, err := VolumeClone(context.Background(), d, "vol-1", "clone", nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "clone", vol.ID)
+		assert.EqualValues(t, 8, vol.Size)
+	}
+	assert.Empty(t, d.snaps)
+}
+
+func TestVolumeCloneCanceled(t *testing.T) {
+	ctx, cancel := context.WithDefaultTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// the context is canceled once the snapshot is taken, and the snapshot
+	// cannot be removed with a context that is done
+	d := newTestDriver()
+	d.vols = []*types.Volume{{ID: "vol-1", Size: 8}}
+	d.before = func(ctx types.Context, op, id string) error {
+		switch op {
+		case "VolumeSnapshot":
+			cancel()
+		case "SnapshotRemove":
+			return ctx.Err()
+		}
+		return nil
+	}
+	_, err := VolumeClone(ctx, d, "vol-1", "clone", nil)
+	assert.Equal(t, ctx.Err(), err)
+	assert.Equal(t, 0, d.count("VolumeCreateFromSnapshot"))
+	assert.Empty(t, d.snaps, "intermediate snapshot removed")
+}
+
+func TestVolumeDetachAll(t *testing.T) {
+	local := &types.InstanceID{ID: "i-local", Driver: "test"}
+	remote := &types.InstanceID{ID: "i-remote", Driver: "test"}
+	d := newTestDriver()
+	d.vols = []*types.Volume{
+		{ID: "vol-1", Attachments: []*types.VolumeAttachment{
+			{VolumeID: "vol-1", InstanceID: remote}}},
+		{ID: "vol-2", Attachments: []*types.VolumeAttachment{
+			{VolumeID: "vol-2", InstanceID: local}}},
+		{ID: "vol-3", Attachments: []*types.VolumeAttachment{
+			{VolumeID: "vol-3", InstanceID: remote},
+			{VolumeID: "vol-3", InstanceID: local}}},
+	}
+	ctx := context.WithInstanceID(context.Background(), local)
+
+	// the volumes are detached from the remote instance only
+	atts, err := VolumeDetachAll(ctx, d, remote.ID, nil)
+	assert.NoError(t, err)
+	assert.Len(t, atts, 2)
+	assert.Empty(t, d.vols[0].Attachments)
+	assert.Len(t, d.vols[1].Attachments, 1)
+	if assert.Len(t, d.vols[2].Attachments, 1) {
+		assert.Equal(t, local, d.vols[2].Attachments[0].InstanceID)
+	}
+
+	atts, err = VolumeDetachAll(ctx, d, "", nil)
+	assert.NoError(t, err)
+	assert.Len(t, atts, 2)
+	assert.Empty(t, d.vols[1].Attachments)
+	assert.Empty(t, d.vols[2].Attachments)
+}
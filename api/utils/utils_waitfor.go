@@ -1,6 +1,14 @@
 package utils
 
-import "time"
+import (
+	"os"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
 
 // WaitFor waits for a lambda to complete or aborts after a specified amount
 // of time. If the function fails to complete in the specified amount of time
@@ -30,3 +38,59 @@ func WaitFor(
 		return nil, false, nil
 	}
 }
+
+const (
+	waitForDeviceBaseDelay = 100 * time.Millisecond
+	waitForDeviceMaxDelay  = 2 * time.Second
+)
+
+// WaitForDevice waits for the device node at the provided path to appear.
+// The delay between checks doubles from 100ms up to 2s. ErrTimedOut is
+// returned if the device does not appear before the timeout elapses, and
+// the context's error is returned if the context is done first. A timeout
+// less than or equal to zero waits until the context is done.
+func WaitForDevice(
+	ctx types.Context,
+	devicePath string,
+	timeout time.Duration) error {
+
+	var timeoutC <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutC = timer.C
+	}
+
+	delay := waitForDeviceBaseDelay
+	for {
+		_, err := os.Stat(devicePath)
+		if err == nil {
+			return nil
+		}
+		if !os.IsNotExist(err) {
+			return goof.WithFieldE(
+				"devicePath", devicePath, "error checking device", err)
+		}
+
+		ctx.WithField("devicePath", devicePath).Debug("waiting for device")
+
+		wait := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			return ctx.Err()
+		case <-timeoutC:
+			wait.Stop()
+			ctx.WithFields(log.Fields{
+				"devicePath": devicePath,
+				"timeout":    timeout,
+			}).Debug("timed out waiting for device")
+			return types.ErrTimedOut
+		case <-wait.C:
+		}
+
+		if delay = delay * 2; delay > waitForDeviceMaxDelay {
+			delay = waitForDeviceMaxDelay
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestWaitForDevice(t *testing.T) {
+	dir, err := ioutil.TempDir("", "waitfordevice")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	dev := path.Join(dir, "xvdf")
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		ioutil.WriteFile(dev, nil, 0644)
+	}()
+	assert.NoError(t, WaitForDevice(context.Background(), dev, 5*time.Second))
+
+	err = WaitForDevice(
+		context.Background(), path.Join(dir, "xvdg"), 200*time.Millisecond)
+	assert.Equal(t, types.ErrTimedOut, err)
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"sync"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// bulkWorkers is the maximum number of concurrent operations used by the
+// bulk helpers when a driver cannot perform an operation in bulk natively.
+const bulkWorkers = 10
+
+// forEachIndex invokes f for each index from 0 to n-1 using no more than
+// the specified number of concurrent workers. No new indices are dispatched
+// once the context is done, in which case the context's error is returned
+// after all in-flight invocations have completed. The return value is the
+// number of indices that were dispatched.
+func forEachIndex(
+	ctx types.Context, n, workers int, f func(i int)) (int, error) {
+
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		wg   sync.WaitGroup
+		jobs = make(chan int)
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				f(i)
+			}
+		}()
+	}
+
+	dispatched, err := func() (int, error) {
+		defer close(jobs)
+		for i := 0; i < n; i++ {
+			if err := ctx.Err(); err != nil {
+				return i, err
+			}
+			select {
+			case <-ctx.Done():
+				return i, ctx.Err()
+			case jobs <- i:
+			}
+		}
+		return n, nil
+	}()
+
+	wg.Wait()
+	return dispatched, err
+}
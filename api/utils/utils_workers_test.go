@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+
+	apictx "github.com/codedellemc/libstorage/api/context"
+)
+
+func TestForEachIndex(t *testing.T) {
+	var (
+		seenL sync.Mutex
+		seen  = map[int]bool{}
+	)
+	n, err := forEachIndex(apictx.Background(), 25, 4, func(i int) {
+		seenL.Lock()
+		defer seenL.Unlock()
+		seen[i] = true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 25, n)
+	assert.Len(t, seen, 25)
+}
+
+func TestForEachIndexBoundedWorkers(t *testing.T) {
+	var active, max int32
+	_, err := forEachIndex(apictx.Background(), 50, 3, func(i int) {
+		a := atomic.AddInt32(&active, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if a <= m || atomic.CompareAndSwapInt32(&max, m, a) {
+				break
+			}
+		}
+		atomic.AddInt32(&active, -1)
+	})
+	assert.NoError(t, err)
+	assert.True(t, max <= 3)
+}
+
+func TestForEachIndexCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(apictx.Background())
+	cancel()
+	n, err := forEachIndex(apictx.New(ctx), 10, 2, func(i int) {})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, n)
+}
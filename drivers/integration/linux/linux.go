@@ -3,6 +3,7 @@ package linux
 import (
 	"os"
 	"path"
+	"strings"
 
 	"fmt"
 
@@ -28,11 +29,17 @@ type driver struct {
 }
 
 type volumeMapping struct {
+	ID               string                 `json:"-"`
+	InstID           string                 `json:"-"`
 	Name             string                 `json:"Name"`
 	VolumeMountPoint string                 `json:"Mountpoint"`
 	VolumeStatus     map[string]interface{} `json:"Status"`
 }
 
+func (v *volumeMapping) VolumeID() string {
+	return v.ID
+}
+
 func (v *volumeMapping) VolumeName() string {
 	return v.Name
 }
@@ -41,6 +48,10 @@ func (v *volumeMapping) MountPoint() string {
 	return v.VolumeMountPoint
 }
 
+func (v *volumeMapping) InstanceID() string {
+	return v.InstID
+}
+
 func (v *volumeMapping) Status() map[string]interface{} {
 	return v.VolumeStatus
 }
@@ -93,14 +104,23 @@ func (d *driver) List(
 	ctx types.Context,
 	opts types.Store) ([]types.VolumeMapping, error) {
 
-	client := context.MustClient(ctx)
-	vols, err := client.Storage().Volumes(
-		ctx,
-		&types.VolumesOpts{
-			Attachments: opts.GetAttachments(),
-			Opts:        opts,
-		},
+	var (
+		vols []*types.Volume
+		err  error
 	)
+
+	client := context.MustClient(ctx)
+	if volumeID := opts.GetString("volumeID"); volumeID != "" {
+		vols, err = d.listVolume(ctx, volumeID, opts)
+	} else {
+		vols, err = client.Storage().Volumes(
+			ctx,
+			&types.VolumesOpts{
+				Attachments: opts.GetAttachments(),
+				Opts:        opts,
+			},
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -110,10 +130,14 @@ func (d *driver) List(
 		return nil, goof.New("service name is missing")
 	}
 
+	d.setMountPoints(ctx, vols, opts)
+
 	volMaps := []types.VolumeMapping{}
 	for _, v := range vols {
 		vs := buildVolumeStatus(v, serviceName)
 		volMaps = append(volMaps, &volumeMapping{
+			ID:               v.ID,
+			InstID:           v.InstanceID(),
 			Name:             v.Name,
 			VolumeMountPoint: v.MountPoint(),
 			VolumeStatus:     vs,
@@ -123,6 +147,33 @@ func (d *driver) List(
 	return volMaps, nil
 }
 
+// listVolume returns the volume with the provided ID as a list. The server
+// omits a volume that does not match the requested attachment state, so an
+// empty list is returned if the volume does not match or does not exist.
+func (d *driver) listVolume(
+	ctx types.Context,
+	volumeID string,
+	opts types.Store) ([]*types.Volume, error) {
+
+	client := context.MustClient(ctx)
+	vol, err := client.Storage().VolumeInspect(
+		ctx,
+		volumeID,
+		&types.VolumeInspectOpts{
+			Attachments: opts.GetAttachments(),
+			Opts:        opts,
+		},
+	)
+	if err != nil {
+		if utils.IsNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return []*types.Volume{vol}, nil
+}
+
 // Inspect returns a specific volume as identified by the provided
 // volume name.
 func (d *driver) Inspect(
@@ -152,8 +203,11 @@ func (d *driver) Inspect(
 	if vol == nil {
 		return nil, utils.NewNotFoundError(volumeName)
 	}
+	d.setMountPoints(ctx, []*types.Volume{vol}, opts)
 	vs := buildVolumeStatus(vol, serviceName)
 	obj := &volumeMapping{
+		ID:               vol.ID,
+		InstID:           vol.InstanceID(),
 		Name:             vol.Name,
 		VolumeMountPoint: vol.MountPoint(),
 		VolumeStatus:     vs,
@@ -217,8 +271,9 @@ func (d *driver) Mount(
 		var token string
 		vol, token, err = client.Storage().VolumeAttach(
 			ctx, vol.ID, &types.VolumeAttachOpts{
-				Force: opts.Preempt,
-				Opts:  utils.NewStore(),
+				Force:    opts.Preempt,
+				ReadOnly: opts.ReadOnly,
+				Opts:     utils.NewStore(),
 			})
 		if err != nil {
 			return "", nil, err
@@ -273,6 +328,16 @@ func (d *driver) Mount(
 		return "", nil, goof.New("no device name returned")
 	}
 
+	// the device node may not exist yet even though the executor reported
+	// the device, so wait for it before formatting or mounting the device
+	if strings.HasPrefix(ma.DeviceName, "/dev/") {
+		if err := utils.WaitForDevice(
+			ctx, ma.DeviceName,
+			apiconfig.DeviceAttachTimeout(d.config)); err != nil {
+			return "", nil, err
+		}
+	}
+
 	mounts, err := client.OS().Mounts(
 		ctx, ma.DeviceName, "", opts.Opts)
 	if err != nil {
@@ -283,17 +348,20 @@ func (d *driver) Mount(
 		return d.volumeMountPath(mounts[0].MountPoint), vol, nil
 	}
 
-	if opts.NewFSType == "" {
-		opts.NewFSType = d.fsType()
-	}
-	if err := client.OS().Format(
-		ctx,
-		ma.DeviceName,
-		&types.DeviceFormatOpts{
-			NewFSType:   opts.NewFSType,
-			OverwriteFS: opts.OverwriteFS,
-		}); err != nil {
-		return "", nil, err
+	readOnly := opts.ReadOnly || ma.ReadOnly
+	if !readOnly {
+		if opts.NewFSType == "" {
+			opts.NewFSType = d.fsType()
+		}
+		if err := client.OS().Format(
+			ctx,
+			ma.DeviceName,
+			&types.DeviceFormatOpts{
+				NewFSType:   opts.NewFSType,
+				OverwriteFS: opts.OverwriteFS,
+			}); err != nil {
+			return "", nil, err
+		}
 	}
 
 	mountPath, err := d.getVolumeMountPath(vol.Name)
@@ -305,11 +373,15 @@ func (d *driver) Mount(
 		return "", nil, err
 	}
 
+	mountOpts := &types.DeviceMountOpts{}
+	if readOnly {
+		mountOpts.MountOptions = "ro"
+	}
 	if err := client.OS().Mount(
 		ctx,
 		ma.DeviceName,
 		mountPath,
-		&types.DeviceMountOpts{}); err != nil {
+		mountOpts); err != nil {
 		return "", nil, err
 	}
 
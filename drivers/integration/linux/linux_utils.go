@@ -105,14 +105,44 @@ func (d *driver) volumeInspectByIDOrName(
 }
 
 func isErrNotFound(err error) bool {
-	switch err.(type) {
-	case *types.ErrNotFound:
-		return true
-	default:
-		return false
-	}
+	return utils.IsNotFoundErr(err)
 }
 
 func (d *driver) volumeMountPath(target string) string {
 	return path.Join(target, d.volumeRootPath())
 }
+
+// setMountPoints sets the mount points of the volumes' attachments from the
+// host's mounts. Drivers such as S3FS and NFS report the mount point of a
+// volume with its attachment, but block device drivers report only the
+// device. The volumes are returned without mount points if the mounts
+// cannot be read.
+func (d *driver) setMountPoints(
+	ctx types.Context,
+	vols []*types.Volume,
+	opts types.Store) {
+
+	attached := false
+	for _, v := range vols {
+		if len(v.Attachments) > 0 {
+			attached = true
+			break
+		}
+	}
+	if !attached {
+		return
+	}
+
+	client := context.MustClient(ctx)
+	mounts, err := client.OS().Mounts(ctx, "", "", opts)
+	if err != nil {
+		ctx.WithError(err).Warn("error getting mounts")
+		return
+	}
+
+	var instanceID string
+	if iid, ok := context.InstanceID(ctx); ok {
+		instanceID = iid.ID
+	}
+	utils.SetMountPoints(vols, mounts, instanceID)
+}
@@ -160,7 +160,8 @@ func (d *driver) Mount(
 
 	options := fmt.Sprintf("%s,%s", opts.MountOptions, opts.MountLabel)
 	if fsType == "xfs" {
-		options = fmt.Sprintf("%s,nouuid", opts.MountLabel)
+		options = fmt.Sprintf(
+			"%s,%s,nouuid", opts.MountOptions, opts.MountLabel)
 	}
 
 	if err := mount(deviceName, mountPoint, fsType, options); err != nil {
@@ -3,7 +3,6 @@ package executor
 import (
 	"bufio"
 	"bytes"
-	"fmt"
 	"os/exec"
 	"regexp"
 	"strings"
@@ -16,6 +15,7 @@ import (
 
 	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/types"
+	apiUtils "github.com/codedellemc/libstorage/api/utils"
 	"github.com/codedellemc/libstorage/drivers/storage/azureud"
 	"github.com/codedellemc/libstorage/drivers/storage/azureud/utils"
 )
@@ -65,46 +65,24 @@ func (d *driver) InstanceID(
 	return utils.InstanceID(ctx)
 }
 
-var errNoAvaiDevice = goof.New("no available device")
-var nextDevRe = regexp.MustCompile("^/dev/" +
-	utils.NextDeviceInfo.Prefix +
-	"(" + utils.NextDeviceInfo.Pattern + ")")
-var availLetters = []string{
-	"c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n",
-	"o", "p", "q", "r", "s", "t", "u", "v", "w", "x", "y", "z"}
-
 // NextDevice returns the next available device.
 func (d *driver) NextDevice(
 	ctx types.Context,
 	opts types.Store) (string, error) {
-	// All possible device paths on Linux instances are /dev/sd[c-z]
-
-	// Find which letters are used for local devices
-	localDeviceNames := make(map[string]bool)
 
 	localDevices, err := d.LocalDevices(
 		ctx, &types.LocalDevicesOpts{Opts: opts})
 	if err != nil {
 		return "", goof.WithError("error getting local devices", err)
 	}
-	localDeviceMapping := localDevices.DeviceMap
 
-	for localDevice := range localDeviceMapping {
-		res := nextDevRe.FindStringSubmatch(localDevice)
-		if len(res) > 0 {
-			localDeviceNames[res[1]] = true
-		}
+	var inUse []string
+	for localDevice := range localDevices.DeviceMap {
+		inUse = append(inUse, localDevice)
 	}
 
-	// Find next available letter for device path
-	for _, letter := range availLetters {
-		if localDeviceNames[letter] {
-			continue
-		}
-		return fmt.Sprintf(
-			"/dev/%s%s", utils.NextDeviceInfo.Prefix, letter), nil
-	}
-	return "", errNoAvaiDevice
+	// All possible device paths on Linux instances are /dev/sd[c-z]
+	return apiUtils.GetNextAvailableDevice(utils.NextDeviceInfo, inUse)
 }
 
 var (
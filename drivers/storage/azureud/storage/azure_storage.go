@@ -79,15 +79,19 @@ func (d *driver) Name() string {
 func (d *driver) Init(context types.Context, config gofig.Config) error {
 	d.config = config
 
-	d.tenantID = d.getTenantID()
-	if d.tenantID == "" {
-		return goof.New("tenantID is a required config item")
+	if err := apiUtils.RequireConfigKeys(
+		config,
+		azureud.ConfigAzureTenantIDKey,
+		azureud.ConfigAzureClientIDKey,
+		azureud.ConfigAzureStorageAccountKey,
+		azureud.ConfigAzureStorageAccessKey,
+		azureud.ConfigAzureSubscriptionIDKey,
+		azureud.ConfigAzureResourceGroupKey); err != nil {
+		return err
 	}
 
+	d.tenantID = d.getTenantID()
 	d.clientID = d.getClientID()
-	if d.clientID == "" {
-		return goof.New("clientID is a required config item")
-	}
 
 	d.clientSecret = d.getClientSecret()
 	d.certPath = d.getCertPath()
@@ -100,26 +104,10 @@ func (d *driver) Init(context types.Context, config gofig.Config) error {
 	}
 
 	d.storageAccount = d.getStorageAccount()
-	if d.storageAccount == "" {
-		return goof.New("storageAccount is a required config item")
-	}
-
 	d.storageAccessKey = d.getStorageAccessKey()
-	if d.storageAccessKey == "" {
-		return goof.New("storageAccessKey is a required config item")
-	}
-
 	d.container = d.getContainer()
-
 	d.subscriptionID = d.getSubscriptionID()
-	if d.subscriptionID == "" {
-		return goof.New("subscriptionID is a required config item")
-	}
-
 	d.resourceGroup = d.getResourceGroup()
-	if d.resourceGroup == "" {
-		return goof.New("resourceGroup is a required config item")
-	}
 
 	d.useHTTPS = d.getUseHTTPS()
 
@@ -137,9 +125,19 @@ type azureSession struct {
 
 var (
 	sessions  = map[string]*azureSession{}
-	sessionsL = &sync.Mutex{}
+	sessionsL = &sync.RWMutex{}
 )
 
+// cachedSession returns the cached session for the key. The cache is read
+// under a shared lock so that concurrent requests do not serialize on the
+// common case.
+func cachedSession(ckey string) (*azureSession, bool) {
+	sessionsL.RLock()
+	defer sessionsL.RUnlock()
+	session, ok := sessions[ckey]
+	return session, ok
+}
+
 func writeHkeyB(h hash.Hash, ps []byte) {
 	if ps == nil {
 		return
@@ -180,9 +178,6 @@ func mustSession(ctx types.Context) *azureSession {
 }
 
 func (d *driver) Login(ctx types.Context) (interface{}, error) {
-	sessionsL.Lock()
-	defer sessionsL.Unlock()
-
 	ctx.Debug("login to azure storage driver")
 	var (
 		hkey     = md5.New()
@@ -198,12 +193,21 @@ func (d *driver) Login(ctx types.Context) (interface{}, error) {
 	writeHkey(hkey, &d.clientID)
 	ckey = fmt.Sprintf("%x", hkey.Sum(nil))
 
-	if session, ok := sessions[ckey]; ok {
+	if session, ok := cachedSession(ckey); ok {
 		ctx.WithField(cacheKeyC, ckey).Debug(
 			"using cached azure client")
 		return session, nil
 	}
 
+	sessionsL.Lock()
+	defer sessionsL.Unlock()
+
+	// another request may have created the session while this one waited
+	// for the lock
+	if session, ok := sessions[ckey]; ok {
+		return session, nil
+	}
+
 	if d.clientSecret != "" {
 		ctx.Info("Authenticating via clientSecret")
 	} else {
@@ -294,6 +298,12 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Block, nil
 }
 
+// Capabilities returns the features supported by the driver.
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{}, nil
+}
+
 // InstanceInspect returns an instance.
 func (d *driver) InstanceInspect(
 	ctx types.Context,
@@ -413,10 +423,7 @@ func (d *driver) VolumeRemove(
 	return nil
 }
 
-var (
-	errMissingNextDevice  = goof.New("missing next device")
-	errVolAlreadyAttached = goof.New("volume already attached to a host")
-)
+var errMissingNextDevice = goof.New("missing next device")
 
 // VolumeAttach attaches a volume and provides a token clients can use
 // to validate that device has appeared locally.
@@ -440,7 +447,7 @@ func (d *driver) VolumeAttach(
 	volume, err := d.getVolume(ctx, volumeID,
 		types.VolumeAttachmentsRequested)
 	if err != nil {
-		if _, ok := err.(*types.ErrNotFound); ok {
+		if apiUtils.IsNotFoundErr(err) {
 			return nil, "", err
 		}
 		return nil, "", goof.WithFieldsE(fields,
@@ -451,7 +458,7 @@ func (d *driver) VolumeAttach(
 	if len(volume.Attachments) > 0 {
 		// Detach already attached volume if forced
 		if !opts.Force {
-			return nil, "", errVolAlreadyAttached
+			return nil, "", apiUtils.NewVolumeAlreadyAttachedErr(volumeID)
 		}
 		for _, att := range volume.Attachments {
 			err = d.detachDisk(ctx, &volumeID, &att.InstanceID.ID)
@@ -484,8 +491,6 @@ func (d *driver) VolumeAttach(
 	return volume, *opts.NextDevice, nil
 }
 
-var errVolAlreadyDetached = goof.New("volume already detached")
-
 // VolumeDetach detaches a volume.
 func (d *driver) VolumeDetach(
 	ctx types.Context,
@@ -502,14 +507,14 @@ func (d *driver) VolumeDetach(
 	volume, err := d.getVolume(ctx, volumeID,
 		types.VolumeAttachmentsRequested)
 	if err != nil {
-		if _, ok := err.(*types.ErrNotFound); ok {
+		if apiUtils.IsNotFoundErr(err) {
 			return nil, err
 		}
 		return nil, goof.WithFieldsE(fields,
 			"failed to get volume", err)
 	}
 	if len(volume.Attachments) == 0 {
-		return nil, errVolAlreadyDetached
+		return nil, apiUtils.NewVolumeNotAttachedErr(volumeID)
 	}
 
 	err = d.detachDisk(ctx, &volumeID, &vmName)
@@ -695,6 +700,7 @@ func (d *driver) toTypesVolume(
 						ID:     attVM,
 						Driver: azureud.Name,
 					},
+					Status: types.AttachmentStatusAttached,
 				}
 				if attachments.Devices() {
 					if iid.ID == attVM {
@@ -776,7 +782,7 @@ func (d *driver) getVolume(
 		return nil, goof.WithError("error listing blobs", err)
 	}
 	if len(list.Blobs) == 0 {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 	if len(list.Blobs) > 1 {
 		return nil, goof.New("multiple volumes found")
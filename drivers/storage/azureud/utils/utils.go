@@ -1,11 +1,15 @@
 package utils
 
 import (
+	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/akutz/goof"
+
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/drivers/storage/azureud"
 )
@@ -13,6 +17,11 @@ import (
 const (
 	raddr    = "169.254.169.254"
 	maintURL = "http://" + raddr + "/metadata/v1/maintenance"
+
+	// vmNameURL is the Azure Instance Metadata Service endpoint that
+	// returns the name of the VM
+	vmNameURL = "http://" + raddr +
+		"/metadata/instance/compute/name?api-version=2017-04-02&format=text"
 )
 
 // IsAzureInstance returns a flag indicating whether the executing host
@@ -36,12 +45,25 @@ func IsAzureInstance(ctx types.Context) (bool, error) {
 	return false, nil
 }
 
-// InstanceID returns the instance ID for the local host.
+// InstanceID returns the instance ID for the local host. The ID is the
+// name of the VM as reported by the Azure Instance Metadata Service, or the
+// host name if the service is unavailable.
 func InstanceID(ctx types.Context) (*types.InstanceID, error) {
 
 	// UUID can be obtained as descried in
 	// https://azure.microsoft.com/en-us/blog/accessing-and-using-azure-vm-unique-id/
-	// but this code will use hostname as ID
+	// but this code will use the VM name as ID since the storage driver
+	// looks up VMs by name
+
+	if vmName, err := VMName(ctx); err == nil && vmName != "" {
+		return &types.InstanceID{
+			ID:     vmName,
+			Driver: azureud.Name,
+		}, nil
+	} else if err != nil {
+		ctx.WithError(err).Debug(
+			"error getting vm name from metadata, using hostname")
+	}
 
 	hostname, err := os.Hostname()
 	if err != nil {
@@ -52,3 +74,28 @@ func InstanceID(ctx types.Context) (*types.InstanceID, error) {
 		Driver: azureud.Name,
 	}, nil
 }
+
+// VMName returns the name of the VM from the Azure Instance Metadata
+// Service.
+func VMName(ctx types.Context) (string, error) {
+	client := &http.Client{Timeout: time.Duration(1 * time.Second)}
+	req, err := http.NewRequest(http.MethodGet, vmNameURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata", "true")
+	res, err := doRequestWithClient(ctx, client, req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", goof.WithField(
+			"status", res.StatusCode, "error getting vm name")
+	}
+	buf, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf)), nil
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"strconv"
 	"time"
 
 	gofig "github.com/akutz/gofig/types"
@@ -9,6 +10,7 @@ import (
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/types"
+	apiUtils "github.com/codedellemc/libstorage/api/utils"
 	"github.com/codedellemc/libstorage/drivers/storage/cinder"
 
 	"github.com/gophercloud/gophercloud"
@@ -18,6 +20,7 @@ import (
 	volumesv1 "github.com/gophercloud/gophercloud/openstack/blockstorage/v1/volumes"
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v2/volumes"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/extensions/trusts"
 )
 
@@ -68,8 +71,22 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Block, nil
 }
 
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsSnapshots: true,
+		SupportsClone:     true,
+	}, nil
+}
+
 func (d *driver) Init(context types.Context, config gofig.Config) error {
 	d.config = config
+
+	if err := apiUtils.RequireConfigKeys(
+		config, cinder.ConfigAuthURL); err != nil {
+		return err
+	}
+
 	fields := eff(map[string]interface{}{})
 	var err error
 
@@ -151,8 +168,22 @@ func (d *driver) InstanceInspect(
 	ctx types.Context,
 	opts types.Store) (*types.Instance, error) {
 
+	iid := context.MustInstanceID(ctx)
+
+	fields := eff(map[string]interface{}{
+		"instanceId": iid.ID,
+	})
+
+	server, err := servers.Get(d.clientCompute, iid.ID).Extract()
+	if err != nil {
+		return nil, goof.WithFieldsE(fields, "error getting server", err)
+	}
+
 	return &types.Instance{
-		InstanceID: context.MustInstanceID(ctx),
+		InstanceID:   iid,
+		Name:         server.Name,
+		Region:       d.regionName(),
+		ProviderName: iid.Driver,
 	}, nil
 }
 
@@ -261,7 +292,7 @@ func translateVolumeV1(
 				VolumeID:   attachment["volume_id"].(string),
 				InstanceID: &types.InstanceID{ID: attachment["server_id"].(string), Driver: cinder.Name},
 				DeviceName: attachment["device"].(string),
-				Status:     "",
+				Status:     types.AttachmentStatusAttached,
 			}
 			attachments = append(attachments, libstorageAttachment)
 		}
@@ -275,6 +306,7 @@ func translateVolumeV1(
 		Type:             volume.VolumeType,
 		IOPS:             0,
 		Size:             int64(volume.Size),
+		CreateTime:       time.Time(volume.CreatedAt).Unix(),
 		Attachments:      attachments,
 	}
 }
@@ -290,7 +322,7 @@ func translateVolume(
 				VolumeID:   attachment.VolumeID,
 				InstanceID: &types.InstanceID{ID: attachment.ServerID, Driver: cinder.Name},
 				DeviceName: attachment.Device,
-				Status:     "",
+				Status:     types.AttachmentStatusAttached,
 			}
 			attachments = append(attachments, libstorageAttachment)
 		}
@@ -304,6 +336,7 @@ func translateVolume(
 		Type:             volume.VolumeType,
 		IOPS:             0,
 		Size:             int64(volume.Size),
+		CreateTime:       time.Time(volume.CreatedAt).Unix(),
 		Attachments:      attachments,
 	}
 }
@@ -349,7 +382,7 @@ func (d *driver) Snapshots(
 }
 
 func translateSnapshot(snapshot *snapshots.Snapshot) *types.Snapshot {
-	return &types.Snapshot{
+	s := &types.Snapshot{
 		Name:        snapshot.Name,
 		VolumeID:    snapshot.VolumeID,
 		ID:          snapshot.ID,
@@ -358,6 +391,17 @@ func translateSnapshot(snapshot *snapshots.Snapshot) *types.Snapshot {
 		Description: snapshot.Description,
 		Status:      snapshot.Status,
 	}
+	switch snapshot.Status {
+	case "creating":
+		s.Status = types.SnapshotStatusPending
+	case "available":
+		s.Status = types.SnapshotStatusCompleted
+		s.Progress = 100
+	case "error":
+		s.Status = types.SnapshotStatusError
+	}
+	apiUtils.ParseSnapshotExpireTime(s, snapshot.Metadata)
+	return s
 }
 
 func (d *driver) VolumeSnapshot(
@@ -375,6 +419,11 @@ func (d *driver) VolumeSnapshot(
 		VolumeID: volumeID,
 		Force:    true,
 	}
+	if expireTime := opts.GetInt64(types.SnapshotExpireTimeKey); expireTime > 0 {
+		createOpts.Metadata = map[string]interface{}{
+			types.SnapshotExpireTimeTag: strconv.FormatInt(expireTime, 10),
+		}
+	}
 
 	snapshot, err := snapshots.Create(d.clientBlockStorage, createOpts).Extract()
 	if err != nil {
@@ -710,8 +759,7 @@ func (d *driver) SnapshotCopy(
 	ctx types.Context,
 	snapshotID, snapshotName, destinationID string,
 	opts types.Store) (*types.Snapshot, error) {
-	// TODO return nil, nil ?
-	return nil, types.ErrNotImplemented
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshot copy")
 }
 
 func (d *driver) authURL() string {
@@ -58,6 +58,10 @@ const (
 	// incoming requests that have names with underscores should be
 	// converted to dashes to satisfy DO naming requirements
 	ConfigConvertUnderscores = Name + ".convertUnderscores"
+
+	// ConfigEndpoint is the key for the URL of the DigitalOcean API. The
+	// default URL is used when the endpoint is not set.
+	ConfigEndpoint = Name + ".endpoint"
 )
 
 func init() {
@@ -78,5 +82,7 @@ func registerConfig() {
 		ConfigStatusTimeout)
 	r.Key(gofig.Bool, "", defaultConvertUnderscores,
 		"Convert Underscores", ConfigConvertUnderscores)
+	r.Key(gofig.String, "", "", "The URL of the DigitalOcean API",
+		ConfigEndpoint)
 	gofigCore.Register(r)
 }
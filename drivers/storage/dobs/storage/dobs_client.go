@@ -7,12 +7,16 @@ import (
 )
 
 // Client returns a new DigitalOcean client
-func Client(token string) (*godo.Client, error) {
+func Client(token, endpoint string) (*godo.Client, error) {
 	tokenSrc := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
 
+	opts := []godo.ClientOpt{godo.SetUserAgent(userAgent())}
+	if endpoint != "" {
+		opts = append(opts, godo.SetBaseURL(endpoint))
+	}
+
 	client, err := godo.New(oauth2.NewClient(
-		oauth2.NoContext, tokenSrc),
-		godo.SetUserAgent(userAgent()))
+		oauth2.NoContext, tokenSrc), opts...)
 
 	return client, err
 }
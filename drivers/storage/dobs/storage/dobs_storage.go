@@ -2,6 +2,8 @@ package storage
 
 import (
 	"fmt"
+	"math"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -51,6 +53,11 @@ func (d *driver) Init(
 	config gofig.Config) error {
 
 	d.config = config
+
+	if err := apiUtils.RequireConfigKeys(config, do.ConfigToken); err != nil {
+		return err
+	}
+
 	token := d.config.GetString(do.ConfigToken)
 	d.maxAttempts = d.config.GetInt(do.ConfigStatusMaxAttempts)
 
@@ -85,7 +92,12 @@ func (d *driver) Init(
 		fields["token"] = "******"
 	}
 
-	client, err := Client(token)
+	endpoint := d.config.GetString(do.ConfigEndpoint)
+	if endpoint != "" {
+		fields["endpoint"] = endpoint
+	}
+
+	client, err := Client(token, endpoint)
 	if err != nil {
 		return err
 	}
@@ -102,6 +114,13 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Block, nil
 }
 
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsSnapshots: true,
+	}, nil
+}
+
 // DigitalOcean volumes are are found using device-by-id, ex:
 // /dev/disk/by-id/scsi-0DO_Volume_volume-nyc1-01 See
 // https://www.digitalocean.com/community/tutorials/how-to-use-block-storage-on-digitalocean#preparing-volumes-for-use-in-linux
@@ -185,7 +204,7 @@ func (d *driver) VolumeInspectByName(
 		return nil, err
 	}
 	if len(doVolumes) == 0 {
-		return nil, apiUtils.NewNotFoundError(volumeName)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeName, nil)
 	}
 	if len(doVolumes) > 1 {
 		return nil, goof.New("too many volumes returned")
@@ -235,6 +254,65 @@ func (d *driver) VolumeCreate(
 		SizeGigaBytes: *opts.Size,
 	}
 
+	return d.volumeCreate(ctx, volumeReq, fields)
+}
+
+func (d *driver) VolumeCreateFromSnapshot(
+	ctx types.Context, snapshotID string, volumeName string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	volumeName = d.convUnderscores(volumeName)
+	fields := map[string]interface{}{
+		"volumeName": volumeName,
+		"snapshotID": snapshotID,
+	}
+
+	snapshot, err := d.getSnapshot(ctx, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A volume created from a snapshot must reside in the snapshot's
+	// region and be at least as large as the snapshot's source volume.
+	if len(snapshot.Regions) == 0 {
+		return nil, goof.WithFields(fields, "snapshot has no region")
+	}
+	region := snapshot.Regions[0]
+	if opts.AvailabilityZone != nil && *opts.AvailabilityZone != "" &&
+		*opts.AvailabilityZone != region {
+		fields["region"] = *opts.AvailabilityZone
+		fields["snapshotRegion"] = region
+		return nil, goof.WithFields(fields,
+			"volume region must match snapshot region")
+	}
+	fields["region"] = region
+
+	size := int64(snapshot.MinDiskSize)
+	if opts.Size != nil {
+		size = *opts.Size
+	}
+	fields["size"] = size
+
+	if size < int64(snapshot.MinDiskSize) {
+		fields["minSize"] = snapshot.MinDiskSize
+		return nil, goof.WithFields(fields, "volume size too small")
+	}
+
+	volumeReq := &godo.VolumeCreateRequest{
+		Region:        region,
+		Name:          volumeName,
+		SizeGigaBytes: size,
+		SnapshotID:    snapshot.ID,
+	}
+
+	return d.volumeCreate(ctx, volumeReq, fields)
+}
+
+func (d *driver) volumeCreate(
+	ctx types.Context,
+	volumeReq *godo.VolumeCreateRequest,
+	fields map[string]interface{}) (*types.Volume, error) {
+
 	volume, _, err := d.client.Storage.CreateVolume(ctx, volumeReq)
 	if err != nil {
 		ctx.WithFields(fields).WithError(err).Error(
@@ -249,12 +327,6 @@ func (d *driver) VolumeCreate(
 	)
 }
 
-func (d *driver) VolumeCreateFromSnapshot(
-	ctx types.Context, snapshotID string, volumeName string,
-	opts *types.VolumeCreateOpts) (*types.Volume, error) {
-	return nil, types.ErrNotImplemented
-}
-
 func (d *driver) VolumeCopy(
 	ctx types.Context, volumeID string, volumeName string,
 	opts types.Store) (*types.Volume, error) {
@@ -264,7 +336,27 @@ func (d *driver) VolumeCopy(
 func (d *driver) VolumeSnapshot(
 	ctx types.Context, volumeID string, snapshotName string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, types.ErrNotImplemented
+
+	snapshotName = d.convUnderscores(snapshotName)
+	fields := map[string]interface{}{
+		"volumeID":     volumeID,
+		"snapshotName": snapshotName,
+	}
+
+	snapshot, _, err := d.client.Storage.CreateSnapshot(
+		ctx,
+		&godo.SnapshotCreateRequest{
+			VolumeID: volumeID,
+			Name:     snapshotName,
+		},
+	)
+	if err != nil {
+		ctx.WithFields(fields).WithError(err).Error(
+			"error returned from create snapshot")
+		return nil, err
+	}
+
+	return d.toTypesSnapshot(snapshot), nil
 }
 
 func (d *driver) VolumeRemove(
@@ -397,24 +489,50 @@ func (d *driver) VolumeDetach(
 
 func (d *driver) Snapshots(
 	ctx types.Context, opts types.Store) ([]*types.Snapshot, error) {
-	return nil, types.ErrNotImplemented
+
+	doSnapshots, _, err := d.client.Snapshots.ListVolume(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*types.Snapshot
+	for i := range doSnapshots {
+		snapshots = append(snapshots, d.toTypesSnapshot(&doSnapshots[i]))
+	}
+
+	return snapshots, nil
 }
 
 func (d *driver) SnapshotInspect(
 	ctx types.Context, snapshotID string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, types.ErrNotImplemented
+
+	snapshot, err := d.getSnapshot(ctx, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	return d.toTypesSnapshot(snapshot), nil
 }
 
 func (d *driver) SnapshotCopy(
 	ctx types.Context, snapshotID string, snapshotName string, destinationID string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, types.ErrNotImplemented
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshot copy")
 }
 
 func (d *driver) SnapshotRemove(
 	ctx types.Context, snapshotID string, opts types.Store) error {
-	return types.ErrNotImplemented
+
+	if _, err := d.getSnapshot(ctx, snapshotID); err != nil {
+		return err
+	}
+
+	_, err := d.client.Snapshots.Delete(ctx, snapshotID)
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func mustInstanceIDID(ctx types.Context) *string {
@@ -449,6 +567,7 @@ func (d *driver) toTypesVolume(
 		Encrypted:        false,
 		Size:             volume.SizeGigaBytes,
 		AvailabilityZone: volume.Region.Slug,
+		CreateTime:       volume.CreatedAt.Unix(),
 	}
 
 	// Collect attachment info for the volume
@@ -462,6 +581,7 @@ func (d *driver) toTypesVolume(
 					ID:     strDropletID,
 					Driver: d.Name(),
 				},
+				Status: types.AttachmentStatusAttached,
 			}
 
 			if attachments.Devices() {
@@ -481,6 +601,38 @@ func (d *driver) toTypesVolume(
 	return vol, nil
 }
 
+func (d *driver) getSnapshot(
+	ctx types.Context,
+	snapshotID string) (*godo.Snapshot, error) {
+
+	snapshot, resp, err := d.client.Snapshots.Get(ctx, snapshotID)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, apiUtils.NewSnapshotNotFoundErr(snapshotID, err)
+		}
+		return nil, err
+	}
+	if snapshot.ResourceType != "volume" {
+		return nil, apiUtils.NewSnapshotNotFoundErr(snapshotID, nil)
+	}
+	return snapshot, nil
+}
+
+func (d *driver) toTypesSnapshot(snapshot *godo.Snapshot) *types.Snapshot {
+	s := &types.Snapshot{
+		Name:       snapshot.Name,
+		ID:         snapshot.ID,
+		VolumeID:   snapshot.ResourceID,
+		VolumeSize: int64(snapshot.MinDiskSize),
+		Size:       int64(math.Ceil(snapshot.SizeGigaBytes)),
+		Status:     types.SnapshotStatusCompleted,
+	}
+	if t, err := time.Parse(time.RFC3339, snapshot.Created); err == nil {
+		s.StartTime = t.Unix()
+	}
+	return s
+}
+
 func (d *driver) waitForAction(
 	ctx types.Context,
 	volumeID string,
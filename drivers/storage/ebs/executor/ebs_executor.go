@@ -3,7 +3,7 @@ package executor
 import (
 	"bufio"
 	"bytes"
-	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
 	"regexp"
@@ -14,6 +14,7 @@ import (
 
 	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/types"
+	apiUtils "github.com/codedellemc/libstorage/api/utils"
 	"github.com/codedellemc/libstorage/drivers/storage/ebs"
 	ebsUtils "github.com/codedellemc/libstorage/drivers/storage/ebs/utils"
 )
@@ -66,66 +67,41 @@ func (d *driver) InstanceID(
 	return ebsUtils.InstanceID(ctx, d.Name())
 }
 
-var errNoAvaiDevice = goof.New("no available device")
-
 // NextDevice returns the next available device.
 func (d *driver) NextDevice(
 	ctx types.Context,
 	opts types.Store) (string, error) {
-	// All possible device paths on Linux EC2 instances are /dev/xvd[f-p]
-	letters := []string{
-		"f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p"}
-
-	// Find which letters are used for local devices
-	localDeviceNames := make(map[string]bool)
-
 	localDevices, err := d.LocalDevices(
 		ctx, &types.LocalDevicesOpts{Opts: opts})
 	if err != nil {
 		return "", goof.WithError("error getting local devices", err)
 	}
-	localDeviceMapping := localDevices.DeviceMap
-
-	for localDevice := range localDeviceMapping {
-		re, _ := regexp.Compile(`^/dev/` +
-			ebsUtils.NextDeviceInfo.Prefix +
-			`(` + ebsUtils.NextDeviceInfo.Pattern + `)`)
-		res := re.FindStringSubmatch(localDevice)
-		if len(res) > 0 {
-			localDeviceNames[res[1]] = true
-		}
+
+	var inUse []string
+	for localDevice := range localDevices.DeviceMap {
+		inUse = append(inUse, localDevice)
 	}
 
-	// Find which letters are used for ephemeral devices
+	// Ephemeral devices are not always present in the local devices
 	ephemeralDevices, err := d.getEphemeralDevices(ctx)
 	if err != nil {
 		return "", goof.WithError("error getting ephemeral devices", err)
 	}
+	inUse = append(inUse, ephemeralDevices...)
 
-	for _, ephemeralDevice := range ephemeralDevices {
-		re, _ := regexp.Compile(`^` +
-			ebsUtils.NextDeviceInfo.Prefix +
-			`(` + ebsUtils.NextDeviceInfo.Pattern + `)`)
-		res := re.FindStringSubmatch(ephemeralDevice)
-		if len(res) > 0 {
-			localDeviceNames[res[1]] = true
-		}
-	}
-
-	// Find next available letter for device path
-	for _, letter := range letters {
-		if localDeviceNames[letter] {
-			continue
-		}
-		return fmt.Sprintf(
-			"/dev/%s%s", ebsUtils.NextDeviceInfo.Prefix, letter), nil
-	}
-	return "", errNoAvaiDevice
+	// All possible device paths on Linux EC2 instances are /dev/xvd[f-p]
+	return apiUtils.GetNextAvailableDevice(ebsUtils.NextDeviceInfo, inUse)
 }
 
 const procPartitions = "/proc/partitions"
 
-var xvdRX = regexp.MustCompile(`^xvd[a-z]$`)
+var (
+	xvdRX  = regexp.MustCompile(`^xvd[a-z]$`)
+	nvmeRX = regexp.MustCompile(`^nvme[0-9]+n[0-9]+$`)
+)
+
+// sysBlock is the directory from which an NVMe device's serial is read.
+const sysBlock = "/sys/block"
 
 // Retrieve device paths currently attached and/or mounted
 func (d *driver) LocalDevices(
@@ -147,11 +123,26 @@ func (d *driver) LocalDevices(
 			continue
 		}
 		devName := fields[3]
-		if !xvdRX.MatchString(devName) {
+		devPath := path.Join("/dev/", devName)
+		if xvdRX.MatchString(devName) {
+			devMap[devPath] = devPath
 			continue
 		}
-		devPath := path.Join("/dev/", devName)
-		devMap[devPath] = devPath
+		if !nvmeRX.MatchString(devName) {
+			continue
+		}
+		// an nvme device is keyed by the id of the volume it exposes
+		serial, err := ioutil.ReadFile(
+			path.Join(sysBlock, devName, "device", "serial"))
+		if err != nil {
+			ctx.WithField("device", devPath).WithError(err).Debug(
+				"error reading nvme device serial")
+			continue
+		}
+		if volumeID := ebsUtils.VolumeIDFromSerial(
+			string(serial)); volumeID != "" {
+			devMap[volumeID] = devPath
+		}
 	}
 
 	ld := &types.LocalDevices{Driver: d.Name()}
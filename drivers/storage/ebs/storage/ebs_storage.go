@@ -14,6 +14,7 @@ import (
 	"github.com/akutz/goof"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
@@ -71,7 +72,7 @@ func (d *driver) Name() string {
 }
 
 // Init initializes the driver.
-func (d *driver) Init(context types.Context, config gofig.Config) error {
+func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	// Ensure backwards compatibility with ebs and ec2 in config
 	ebs.BackCompat(config)
 	d.config = config
@@ -101,7 +102,7 @@ func (d *driver) Init(context types.Context, config gofig.Config) error {
 		return err
 	}
 
-	log.Info("storage driver initialized")
+	ctx.Info("storage driver initialized")
 	return nil
 }
 
@@ -109,9 +110,19 @@ const cacheKeyC = "cacheKey"
 
 var (
 	sessions  = map[string]*awsec2.EC2{}
-	sessionsL = &sync.Mutex{}
+	sessionsL = &sync.RWMutex{}
 )
 
+// cachedSession returns the cached service for the key. The cache is read
+// under a shared lock so that concurrent requests do not serialize on the
+// common case.
+func cachedSession(ckey string) (*awsec2.EC2, bool) {
+	sessionsL.RLock()
+	defer sessionsL.RUnlock()
+	svc, ok := sessions[ckey]
+	return svc, ok
+}
+
 func writeHkey(h hash.Hash, ps *string) {
 	if ps == nil {
 		return
@@ -120,9 +131,6 @@ func writeHkey(h hash.Hash, ps *string) {
 }
 
 func (d *driver) Login(ctx types.Context) (interface{}, error) {
-	sessionsL.Lock()
-	defer sessionsL.Unlock()
-
 	var (
 		endpoint *string
 		ckey     string
@@ -144,8 +152,17 @@ func (d *driver) Login(ctx types.Context) (interface{}, error) {
 	ckey = fmt.Sprintf("%x", hkey.Sum(nil))
 
 	// if the session is cached then return it
+	if svc, ok := cachedSession(ckey); ok {
+		ctx.WithField(cacheKeyC, ckey).Debug("using cached ebs service")
+		return svc, nil
+	}
+
+	sessionsL.Lock()
+	defer sessionsL.Unlock()
+
+	// another request may have created the session while this one waited
+	// for the lock
 	if svc, ok := sessions[ckey]; ok {
-		log.WithField(cacheKeyC, ckey).Debug("using cached ebs service")
 		return svc, nil
 	}
 
@@ -170,7 +187,7 @@ func (d *driver) Login(ctx types.Context) (interface{}, error) {
 		fields[ebs.Endpoint] = *endpoint
 	}
 
-	log.WithFields(fields).Debug("ebs service connetion attempt")
+	ctx.WithFields(fields).Debug("ebs service connetion attempt")
 	sess := session.New()
 
 	svc := awsec2.New(
@@ -198,7 +215,7 @@ func (d *driver) Login(ctx types.Context) (interface{}, error) {
 	)
 
 	sessions[ckey] = svc
-	log.WithFields(fields).Info("ebs service connetion created & cached")
+	ctx.WithFields(fields).Info("ebs service connetion created & cached")
 
 	return svc, nil
 }
@@ -244,6 +261,90 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Block, nil
 }
 
+// Capabilities returns the features supported by the driver.
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsEncryption: true,
+	}, nil
+}
+
+// volumeTypes are the EBS volume types the driver can create. Only io1
+// volumes accept provisioned IOPS.
+var volumeTypes = []*types.VolumeType{
+	{
+		Name:        "standard",
+		Description: "Magnetic",
+		MinSize:     1,
+		MaxSize:     1024,
+	},
+	{
+		Name:        "gp2",
+		Description: "General Purpose SSD",
+		MinSize:     1,
+		MaxSize:     16384,
+	},
+	{
+		Name:         "io1",
+		Description:  "Provisioned IOPS SSD",
+		MinSize:      4,
+		MaxSize:      16384,
+		MinIOPS:      100,
+		MaxIOPS:      20000,
+		SupportsIOPS: true,
+	},
+	{
+		Name:        "st1",
+		Description: "Throughput Optimized HDD",
+		MinSize:     500,
+		MaxSize:     16384,
+	},
+	{
+		Name:        "sc1",
+		Description: "Cold HDD",
+		MinSize:     500,
+		MaxSize:     16384,
+	},
+}
+
+// VolumeTypes returns the EBS volume types.
+func (d *driver) VolumeTypes(
+	ctx types.Context,
+	opts types.Store) ([]*types.VolumeType, error) {
+	vts := make([]*types.VolumeType, len(volumeTypes))
+	for i, vt := range volumeTypes {
+		vtc := *vt
+		vts[i] = &vtc
+	}
+	return vts, nil
+}
+
+// MaxVolumeCount returns the number of volumes that may be attached to the
+// instance based on its type and number of network interfaces.
+func (d *driver) MaxVolumeCount(
+	ctx types.Context,
+	opts types.Store) (int, error) {
+
+	inst, err := d.getInstance(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return ebsUtils.MaxVolumeCount(
+		aws.StringValue(inst.InstanceType),
+		len(inst.NetworkInterfaces)), nil
+}
+
+// Ping verifies the EC2 service is reachable and the configured credentials
+// are valid.
+func (d *driver) Ping(ctx types.Context) error {
+	req, _ := mustSession(ctx).DescribeVolumesRequest(
+		&awsec2.DescribeVolumesInput{MaxResults: aws.Int64(5)})
+	if err := ebsUtils.SendRequest(ctx, req); err != nil {
+		return goof.WithError("error describing ec2 volumes", err)
+	}
+	return nil
+}
+
 // InstanceInspect returns an instance.
 func (d *driver) InstanceInspect(
 	ctx types.Context,
@@ -251,10 +352,11 @@ func (d *driver) InstanceInspect(
 
 	iid := context.MustInstanceID(ctx)
 	return &types.Instance{
-		Name:         iid.ID,
-		Region:       iid.Fields[ebs.InstanceIDFieldRegion],
-		InstanceID:   iid,
-		ProviderName: iid.Driver,
+		Name:             iid.ID,
+		Region:           iid.Fields[ebs.InstanceIDFieldRegion],
+		AvailabilityZone: iid.Fields[ebs.InstanceIDFieldAvailabilityZone],
+		InstanceID:       iid,
+		ProviderName:     iid.Driver,
 	}, nil
 }
 
@@ -278,6 +380,118 @@ func (d *driver) Volumes(
 	return vols, nil
 }
 
+// InstanceAttachments returns the attachments of the volumes attached to the
+// instance. The attachments are filtered by the EC2 API.
+func (d *driver) InstanceAttachments(
+	ctx types.Context,
+	instanceID string,
+	opts types.Store) ([]*types.VolumeAttachment, error) {
+
+	dvInput := &awsec2.DescribeVolumesInput{
+		Filters: []*awsec2.Filter{{
+			Name:   aws.String("attachment.instance-id"),
+			Values: []*string{aws.String(instanceID)},
+		}},
+	}
+	req, resp := mustSession(ctx).DescribeVolumesRequest(dvInput)
+	if err := ebsUtils.SendRequest(ctx, req); err != nil {
+		return nil, goof.WithFieldE(
+			"instanceID", instanceID, "error getting attachments", err)
+	}
+
+	// the local devices describe only the instance in the context
+	var devMap map[string]string
+	if iid, ok := context.InstanceID(ctx); ok && iid.ID == instanceID {
+		if ld, ok := context.LocalDevices(ctx); ok {
+			devMap = ld.DeviceMap
+		}
+	}
+
+	var atts []*types.VolumeAttachment
+	for _, v := range resp.Volumes {
+		for _, a := range v.Attachments {
+			if a.InstanceId == nil || *a.InstanceId != instanceID {
+				continue
+			}
+			// compensate for kernel volume mapping i.e. change "/dev/sda"
+			// to "/dev/xvda"
+			requestedName := strings.Replace(
+				*a.Device, "sd", ebsUtils.NextDeviceInfo.Prefix, 1)
+			deviceName := requestedName
+			if v := ebsUtils.ResolveDevicePath(
+				requestedName, *a.VolumeId, devMap); v != "" {
+				deviceName = v
+			}
+			atts = append(atts, &types.VolumeAttachment{
+				VolumeID: *a.VolumeId,
+				InstanceID: &types.InstanceID{
+					ID:     instanceID,
+					Driver: d.Name(),
+				},
+				DeviceName:          deviceName,
+				RequestedDeviceName: requestedName,
+				Status:              *a.State,
+			})
+		}
+	}
+	return atts, nil
+}
+
+// FindVolumes returns the volumes that match the filter. The filter is
+// applied by the EC2 API.
+func (d *driver) FindVolumes(
+	ctx types.Context,
+	filter *types.VolumeFilter,
+	opts *types.VolumesOpts) ([]*types.Volume, error) {
+
+	filters := []*awsec2.Filter{}
+
+	if filter.AvailabilityZone != "" {
+		filters = append(filters, &awsec2.Filter{
+			Name:   aws.String("availability-zone"),
+			Values: []*string{aws.String(filter.AvailabilityZone)},
+		})
+	} else if avaiZone := d.mustAvailabilityZone(ctx); avaiZone != nil {
+		filters = append(filters, &awsec2.Filter{
+			Name:   aws.String("availability-zone"),
+			Values: []*string{avaiZone},
+		})
+	}
+
+	if filter.Name != "" {
+		filters = append(filters, &awsec2.Filter{
+			Name:   aws.String("tag:Name"),
+			Values: []*string{aws.String(filter.Name)},
+		})
+	}
+
+	if filter.Status != "" {
+		filters = append(filters, &awsec2.Filter{
+			Name:   aws.String("status"),
+			Values: []*string{aws.String(filter.Status)},
+		})
+	}
+
+	for k, v := range filter.Tags {
+		filters = append(filters, &awsec2.Filter{
+			Name:   aws.String("tag:" + k),
+			Values: []*string{aws.String(v)},
+		})
+	}
+
+	dvInput := &awsec2.DescribeVolumesInput{Filters: filters}
+	req, resp := mustSession(ctx).DescribeVolumesRequest(dvInput)
+	if err := ebsUtils.SendRequest(ctx, req); err != nil {
+		return nil, goof.WithError("error finding volumes", err)
+	}
+
+	vols, err := d.toTypesVolume(ctx, resp.Volumes, opts.Attachments)
+	if err != nil {
+		return nil, goof.WithError("error converting to types.Volume", err)
+	}
+	return vols, nil
+}
+
 // VolumeInspect inspects a single volume.
 func (d *driver) VolumeInspect(
 	ctx types.Context,
@@ -286,10 +500,14 @@ func (d *driver) VolumeInspect(
 	// Get volume corresponding to volume ID via EC2 API
 	ec2vols, err := d.getVolume(ctx, volumeID, "")
 	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok &&
+			aerr.Code() == "InvalidVolume.NotFound" {
+			return nil, apiUtils.NewVolumeNotFoundErr(volumeID, err)
+		}
 		return nil, goof.WithError("error getting volume", err)
 	}
 	if len(ec2vols) == 0 {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 	vols, convErr := d.toTypesVolume(ctx, ec2vols, opts.Attachments)
 	if convErr != nil {
@@ -325,7 +543,7 @@ func (d *driver) VolumeCreate(ctx types.Context, volumeName string,
 	}
 
 	if len(volumes) > 0 {
-		return nil, goof.New("volume name already exists")
+		return nil, apiUtils.NewAlreadyExistsErr(volumeName)
 	}
 
 	// Pass libStorage types.Volume to helper function which calls EC2 API
@@ -355,7 +573,7 @@ func (d *driver) VolumeCreateFromSnapshot(
 			"opts":       opts,
 		}
 
-		log.WithFields(fields).Debug("creating volume from snapshot")
+		ctx.WithFields(fields).Debug("creating volume from snapshot")
 
 		// Check if volume with same name exists
 		ec2vols, err := d.getVolume(ctx, "", volumeName)
@@ -437,7 +655,7 @@ func (d *driver) VolumeCopy(
 			"opts":       opts,
 		}
 
-		log.WithFields(fields).Debug("creating volume from snapshot")
+		ctx.WithFields(fields).Debug("creating volume from snapshot")
 
 		// Check if volume with same name exists
 		ec2VolsToCheck, err := d.getVolume(ctx, "", volumeName)
@@ -569,10 +787,40 @@ func (d *driver) VolumeRemove(
 	return nil
 }
 
-var (
-	errMissingNextDevice  = goof.New("missing next device")
-	errVolAlreadyAttached = goof.New("volume already attached to a host")
-)
+var errMissingNextDevice = goof.New("missing next device")
+
+// VolumeSetTags adds the provided tags to a volume.
+func (d *driver) VolumeSetTags(
+	ctx types.Context,
+	volumeID string,
+	tags map[string]string,
+	opts types.Store) error {
+
+	if volumeID == "" {
+		return errMissingVolID
+	}
+	return d.setTags(ctx, volumeID, tags)
+}
+
+// VolumeRename renames a volume by updating its Name tag.
+func (d *driver) VolumeRename(
+	ctx types.Context,
+	volumeID, newName string,
+	opts types.Store) (*types.Volume, error) {
+
+	if volumeID == "" {
+		return nil, errMissingVolID
+	}
+	if err := d.createTags(ctx, volumeID, newName); err != nil {
+		return nil, goof.WithFieldE(
+			"volumeID", volumeID, "error renaming volume", err)
+	}
+	return d.VolumeInspect(
+		ctx, volumeID, &types.VolumeInspectOpts{
+			Attachments: types.VolAttReqTrue,
+			Opts:        opts,
+		})
+}
 
 // VolumeAttach attaches a volume and provides a token clients can use
 // to validate that device has appeared locally.
@@ -600,7 +848,7 @@ func (d *driver) VolumeAttach(
 	if len(volumes[0].Attachments) > 0 {
 		// Detach already attached volume if forced
 		if !opts.Force {
-			return nil, "", errVolAlreadyAttached
+			return nil, "", apiUtils.NewVolumeAlreadyAttachedErr(volumeID)
 		}
 		_, err := d.VolumeDetach(
 			ctx,
@@ -646,12 +894,15 @@ func (d *driver) VolumeAttach(
 	}
 
 	// Token is the attachment's device name, which will be matched
-	// to the executor's device ID
+	// to the executor's device ID. Nitro instances expose the volume as an
+	// nvme device that the executor identifies by the volume's ID.
+	if ld, ok := context.LocalDevices(ctx); ok &&
+		ebsUtils.HasNVMeDevices(ld.DeviceMap) {
+		return attachedVol, volumeID, nil
+	}
 	return attachedVol, *opts.NextDevice, nil
 }
 
-var errVolAlreadyDetached = goof.New("volume already detached")
-
 // VolumeDetach detaches a volume.
 func (d *driver) VolumeDetach(
 	ctx types.Context,
@@ -675,7 +926,7 @@ func (d *driver) VolumeDetach(
 
 	// volume has no attachments
 	if len(volumes[0].Attachments) == 0 {
-		return nil, errVolAlreadyDetached
+		return nil, apiUtils.NewVolumeNotAttachedErr(volumeID)
 	}
 
 	dvInput := &awsec2.DetachVolumeInput{
@@ -805,7 +1056,7 @@ func (d *driver) SnapshotCopy(
 				"Error creating tags", err)
 		}
 
-		log.WithFields(log.Fields{
+		ctx.WithFields(log.Fields{
 			"moduleName":      d.Name(),
 			"driverName":      d.Name(),
 			"snapshotName":    snapshotName,
@@ -911,8 +1162,8 @@ func (d *driver) getVolume(
 	}
 
 	// Retrieve filtered volumes through EC2 API call
-	resp, err := mustSession(ctx).DescribeVolumes(dvInput)
-	if err != nil {
+	req, resp := mustSession(ctx).DescribeVolumesRequest(dvInput)
+	if err := ebsUtils.SendRequest(ctx, req); err != nil {
 		return []*awsec2.Volume{}, err
 	}
 
@@ -946,17 +1197,17 @@ func (d *driver) toTypesVolume(
 		if attachments.Requested() {
 			// Leave attachment's device name blank if attachments is false
 			for _, attachment := range volume.Attachments {
-				deviceName := ""
+				deviceName, requestedName := "", ""
 				if attachments.Devices() {
 					// Compensate for kernel volume mapping i.e. change
 					// "/dev/sda" to "/dev/xvda"
-					deviceName = strings.Replace(
+					requestedName = strings.Replace(
 						*attachment.Device, "sd",
 						ebsUtils.NextDeviceInfo.Prefix, 1)
-					// Keep device name if it is found in local devices
-					if _, ok := ld.DeviceMap[deviceName]; !ok {
-						deviceName = ""
-					}
+					// Keep device name if it is found in local devices,
+					// either as requested or as an nvme device
+					deviceName = ebsUtils.ResolveDevicePath(
+						requestedName, *attachment.VolumeId, ld.DeviceMap)
 				}
 				attachmentSD := &types.VolumeAttachment{
 					VolumeID: *attachment.VolumeId,
@@ -964,8 +1215,9 @@ func (d *driver) toTypesVolume(
 						ID:     *attachment.InstanceId,
 						Driver: d.Name(),
 					},
-					DeviceName: deviceName,
-					Status:     *attachment.State,
+					DeviceName:          deviceName,
+					RequestedDeviceName: requestedName,
+					Status:              *attachment.State,
 				}
 				attachmentsSD = append(attachmentsSD, attachmentSD)
 			}
@@ -980,9 +1232,18 @@ func (d *driver) toTypesVolume(
 			Status:           *volume.State,
 			Type:             *volume.VolumeType,
 			Size:             *volume.Size,
+			Tags:             d.getTags(volume.Tags),
 			Attachments:      attachmentsSD,
 		}
 
+		if volume.CreateTime != nil {
+			volumeSD.CreateTime = volume.CreateTime.Unix()
+		}
+
+		if volume.KmsKeyId != nil {
+			volumeSD.EncryptionKey = *volume.KmsKeyId
+		}
+
 		// Some volume types have no IOPS, so we get nil in volume.Iops
 		if volume.Iops != nil {
 			volumeSD.IOPS = *volume.Iops
@@ -1094,10 +1355,8 @@ func (d *driver) attachVolume(
 		VolumeId:   &volumeID,
 	}
 
-	if _, err := mustSession(ctx).AttachVolume(avInput); err != nil {
-		return err
-	}
-	return nil
+	req, _ := mustSession(ctx).AttachVolumeRequest(avInput)
+	return ebsUtils.SendRequest(ctx, req)
 }
 
 // Used in VolumeCreate
@@ -1143,9 +1402,8 @@ func (d *driver) createVolume(
 		}
 	}
 
-	var resp *awsec2.Volume
-
-	if resp, err = mustSession(ctx).CreateVolume(options); err != nil {
+	req, resp := mustSession(ctx).CreateVolumeRequest(options)
+	if err = ebsUtils.SendRequest(ctx, req); err != nil {
 		return &awsec2.Volume{}, goof.WithError(
 			"error creating volume", err)
 	}
@@ -1155,6 +1413,11 @@ func (d *driver) createVolume(
 		return &awsec2.Volume{}, goof.WithError(
 			"error creating tags", err)
 	}
+	if len(opts.Tags) > 0 {
+		if err = d.setTags(ctx, *resp.VolumeId, opts.Tags); err != nil {
+			return &awsec2.Volume{}, err
+		}
+	}
 
 	// Wait for volume status to change
 	if err = d.waitVolumeComplete(
@@ -1219,6 +1482,29 @@ func (d *driver) createTags(ctx types.Context, id, name string) (err error) {
 	return nil
 }
 
+// Add user-defined tags to a volume or snapshot
+func (d *driver) setTags(
+	ctx types.Context, id string, tags map[string]string) error {
+
+	ctInput := &awsec2.CreateTagsInput{
+		Resources: []*string{&id},
+		Tags:      []*awsec2.Tag{},
+	}
+	for k, v := range tags {
+		ctInput.Tags = append(
+			ctInput.Tags,
+			&awsec2.Tag{
+				Key:   aws.String(k),
+				Value: aws.String(v),
+			})
+	}
+
+	if _, err := mustSession(ctx).CreateTags(ctInput); err != nil {
+		return goof.WithError("error creating tags", err)
+	}
+	return nil
+}
+
 var errMissingVolID = goof.New("missing volume ID")
 
 // Wait for volume action to complete (creation, attachment, detachment)
@@ -1260,7 +1546,11 @@ func (d *driver) waitVolumeComplete(
 			ctx.WithField("action", action).Debug(
 				"still waiting for action",
 			)
-			time.Sleep(time.Duration(duration) * time.Nanosecond)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(duration) * time.Nanosecond):
+			}
 			duration = int64(2) * duration
 		}
 		return nil, goof.WithField("maxAttempts", d.maxAttempts,
@@ -1326,6 +1616,24 @@ func (d *driver) getName(tags []*awsec2.Tag) string {
 	return ""
 }
 
+// Retrieve volume or snapshot tags, excluding the name tag
+func (d *driver) getTags(tags []*awsec2.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	m := map[string]string{}
+	for _, tag := range tags {
+		if tag.Key == nil || *tag.Key == "Name" || tag.Value == nil {
+			continue
+		}
+		m[*tag.Key] = *tag.Value
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
 // Retrieve current instance using EC2 API call
 func (d *driver) getInstance(ctx types.Context) (awsec2.Instance, error) {
 	diInput := &awsec2.DescribeInstancesInput{
@@ -8,7 +8,10 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws/request"
+
 	"github.com/codedellemc/libstorage/api/types"
+	apiUtils "github.com/codedellemc/libstorage/api/utils"
 	"github.com/codedellemc/libstorage/drivers/storage/ebs"
 )
 
@@ -126,3 +129,51 @@ func BlockDeviceName(
 
 	return buf, nil
 }
+
+// sendRequest sends an AWS request. If the context is done before the
+// request completes then the context's error is returned. Throttling and
+// server errors are returned as ErrTemporary errors so the server retries
+// the operation. A summary of the request and its response is logged at the
+// debug level. The summary omits the request's parameters and headers so
+// that credentials are never logged.
+func sendRequest(ctx types.Context, req *request.Request) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	start := time.Now()
+	err := req.Send()
+	logRequestSummary(ctx, req, time.Since(start), err)
+	if err != nil {
+		if cerr := ctx.Err(); cerr != nil {
+			return cerr
+		}
+		if apiUtils.IsTemporaryErr(err) {
+			return apiUtils.NewTemporaryErr(err)
+		}
+		return err
+	}
+	return nil
+}
+
+func logRequestSummary(
+	ctx types.Context,
+	req *request.Request,
+	duration time.Duration,
+	err error) {
+
+	fields := map[string]interface{}{
+		"duration":  duration.String(),
+		"requestID": req.RequestID,
+	}
+	if req.Operation != nil {
+		fields["operation"] = req.Operation.Name
+	}
+	if req.HTTPResponse != nil {
+		fields["statusCode"] = req.HTTPResponse.StatusCode
+	}
+	if err != nil {
+		ctx.WithFields(fields).WithError(err).Debug("ec2 request failed")
+		return
+	}
+	ctx.WithFields(fields).Debug("ec2 request")
+}
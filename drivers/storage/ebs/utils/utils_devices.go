@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Nitro instances do not attach a volume at the requested device name.
+// Instead the volume appears as an NVMe namespace, such as /dev/nvme1n1,
+// whose serial is the volume's ID without its dash. The executor maps each
+// such device to the volume's ID so the requested device name can be
+// resolved to the device's actual path.
+
+var nvmeRX = regexp.MustCompile(`^/dev/nvme[0-9]+n[0-9]+$`)
+
+// IsNVMeDevice returns a flag indicating whether or not the device path is
+// an NVMe namespace.
+func IsNVMeDevice(devPath string) bool {
+	return nvmeRX.MatchString(devPath)
+}
+
+// VolumeIDFromSerial returns the ID of the volume with the provided NVMe
+// serial, ex. "vol0123456789abcdef0". An empty string is returned if the
+// serial is not that of an EBS volume.
+func VolumeIDFromSerial(serial string) string {
+	serial = strings.TrimSpace(serial)
+	if !strings.HasPrefix(serial, "vol") || len(serial) == 3 {
+		return ""
+	}
+	if strings.HasPrefix(serial, "vol-") {
+		return serial
+	}
+	return "vol-" + serial[3:]
+}
+
+// HasNVMeDevices returns a flag indicating whether or not the local device
+// map includes NVMe devices, which indicates the instance is a Nitro
+// instance.
+func HasNVMeDevices(devMap map[string]string) bool {
+	for _, v := range devMap {
+		if IsNVMeDevice(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveDevicePath returns the path of the local device at which the
+// volume appears. The volume's ID is matched first, and then the requested
+// device name. An empty string is returned if the volume does not appear
+// in the local device map.
+func ResolveDevicePath(
+	requested, volumeID string,
+	devMap map[string]string) string {
+
+	if v, ok := devMap[volumeID]; ok {
+		return v
+	}
+	if _, ok := devMap[requested]; ok {
+		return requested
+	}
+	return ""
+}
+
+const (
+	// nitroMaxAttachments is the number of attachments a Nitro instance
+	// supports. Network interfaces count towards the limit.
+	nitroMaxAttachments = 28
+
+	// xenMaxVolumes is the number of volumes AWS supports attaching to a
+	// Xen instance running Linux.
+	xenMaxVolumes = 40
+)
+
+// nitroTypeRX matches the families of the instance types built on the Nitro
+// system as well as the bare metal instance types.
+var nitroTypeRX = regexp.MustCompile(
+	`^((a1|c5|c5a|c5ad|c5d|c5n|c6[a-z]*|c7[a-z]*|d3|d3en|dl1|g4[a-z]*|` +
+		`g5[a-z]*|g6[a-z]*|hpc[0-9a-z]*|i3en|i4[a-z]*|im4gn|inf[0-9]|is4gen|` +
+		`m5[a-z]*|m6[a-z]*|m7[a-z]*|p3dn|p4[a-z]*|p5|r5[a-z]*|r6[a-z]*|` +
+		`r7[a-z]*|t3|t3a|t4g|trn[0-9][a-z]*|u-[0-9a-z]+|vt1|x2[a-z]*|z1d)\.|` +
+		`.*\.metal)`)
+
+// IsNitroInstanceType returns a flag indicating whether or not the instance
+// type, ex. "m5.large", is built on the Nitro system.
+func IsNitroInstanceType(instanceType string) bool {
+	return nitroTypeRX.MatchString(instanceType)
+}
+
+// MaxVolumeCount returns the number of volumes that may be attached to an
+// instance of the provided type with the provided number of network
+// interfaces.
+func MaxVolumeCount(instanceType string, networkInterfaces int) int {
+	if !IsNitroInstanceType(instanceType) {
+		return xenMaxVolumes
+	}
+	if n := nitroMaxAttachments - networkInterfaces; n > 0 {
+		return n
+	}
+	return 0
+}
@@ -5,6 +5,8 @@ package utils
 import (
 	"net/http"
 
+	"github.com/aws/aws-sdk-go/aws/request"
+
 	"github.com/codedellemc/libstorage/api/types"
 )
 
@@ -19,3 +21,10 @@ func doRequestWithClient(
 	req = req.WithContext(ctx)
 	return client.Do(req)
 }
+
+// SendRequest sends an AWS request, aborting the request if the context is
+// done before the request completes.
+func SendRequest(ctx types.Context, req *request.Request) error {
+	req.HTTPRequest = req.HTTPRequest.WithContext(ctx)
+	return sendRequest(ctx, req)
+}
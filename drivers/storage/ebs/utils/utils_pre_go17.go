@@ -5,6 +5,7 @@ package utils
 import (
 	"net/http"
 
+	"github.com/aws/aws-sdk-go/aws/request"
 	"golang.org/x/net/context/ctxhttp"
 
 	"github.com/codedellemc/libstorage/api/types"
@@ -20,3 +21,10 @@ func doRequestWithClient(
 	req *http.Request) (*http.Response, error) {
 	return ctxhttp.Do(ctx, client, req)
 }
+
+// SendRequest sends an AWS request, aborting the request if the context is
+// done before the request completes.
+func SendRequest(ctx types.Context, req *request.Request) error {
+	req.HTTPRequest.Cancel = ctx.Done()
+	return sendRequest(ctx, req)
+}
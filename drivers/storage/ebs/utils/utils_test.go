@@ -1,13 +1,23 @@
 package utils
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/stretchr/testify/assert"
+	gocontext "golang.org/x/net/context"
 
 	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	apiUtils "github.com/codedellemc/libstorage/api/utils"
 
 	"github.com/codedellemc/libstorage/drivers/storage/ebs"
 )
@@ -26,3 +36,100 @@ func TestInstanceID(t *testing.T) {
 	}
 	t.Logf("instanceID=%s", iid.String())
 }
+
+func TestSendRequestCancel(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			<-done
+		}))
+	defer srv.Close()
+	defer close(done)
+
+	svc := awsec2.New(session.New(), &aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		MaxRetries:  aws.Int(0),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+
+	ctx, cancel := gocontext.WithTimeout(
+		context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	req, _ := svc.DescribeVolumesRequest(&awsec2.DescribeVolumesInput{})
+
+	start := time.Now()
+	err := SendRequest(context.New(ctx), req)
+	assert.Equal(t, gocontext.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
+
+func TestSendRequestThrottled(t *testing.T) {
+	status, code := http.StatusServiceUnavailable, "RequestLimitExceeded"
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(`<Response><Errors><Error>` +
+				`<Code>` + code + `</Code><Message>failed</Message>` +
+				`</Error></Errors><RequestID>1</RequestID></Response>`))
+		}))
+	defer srv.Close()
+
+	svc := awsec2.New(session.New(), &aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		MaxRetries:  aws.Int(0),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+
+	req, _ := svc.DescribeVolumesRequest(&awsec2.DescribeVolumesInput{})
+	err := SendRequest(context.Background(), req)
+	assert.IsType(t, &types.ErrTemporary{}, err)
+
+	status, code = http.StatusBadRequest, "Throttling"
+	req, _ = svc.DescribeVolumesRequest(&awsec2.DescribeVolumesInput{})
+	err = SendRequest(context.Background(), req)
+	assert.IsType(t, &types.ErrTemporary{}, err, "throttling error code")
+
+	status, code = http.StatusBadRequest, "InvalidParameterValue"
+	req, _ = svc.DescribeVolumesRequest(&awsec2.DescribeVolumesInput{})
+	err = SendRequest(context.Background(), req)
+	if assert.Error(t, err) {
+		assert.False(t, apiUtils.IsTemporaryErr(err))
+	}
+}
+
+func TestVolumeIDFromSerial(t *testing.T) {
+	assert.Equal(t, "vol-0123456789abcdef0",
+		VolumeIDFromSerial("vol0123456789abcdef0\n"))
+	assert.Equal(t, "vol-0123", VolumeIDFromSerial("vol-0123"))
+	assert.Equal(t, "", VolumeIDFromSerial("vol"))
+	assert.Equal(t, "", VolumeIDFromSerial("AWS1234"))
+}
+
+func TestResolveDevicePath(t *testing.T) {
+	xen := map[string]string{"/dev/xvdf": "/dev/xvdf"}
+	nitro := map[string]string{
+		"vol-0000": "/dev/nvme0n1",
+		"vol-0123": "/dev/nvme1n1",
+	}
+
+	assert.False(t, HasNVMeDevices(xen))
+	assert.True(t, HasNVMeDevices(nitro))
+
+	assert.Equal(t, "/dev/xvdf",
+		ResolveDevicePath("/dev/xvdf", "vol-0123", xen))
+	assert.Equal(t, "/dev/nvme1n1",
+		ResolveDevicePath("/dev/xvdf", "vol-0123", nitro))
+	assert.Equal(t, "", ResolveDevicePath("/dev/xvdg", "vol-0456", nitro))
+}
+
+func TestMaxVolumeCount(t *testing.T) {
+	assert.Equal(t, 27, MaxVolumeCount("m5.large", 1))
+	assert.Equal(t, 25, MaxVolumeCount("c5n.18xlarge", 3))
+	assert.Equal(t, 27, MaxVolumeCount("i3.metal", 1))
+	assert.Equal(t, 40, MaxVolumeCount("m4.large", 1))
+	assert.Equal(t, 40, MaxVolumeCount("t2.micro", 2))
+	assert.Equal(t, 0, MaxVolumeCount("m5.large", 30))
+}
@@ -119,9 +119,19 @@ const cacheKeyC = "cacheKey"
 
 var (
 	sessions  = map[string]*awsefs.EFS{}
-	sessionsL = &sync.Mutex{}
+	sessionsL = &sync.RWMutex{}
 )
 
+// cachedSession returns the cached service for the key. The cache is read
+// under a shared lock so that concurrent requests do not serialize on the
+// common case.
+func cachedSession(ckey string) (*awsefs.EFS, bool) {
+	sessionsL.RLock()
+	defer sessionsL.RUnlock()
+	svc, ok := sessions[ckey]
+	return svc, ok
+}
+
 func writeHkey(h hash.Hash, ps *string) {
 	if ps == nil {
 		return
@@ -130,9 +140,6 @@ func writeHkey(h hash.Hash, ps *string) {
 }
 
 func (d *driver) Login(ctx types.Context) (interface{}, error) {
-	sessionsL.Lock()
-	defer sessionsL.Unlock()
-
 	var (
 		endpoint *string
 		ckey     string
@@ -155,10 +162,19 @@ func (d *driver) Login(ctx types.Context) (interface{}, error) {
 		ckey = fmt.Sprintf("%x", hkey.Sum(nil))
 
 		// if the session is cached then return it
-		if svc, ok := sessions[ckey]; ok {
+		if svc, ok := cachedSession(ckey); ok {
 			ctx.WithField(cacheKeyC, ckey).Debug("using cached efs service")
 			return svc, nil
 		}
+
+		sessionsL.Lock()
+		defer sessionsL.Unlock()
+
+		// another request may have created the session while this one
+		// waited for the lock
+		if svc, ok := sessions[ckey]; ok {
+			return svc, nil
+		}
 	}
 
 	var (
@@ -282,10 +298,11 @@ func (d *driver) InstanceInspect(
 
 	iid := context.MustInstanceID(ctx)
 	return &types.Instance{
-		Name:         iid.ID,
-		Region:       iid.Fields[efs.InstanceIDFieldRegion],
-		InstanceID:   iid,
-		ProviderName: iid.Driver,
+		Name:             iid.ID,
+		Region:           iid.Fields[efs.InstanceIDFieldRegion],
+		AvailabilityZone: iid.Fields[efs.InstanceIDFieldAvailabilityZone],
+		InstanceID:       iid,
+		ProviderName:     iid.Driver,
 	}, nil
 }
 
@@ -294,6 +311,14 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.NAS, nil
 }
 
+// Capabilities returns the features supported by the driver.
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsMultiAttach: true,
+	}, nil
+}
+
 // NextDeviceInfo returns the information about the driver's next available
 // device workflow.
 func (d *driver) NextDeviceInfo(
@@ -339,6 +364,9 @@ func (d *driver) Volumes(
 			Size:        *fileSystem.SizeInBytes.Value,
 			Attachments: nil,
 		}
+		if fileSystem.CreationTime != nil {
+			volumeSD.CreateTime = fileSystem.CreationTime.Unix()
+		}
 
 		var atts []*types.VolumeAttachment
 		if opts.Attachments.Requested() {
@@ -370,7 +398,7 @@ func (d *driver) VolumeInspect(
 	}
 
 	if len(resp.FileSystems) == 0 {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
 	fileSystem := resp.FileSystems[0]
@@ -397,6 +425,9 @@ func (d *driver) VolumeInspect(
 		Size:        *fileSystem.SizeInBytes.Value,
 		Attachments: nil,
 	}
+	if fileSystem.CreationTime != nil {
+		volume.CreateTime = fileSystem.CreationTime.Unix()
+	}
 
 	var atts []*types.VolumeAttachment
 
@@ -689,7 +720,7 @@ func (d *driver) VolumeAttach(
 		// log a warning if any of the server-side defined SGs
 		// are not present in the list sent by the client instance
 		if len(missingSecGrpIDs) > 0 {
-			log.WithField("missingStorageGroups", missingSecGrpIDs).Warn(
+			ctx.WithField("missingStorageGroups", missingSecGrpIDs).Warn(
 				"configured sec grps not present on instance")
 		}
 
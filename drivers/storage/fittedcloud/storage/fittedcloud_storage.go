@@ -69,7 +69,7 @@ func (d *driver) Name() string {
 }
 
 // Init initializes the driver.
-func (d *driver) Init(context types.Context, config gofig.Config) error {
+func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	d.config = config
 	d.accessKey = d.getAccessKey()
 	if v := d.getRegion(); v != "" {
@@ -99,11 +99,11 @@ func (d *driver) Init(context types.Context, config gofig.Config) error {
 
 	_, err = fcagent.IsRunning()
 	if err != nil {
-		log.Error("error FittedCloud Agent is not running, ", err)
+		ctx.Error("error FittedCloud Agent is not running, ", err)
 		return goof.WithError("error FittedCloud Agent is not running", err)
 	}
 	d.fcTag = fcTag
-	log.Info("FittedCloud storage driver initialized")
+	ctx.Info("FittedCloud storage driver initialized")
 
 	return nil
 }
@@ -112,9 +112,19 @@ const cacheKeyC = "cacheKey"
 
 var (
 	sessions  = map[string]*awsec2.EC2{}
-	sessionsL = &sync.Mutex{}
+	sessionsL = &sync.RWMutex{}
 )
 
+// cachedSession returns the cached service for the key. The cache is read
+// under a shared lock so that concurrent requests do not serialize on the
+// common case.
+func cachedSession(ckey string) (*awsec2.EC2, bool) {
+	sessionsL.RLock()
+	defer sessionsL.RUnlock()
+	svc, ok := sessions[ckey]
+	return svc, ok
+}
+
 func writeHkey(h hash.Hash, ps *string) {
 	if ps == nil {
 		return
@@ -123,9 +133,6 @@ func writeHkey(h hash.Hash, ps *string) {
 }
 
 func (d *driver) Login(ctx types.Context) (interface{}, error) {
-	sessionsL.Lock()
-	defer sessionsL.Unlock()
-
 	var (
 		endpoint *string
 		ckey     string
@@ -147,8 +154,17 @@ func (d *driver) Login(ctx types.Context) (interface{}, error) {
 	ckey = fmt.Sprintf("%x", hkey.Sum(nil))
 
 	// if the session is cached then return it
+	if svc, ok := cachedSession(ckey); ok {
+		ctx.WithField(cacheKeyC, ckey).Debug("using cached ebs service")
+		return svc, nil
+	}
+
+	sessionsL.Lock()
+	defer sessionsL.Unlock()
+
+	// another request may have created the session while this one waited
+	// for the lock
 	if svc, ok := sessions[ckey]; ok {
-		log.WithField(cacheKeyC, ckey).Debug("using cached ebs service")
 		return svc, nil
 	}
 
@@ -173,7 +189,7 @@ func (d *driver) Login(ctx types.Context) (interface{}, error) {
 		fields[fittedcloud.Endpoint] = *endpoint
 	}
 
-	log.WithFields(fields).Debug("ebs service connetion attempt")
+	ctx.WithFields(fields).Debug("ebs service connetion attempt")
 	sess := session.New()
 
 	svc := awsec2.New(
@@ -201,7 +217,7 @@ func (d *driver) Login(ctx types.Context) (interface{}, error) {
 	)
 
 	sessions[ckey] = svc
-	log.WithFields(fields).Info("ebs service connetion created & cached")
+	ctx.WithFields(fields).Info("ebs service connetion created & cached")
 
 	return svc, nil
 }
@@ -247,6 +263,14 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Block, nil
 }
 
+// Capabilities returns the features supported by the driver.
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsEncryption: true,
+	}, nil
+}
+
 // InstanceInspect returns an instance.
 func (d *driver) InstanceInspect(
 	ctx types.Context,
@@ -254,10 +278,11 @@ func (d *driver) InstanceInspect(
 
 	iid := context.MustInstanceID(ctx)
 	return &types.Instance{
-		Name:         iid.ID,
-		Region:       iid.Fields[fittedcloud.InstanceIDFieldRegion],
-		InstanceID:   iid,
-		ProviderName: iid.Driver,
+		Name:             iid.ID,
+		Region:           iid.Fields[fittedcloud.InstanceIDFieldRegion],
+		AvailabilityZone: iid.Fields[fittedcloud.InstanceIDFieldAvailabilityZone],
+		InstanceID:       iid,
+		ProviderName:     iid.Driver,
 	}, nil
 }
 
@@ -292,7 +317,7 @@ func (d *driver) VolumeInspect(
 		return nil, goof.WithError("error getting volume", err)
 	}
 	if len(ec2vols) == 0 {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 	vols, convErr := d.toTypesVolume(ctx, ec2vols, opts.Attachments)
 	if convErr != nil {
@@ -340,7 +365,7 @@ func (d *driver) VolumeCreate(ctx types.Context, volumeName string,
 	}
 
 	if len(volumes) > 0 {
-		return nil, goof.WithFields(fields, "volume name already exists")
+		return nil, apiUtils.NewAlreadyExistsErr(volumeName)
 	}
 
 	// Pass libStorage types.Volume to helper function which calls EC2 API
@@ -413,7 +438,7 @@ func (d *driver) VolumeRemove(
 	for _, volume := range ec2vols {
 		// Skip volumes without FittedCloudCreated tag
 		if d.fittedcloudCreated(volume.Tags) == "" {
-			log.Warn(goof.WithFields(fields, "not a FittedCloudCreated volume"))
+			ctx.Warn(goof.WithFields(fields, "not a FittedCloudCreated volume"))
 			continue
 		}
 		// Delete volume via EC2 API call
@@ -429,10 +454,7 @@ func (d *driver) VolumeRemove(
 	return nil
 }
 
-var (
-	errMissingNextDevice  = goof.New("missing next device")
-	errVolAlreadyAttached = goof.New("volume already attached to a host")
-)
+var errMissingNextDevice = goof.New("missing next device")
 
 // VolumeAttach attaches a volume and provides a token clients can use
 // to validate that device has appeared locally.
@@ -466,7 +488,7 @@ func (d *driver) VolumeAttach(
 	if len(volumes[0].Attachments) > 0 {
 		// Detach already attached volume if forced
 		if !opts.Force {
-			return nil, "", errVolAlreadyAttached
+			return nil, "", apiUtils.NewVolumeAlreadyAttachedErr(volumeID)
 		}
 		_, err := d.VolumeDetach(
 			ctx,
@@ -547,8 +569,6 @@ func (d *driver) VolumeAttach(
 	return attachedVol, *opts.NextDevice, nil
 }
 
-var errVolAlreadyDetached = goof.New("volume already detached")
-
 // VolumeDetach detaches a volume.
 func (d *driver) VolumeDetach(
 	ctx types.Context,
@@ -575,6 +595,11 @@ func (d *driver) VolumeDetach(
 		return nil, errNoVolReturned
 	}
 
+	// volume has no attachments
+	if len(volumes[0].Attachments) == 0 {
+		return nil, apiUtils.NewVolumeNotAttachedErr(volumeID)
+	}
+
 	// tagName: e.g. "vol1"
 	tagName := volumes[0].Name
 	if tagName == "" {
@@ -586,7 +611,7 @@ func (d *driver) VolumeDetach(
 	// Delete FittedCloud volume
 	_, err = fcagent.DelVol(fcVolName)
 	if err != nil {
-		log.Error("error deleting FittedCloud volume, ", err)
+		ctx.Error("error deleting FittedCloud volume, ", err)
 		return nil, goof.WithError("error deleting FittedCloud volume", err)
 	}
 
@@ -606,7 +631,7 @@ func (d *driver) VolumeDetach(
 		// Detach volume using EC2 API call
 		if _, err = mustSession(ctx).DetachVolume(dvInput); err != nil {
 			allOK = false
-			log.WithFields(log.Fields{
+			ctx.WithFields(log.Fields{
 				"provider": d.Name(),
 				"volumeID": volume.VolumeId}).Error(
 				"error detaching volume", err)
@@ -614,7 +639,7 @@ func (d *driver) VolumeDetach(
 		// Wait for detaches to complete
 		if err = d.waitVolumeComplete(ctx, *volume.VolumeId, waitVolumeDetach); err != nil {
 			allOK = false
-			log.WithFields(log.Fields{
+			ctx.WithFields(log.Fields{
 				"provider": d.Name(),
 				"volumeID": volume.VolumeId}).Error(
 				"error waiting for volume detach", err)
@@ -762,7 +787,7 @@ func (d *driver) toTypesVolume(
 						fcUtils.NextDeviceInfo.Prefix, 1)
 					cmdOut, err := fcagent.GetFcVolName(deviceName)
 					if err != nil {
-						log.Error("error not a FittedCloud volume, ", err)
+						ctx.Error("error not a FittedCloud volume, ", err)
 					} else {
 						deviceName = cmdOut
 					}
@@ -792,6 +817,10 @@ func (d *driver) toTypesVolume(
 			Attachments:      attachmentsSD,
 		}
 
+		if volume.CreateTime != nil {
+			volumeSD.CreateTime = volume.CreateTime.Unix()
+		}
+
 		// Some volume types have no IOPS, so we get nil in volume.Iops
 		if volume.Iops != nil {
 			volumeSD.IOPS = *volume.Iops
@@ -3,7 +3,6 @@ package utils
 import (
 	"bufio"
 	"bytes"
-	"fmt"
 	"os"
 	"path"
 	"regexp"
@@ -12,65 +11,35 @@ import (
 	"github.com/akutz/goof"
 
 	"github.com/codedellemc/libstorage/api/types"
+	apiUtils "github.com/codedellemc/libstorage/api/utils"
 
 	"github.com/codedellemc/libstorage/drivers/storage/fittedcloud"
 )
 
-var errNoAvaiDevice = goof.New("no available device")
-
 // NextDevice returns the next available device.
 func NextDevice(
 	ctx types.Context,
 	opts types.Store) (string, error) {
-	// All possible device paths on Linux EC2 instances are /dev/xvd[f-p]
-	letters := []string{
-		"f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p"}
-
-	// Find which letters are used for local devices
-	localDeviceNames := make(map[string]bool)
-
 	localDevices, err := LocalDevices(
 		ctx, &types.LocalDevicesOpts{Opts: opts})
 	if err != nil {
 		return "", goof.WithError("error getting local devices", err)
 	}
-	localDeviceMapping := localDevices.DeviceMap
-
-	for localDevice := range localDeviceMapping {
-		re, _ := regexp.Compile(`^/dev/` +
-			NextDeviceInfo.Prefix +
-			`(` + NextDeviceInfo.Pattern + `)`)
-		res := re.FindStringSubmatch(localDevice)
-		if len(res) > 0 {
-			localDeviceNames[res[1]] = true
-		}
+
+	var inUse []string
+	for localDevice := range localDevices.DeviceMap {
+		inUse = append(inUse, localDevice)
 	}
 
-	// Find which letters are used for ephemeral devices
+	// Ephemeral devices are not always present in the local devices
 	ephemeralDevices, err := getEphemeralDevices(ctx)
 	if err != nil {
 		return "", goof.WithError("error getting ephemeral devices", err)
 	}
+	inUse = append(inUse, ephemeralDevices...)
 
-	for _, ephemeralDevice := range ephemeralDevices {
-		re, _ := regexp.Compile(`^` +
-			NextDeviceInfo.Prefix +
-			`(` + NextDeviceInfo.Pattern + `)`)
-		res := re.FindStringSubmatch(ephemeralDevice)
-		if len(res) > 0 {
-			localDeviceNames[res[1]] = true
-		}
-	}
-
-	// Find next available letter for device path
-	for _, letter := range letters {
-		if localDeviceNames[letter] {
-			continue
-		}
-		return fmt.Sprintf(
-			"/dev/%s%s", NextDeviceInfo.Prefix, letter), nil
-	}
-	return "", errNoAvaiDevice
+	// All possible device paths on Linux EC2 instances are /dev/xvd[f-p]
+	return apiUtils.GetNextAvailableDevice(NextDeviceInfo, inUse)
 }
 
 const procPartitions = "/proc/partitions"
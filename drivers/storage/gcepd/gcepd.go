@@ -65,6 +65,10 @@ const (
 	// incoming requests that have names with underscores should be
 	// converted to dashes to satisfy GCE naming requirements
 	ConfigConvertUnderscores = Name + ".convertUnderscores"
+
+	// ConfigEndpoint is the key for the URL of the compute API. The
+	// default URL is used when the endpoint is not set.
+	ConfigEndpoint = Name + ".endpoint"
 )
 
 func init() {
@@ -86,6 +90,8 @@ func init() {
 		ConfigStatusTimeout)
 	r.Key(gofig.Bool, "", defaultConvertUnderscores,
 		"Convert Underscores", ConfigConvertUnderscores)
+	r.Key(gofig.String, "", "", "The URL of the compute API",
+		ConfigEndpoint)
 
 	gofigCore.Register(r)
 }
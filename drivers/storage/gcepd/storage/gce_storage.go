@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -35,6 +36,7 @@ const (
 	cacheKeyC     = "cacheKey"
 	tagKey        = "libstoragetag"
 	minDiskSizeGB = 10
+	bytesPerGiB   = 1024 * 1024 * 1024
 )
 
 var (
@@ -57,6 +59,7 @@ type driver struct {
 	statusDelay     int64
 	statusTimeout   time.Duration
 	convUnderscore  bool
+	endpoint        string
 }
 
 func init() {
@@ -143,13 +146,18 @@ func (d *driver) Init(context types.Context, config gofig.Config) error {
 
 	d.convUnderscore = d.config.GetBool(gcepd.ConfigConvertUnderscores)
 
+	d.endpoint = d.config.GetString(gcepd.ConfigEndpoint)
+	if d.endpoint != "" {
+		context.Infof("Using compute API endpoint: %s", d.endpoint)
+	}
+
 	context.Info("storage driver initialized")
 	return nil
 }
 
 var (
 	sessions  = map[string]*compute.Service{}
-	sessionsL = &sync.Mutex{}
+	sessionsL = &sync.RWMutex{}
 )
 
 func writeHkey(h hash.Hash, ps *string) {
@@ -159,30 +167,53 @@ func writeHkey(h hash.Hash, ps *string) {
 	h.Write([]byte(*ps))
 }
 
-func (d *driver) Login(ctx types.Context) (interface{}, error) {
-	sessionsL.Lock()
-	defer sessionsL.Unlock()
-
-	var (
-		ckey   string
-		hkey   = md5.New()
-		client *http.Client
-	)
+// cachedSession returns the cached service for the driver. The cache is read
+// under a shared lock so that concurrent requests do not serialize on the
+// common case.
+func (d *driver) cachedSession() (string, *compute.Service, bool) {
+	sessionsL.RLock()
+	defer sessionsL.RUnlock()
+	ckey, _ := d.sessionKey()
+	svc, ok := sessions[ckey]
+	return ckey, svc, ok
+}
 
+// sessionKey returns the session cache key and the hash from which it was
+// computed. The caller must hold sessionsL.
+func (d *driver) sessionKey() (string, hash.Hash) {
 	// Unique connections to google APIs are based on project ID
 	// optionally there may be an additional service account
+	hkey := md5.New()
 	writeHkey(hkey, d.projectID)
 	if d.svcAccount != "" {
 		writeHkey(hkey, &d.svcAccount)
 	}
-	ckey = fmt.Sprintf("%x", hkey.Sum(nil))
+	if d.endpoint != "" {
+		writeHkey(hkey, &d.endpoint)
+	}
+	return fmt.Sprintf("%x", hkey.Sum(nil)), hkey
+}
+
+func (d *driver) Login(ctx types.Context) (interface{}, error) {
 
 	// if the session is cached then return it
-	if svc, ok := sessions[ckey]; ok {
+	if ckey, svc, ok := d.cachedSession(); ok {
 		ctx.WithField(cacheKeyC, ckey).Debug("using cached gce service")
 		return svc, nil
 	}
 
+	sessionsL.Lock()
+	defer sessionsL.Unlock()
+
+	var client *http.Client
+
+	// another request may have created the session while this one waited
+	// for the lock
+	ckey, hkey := d.sessionKey()
+	if svc, ok := sessions[ckey]; ok {
+		return svc, nil
+	}
+
 	fields := map[string]interface{}{
 		cacheKeyC:   ckey,
 		"keyfile":   d.keyFile,
@@ -227,6 +258,9 @@ func (d *driver) Login(ctx types.Context) (interface{}, error) {
 		return nil, err
 
 	}
+	if d.endpoint != "" {
+		svc.BasePath = d.endpoint
+	}
 
 	sessions[ckey] = svc
 	ctx.Info("GCE service connection created and cached")
@@ -245,6 +279,62 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Block, nil
 }
 
+// Capabilities returns the features supported by the driver.
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsSnapshots: true,
+	}, nil
+}
+
+// maxDiskSizeGB is the largest persistent disk GCE creates.
+const maxDiskSizeGB = 64 * 1024
+
+// VolumeTypes returns the types of persistent disks GCE offers.
+func (d *driver) VolumeTypes(
+	ctx types.Context,
+	opts types.Store) ([]*types.VolumeType, error) {
+	return []*types.VolumeType{
+		{
+			Name:        gcepd.DiskTypeSSD,
+			Description: "SSD persistent disk",
+			MinSize:     minDiskSizeGB,
+			MaxSize:     maxDiskSizeGB,
+		},
+		{
+			Name:        gcepd.DiskTypeStandard,
+			Description: "Standard persistent disk",
+			MinSize:     minDiskSizeGB,
+			MaxSize:     maxDiskSizeGB,
+		},
+	}, nil
+}
+
+// MaxVolumeCount returns the number of persistent disks that may be
+// attached to the instance based on its machine type.
+func (d *driver) MaxVolumeCount(
+	ctx types.Context,
+	opts types.Store) (int, error) {
+
+	zone, err := d.validZone(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if zone == nil || *zone == "" {
+		return 0, goof.New("Zone is required for MaxVolumeCount")
+	}
+
+	instanceName := context.MustInstanceID(ctx).ID
+	gceInst, err := d.getInstance(ctx, zone, &instanceName)
+	if err != nil {
+		return 0, err
+	}
+	if gceInst == nil {
+		return 0, goof.New("Instance not found")
+	}
+	return utils.MaxDiskCount(gceInst.MachineType), nil
+}
+
 // InstanceInspect returns an instance.
 func (d *driver) InstanceInspect(
 	ctx types.Context,
@@ -252,7 +342,8 @@ func (d *driver) InstanceInspect(
 
 	iid := context.MustInstanceID(ctx)
 	return &types.Instance{
-		InstanceID: iid,
+		InstanceID:       iid,
+		AvailabilityZone: iid.Fields[gcepd.InstanceIDFieldZone],
 	}, nil
 }
 
@@ -323,7 +414,7 @@ func (d *driver) VolumeInspect(
 			"Unable to get disk from GCE API", err)
 	}
 	if gceDisk == nil {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
 	gceDisks := []*compute.Disk{gceDisk}
@@ -358,11 +449,23 @@ func (d *driver) VolumeCreate(
 	volumeName string,
 	opts *types.VolumeCreateOpts) (*types.Volume, error) {
 
+	return d.volumeCreate(ctx, volumeName, nil, opts)
+}
+
+func (d *driver) volumeCreate(
+	ctx types.Context,
+	volumeName string,
+	snapshot *compute.Snapshot,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
 	fields := map[string]interface{}{
 		"driverName": d.Name(),
 		"volumeName": volumeName,
 		"opts":       opts,
 	}
+	if snapshot != nil {
+		fields["snapshotID"] = snapshot.Name
+	}
 
 	zone, err := d.validZone(ctx)
 	if err != nil {
@@ -397,6 +500,9 @@ func (d *driver) VolumeCreate(
 
 	if opts.Size == nil {
 		size := int64(minDiskSizeGB)
+		if snapshot != nil && snapshot.DiskSizeGb > size {
+			size = snapshot.DiskSizeGb
+		}
 		opts.Size = &size
 	}
 
@@ -416,11 +522,10 @@ func (d *driver) VolumeCreate(
 			"error querying for existing volume", err)
 	}
 	if gceDisk != nil {
-		return nil, goof.WithFields(fields,
-			"volume name already exists")
+		return nil, apiUtils.NewAlreadyExistsErr(volumeName)
 	}
 
-	err = d.createVolume(ctx, &volumeName, opts)
+	err = d.createVolume(ctx, &volumeName, snapshot, opts)
 	if err != nil {
 		return nil, goof.WithFieldsE(
 			fields, "error creating volume", err)
@@ -440,7 +545,16 @@ func (d *driver) VolumeCreateFromSnapshot(
 	snapshotID, volumeName string,
 	opts *types.VolumeCreateOpts) (*types.Volume, error) {
 
-	return nil, types.ErrNotImplemented
+	gceSnapshot, err := d.getSnapshot(ctx, &snapshotID)
+	if err != nil {
+		return nil, goof.WithError(
+			"Unable to get snapshot from GCE API", err)
+	}
+	if gceSnapshot == nil {
+		return nil, apiUtils.NewSnapshotNotFoundErr(snapshotID, nil)
+	}
+
+	return d.volumeCreate(ctx, volumeName, gceSnapshot, opts)
 }
 
 // VolumeCopy copies an existing volume.
@@ -458,7 +572,75 @@ func (d *driver) VolumeSnapshot(
 	volumeID, snapshotName string,
 	opts types.Store) (*types.Snapshot, error) {
 
-	return nil, types.ErrNotImplemented
+	fields := map[string]interface{}{
+		"driverName":   d.Name(),
+		"volumeID":     volumeID,
+		"snapshotName": snapshotName,
+	}
+
+	zone, err := d.validZone(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if zone == nil || *zone == "" {
+		return nil, goof.New("Zone is required for VolumeSnapshot")
+	}
+
+	snapshotName = d.convUnderscores(snapshotName)
+	fields["snapshotName"] = snapshotName
+	if !utils.IsValidDiskName(&snapshotName) {
+		return nil, goof.WithFields(fields,
+			"Snapshot name does not meet GCE naming requirements")
+	}
+
+	gceDisk, err := d.getDisk(ctx, zone, &volumeID)
+	if err != nil {
+		return nil, err
+	}
+	if gceDisk == nil {
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
+	}
+
+	// Check if snapshot with same name exists
+	gceSnapshot, err := d.getSnapshot(ctx, &snapshotName)
+	if err != nil {
+		return nil, goof.WithFieldsE(fields,
+			"error querying for existing snapshot", err)
+	}
+	if gceSnapshot != nil {
+		return nil, apiUtils.NewAlreadyExistsErr(snapshotName)
+	}
+
+	ctx.WithFields(fields).Debug("creating snapshot")
+
+	createSnapshot := &compute.Snapshot{
+		Name: snapshotName,
+	}
+	if d.tag != "" {
+		createSnapshot.Labels = getLabels(&d.tag)
+	}
+	if expireTime := opts.GetInt64(types.SnapshotExpireTimeKey); expireTime > 0 {
+		if createSnapshot.Labels == nil {
+			createSnapshot.Labels = map[string]string{}
+		}
+		createSnapshot.Labels[types.SnapshotExpireTimeTag] =
+			strconv.FormatInt(expireTime, 10)
+	}
+
+	asyncOp, err := mustSession(ctx).Disks.CreateSnapshot(
+		*d.projectID, *zone, volumeID, createSnapshot).Do()
+	if err != nil {
+		return nil, goof.WithFieldsE(fields,
+			"Failed to initiate snapshot creation", err)
+	}
+
+	err = d.waitUntilOperationIsFinished(ctx, zone, asyncOp)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.SnapshotInspect(ctx, snapshotName, opts)
 }
 
 // VolumeRemove removes a volume.
@@ -523,10 +705,12 @@ func (d *driver) VolumeAttach(
 		return nil, "", err
 	}
 	if gceDisk == nil {
-		return nil, "", apiUtils.NewNotFoundError(volumeID)
+		return nil, "", apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
-	if len(gceDisk.Users) > 0 {
+	// a disk may be attached read-only to several instances as long as none
+	// of them attach it read-write, which GCE enforces
+	if len(gceDisk.Users) > 0 && !opts.ReadOnly {
 		if !opts.Force {
 			return nil, "", goof.New(
 				"Volume already attached to different host")
@@ -540,7 +724,7 @@ func (d *driver) VolumeAttach(
 		}
 	}
 
-	err = d.attachVolume(ctx, &instanceName, zone, &volumeID)
+	err = d.attachVolume(ctx, &instanceName, zone, &volumeID, opts.ReadOnly)
 	if err != nil {
 		return nil, "", err
 	}
@@ -554,6 +738,13 @@ func (d *driver) VolumeAttach(
 	if err != nil {
 		return nil, "", goof.WithError("Error getting volume", err)
 	}
+	if opts.ReadOnly {
+		for _, att := range vol.Attachments {
+			if att.InstanceID.ID == instanceName {
+				att.ReadOnly = true
+			}
+		}
+	}
 
 	return vol, volumeID, nil
 }
@@ -579,7 +770,7 @@ func (d *driver) VolumeDetach(
 		return nil, err
 	}
 	if gceDisk == nil {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
 	if len(gceDisk.Users) == 0 {
@@ -609,7 +800,18 @@ func (d *driver) Snapshots(
 	ctx types.Context,
 	opts types.Store) ([]*types.Snapshot, error) {
 
-	return nil, types.ErrNotImplemented
+	gceSnapshots, err := d.getSnapshots(ctx)
+	if err != nil {
+		return nil, goof.WithError(
+			"Unable to get snapshots from GCE API", err)
+	}
+
+	snapshots := make([]*types.Snapshot, len(gceSnapshots))
+	for i, gceSnapshot := range gceSnapshots {
+		snapshots[i] = toTypeSnapshot(gceSnapshot)
+	}
+
+	return snapshots, nil
 }
 
 // SnapshotInspect inspects a single snapshot.
@@ -618,7 +820,16 @@ func (d *driver) SnapshotInspect(
 	snapshotID string,
 	opts types.Store) (*types.Snapshot, error) {
 
-	return nil, types.ErrNotImplemented
+	gceSnapshot, err := d.getSnapshot(ctx, &snapshotID)
+	if err != nil {
+		return nil, goof.WithError(
+			"Unable to get snapshot from GCE API", err)
+	}
+	if gceSnapshot == nil {
+		return nil, apiUtils.NewSnapshotNotFoundErr(snapshotID, nil)
+	}
+
+	return toTypeSnapshot(gceSnapshot), nil
 }
 
 // SnapshotCopy copies an existing snapshot.
@@ -627,7 +838,7 @@ func (d *driver) SnapshotCopy(
 	snapshotID, snapshotName, destinationID string,
 	opts types.Store) (*types.Snapshot, error) {
 
-	return nil, types.ErrNotImplemented
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshot copy")
 }
 
 // SnapshotRemove removes a snapshot.
@@ -636,7 +847,22 @@ func (d *driver) SnapshotRemove(
 	snapshotID string,
 	opts types.Store) error {
 
-	return types.ErrNotImplemented
+	gceSnapshot, err := d.getSnapshot(ctx, &snapshotID)
+	if err != nil {
+		return goof.WithError(
+			"Unable to get snapshot from GCE API", err)
+	}
+	if gceSnapshot == nil {
+		return apiUtils.NewSnapshotNotFoundErr(snapshotID, nil)
+	}
+
+	asyncOp, err := mustSession(ctx).Snapshots.Delete(
+		*d.projectID, snapshotID).Do()
+	if err != nil {
+		return goof.WithError("Failed to initiate snapshot deletion", err)
+	}
+
+	return d.waitUntilGlobalOperationIsFinished(ctx, asyncOp)
 }
 
 ///////////////////////////////////////////////////////////////////////
@@ -782,6 +1008,70 @@ func (d *driver) getInstance(
 	return inst, nil
 }
 
+func (d *driver) getSnapshots(
+	ctx types.Context) ([]*compute.Snapshot, error) {
+
+	snapListQ := mustSession(ctx).Snapshots.List(*d.projectID)
+	if d.tag != "" {
+		filter := fmt.Sprintf("labels.%s eq %s", tagKey, d.tag)
+		ctx.Debugf("query filter: %s", filter)
+		snapListQ.Filter(filter)
+	}
+
+	snapList, err := snapListQ.Do()
+	if err != nil {
+		ctx.Errorf("Error listing snapshots: %s", err)
+		return nil, err
+	}
+
+	return snapList.Items, nil
+}
+
+func (d *driver) getSnapshot(
+	ctx types.Context,
+	name *string) (*compute.Snapshot, error) {
+
+	snapshot, err := mustSession(ctx).Snapshots.Get(*d.projectID, *name).Do()
+	if err != nil {
+		if apiE, ok := err.(*googleapi.Error); ok {
+			if apiE.Code == 404 {
+				return nil, nil
+			}
+		}
+		ctx.Errorf("Error getting snapshot: %s", err)
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func toTypeSnapshot(snapshot *compute.Snapshot) *types.Snapshot {
+	lsSnapshot := &types.Snapshot{
+		Name:        snapshot.Name,
+		ID:          snapshot.Name,
+		Description: snapshot.Description,
+		VolumeID:    utils.GetIndex(snapshot.SourceDisk),
+		VolumeSize:  snapshot.DiskSizeGb,
+		Size:        (snapshot.StorageBytes + bytesPerGiB - 1) / bytesPerGiB,
+		Status:      snapshot.Status,
+	}
+	switch snapshot.Status {
+	case "CREATING", "UPLOADING":
+		lsSnapshot.Status = types.SnapshotStatusPending
+	case "READY":
+		lsSnapshot.Status = types.SnapshotStatusCompleted
+		lsSnapshot.Progress = 100
+	case "FAILED":
+		lsSnapshot.Status = types.SnapshotStatusError
+	}
+	if t, err := time.Parse(
+		time.RFC3339, snapshot.CreationTimestamp); err == nil {
+		lsSnapshot.StartTime = t.Unix()
+	}
+	apiUtils.ParseSnapshotExpireTime(lsSnapshot, snapshot.Labels)
+	return lsSnapshot
+}
+
 func (d *driver) toTypeVolume(
 	ctx types.Context,
 	disks []*compute.Disk,
@@ -814,6 +1104,10 @@ func (d *driver) toTypeVolume(
 			Type:             utils.GetIndex(disk.Type),
 			Size:             disk.SizeGb,
 		}
+		if t, err := time.Parse(
+			time.RFC3339, disk.CreationTimestamp); err == nil {
+			volume.CreateTime = t.Unix()
+		}
 
 		if attachments.Requested() {
 			attachment := getAttachment(disk, attachments, ld)
@@ -863,6 +1157,7 @@ func getAttachment(
 				ID:     utils.GetIndex(link),
 				Driver: gcepd.Name,
 			},
+			Status: types.AttachmentStatusAttached,
 		}
 		if attachments.Devices() {
 			if dev, ok := ld.DeviceMap[disk.Name]; ok {
@@ -879,6 +1174,7 @@ func getAttachment(
 func (d *driver) createVolume(
 	ctx types.Context,
 	volumeName *string,
+	snapshot *compute.Snapshot,
 	opts *types.VolumeCreateOpts) error {
 
 	diskType := d.defaultDiskType
@@ -897,6 +1193,9 @@ func (d *driver) createVolume(
 		SizeGb: *opts.Size,
 		Type:   diskTypeURI,
 	}
+	if snapshot != nil {
+		createDisk.SourceSnapshot = snapshot.SelfLink
+	}
 
 	asyncOp, err := mustSession(ctx).Disks.Insert(
 		*d.projectID, *opts.AvailabilityZone, createDisk).Do()
@@ -940,13 +1239,31 @@ func (d *driver) waitUntilOperationIsFinished(
 	zone *string,
 	operation *compute.Operation) error {
 
-	opName := operation.Name
+	return d.waitForOperation(ctx, func() (*compute.Operation, error) {
+		return mustSession(ctx).ZoneOperations.Get(
+			*d.projectID, *zone, operation.Name).Do()
+	})
+}
+
+func (d *driver) waitUntilGlobalOperationIsFinished(
+	ctx types.Context,
+	operation *compute.Operation) error {
+
+	return d.waitForOperation(ctx, func() (*compute.Operation, error) {
+		return mustSession(ctx).GlobalOperations.Get(
+			*d.projectID, operation.Name).Do()
+	})
+}
+
+func (d *driver) waitForOperation(
+	ctx types.Context,
+	getOp func() (*compute.Operation, error)) error {
+
 	f := func() (interface{}, error) {
 		duration := d.statusDelay
 		for i := 1; i <= d.maxAttempts; i++ {
 
-			op, err := mustSession(ctx).ZoneOperations.Get(
-				*d.projectID, *zone, opName).Do()
+			op, err := getOp()
 			if err != nil {
 				return nil, err
 			}
@@ -991,13 +1308,20 @@ func (d *driver) attachVolume(
 	ctx types.Context,
 	instanceID *string,
 	zone *string,
-	volumeName *string) error {
+	volumeName *string,
+	readOnly bool) error {
+
+	mode := "READ_WRITE"
+	if readOnly {
+		mode = "READ_ONLY"
+	}
 
 	disk := &compute.AttachedDisk{
 		AutoDelete: false,
 		Boot:       false,
 		Source:     fmt.Sprintf("zones/%s/disks/%s", *zone, *volumeName),
 		DeviceName: *volumeName,
+		Mode:       mode,
 	}
 
 	asyncOp, err := mustSession(ctx).Instances.AttachDisk(
@@ -177,3 +177,33 @@ func IsValidDiskName(name *string) bool {
 	}
 	return diskRegex.MatchString(*name)
 }
+
+const (
+	// sharedCoreMaxDisks is the number of persistent disks that may be
+	// attached to an instance with a shared-core machine type.
+	sharedCoreMaxDisks = 16
+
+	// maxDisks is the number of persistent disks that may be attached to an
+	// instance with any other machine type.
+	maxDisks = 128
+)
+
+// sharedCoreMachineTypes are the machine types whose instances share a
+// physical core.
+var sharedCoreMachineTypes = map[string]bool{
+	"e2-micro":  true,
+	"e2-small":  true,
+	"e2-medium": true,
+	"f1-micro":  true,
+	"g1-small":  true,
+}
+
+// MaxDiskCount returns the number of persistent disks that may be attached
+// to an instance of the provided machine type. The machine type may be
+// either its name or its URL.
+func MaxDiskCount(machineType string) int {
+	if sharedCoreMachineTypes[GetIndex(machineType)] {
+		return sharedCoreMaxDisks
+	}
+	return maxDisks
+}
@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+	"github.com/akutz/gotil"
+
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+
+	"github.com/codedellemc/libstorage/drivers/storage/iscsi"
+	"github.com/codedellemc/libstorage/drivers/storage/iscsi/utils"
+)
+
+// driver is the storage executor for the iscsi storage driver.
+type driver struct {
+	config gofig.Config
+}
+
+func init() {
+	registry.RegisterStorageExecutor(iscsi.Name, newDriver)
+}
+
+func newDriver() types.StorageExecutor {
+	return &driver{}
+}
+
+func (d *driver) Init(ctx types.Context, config gofig.Config) error {
+	d.config = config
+	return nil
+}
+
+func (d *driver) Name() string {
+	return iscsi.Name
+}
+
+// Supported returns a flag indicating whether or not the platform
+// implementing the executor is valid for the host on which the executor
+// resides.
+func (d *driver) Supported(
+	ctx types.Context,
+	opts types.Store) (bool, error) {
+
+	return gotil.FileExistsInPath("iscsiadm"), nil
+}
+
+// InstanceID returns the local instance ID.
+func (d *driver) InstanceID(
+	ctx types.Context,
+	opts types.Store) (*types.InstanceID, error) {
+	return utils.InstanceID(ctx)
+}
+
+var errNoAvaiDevice = goof.New("no available device")
+
+const procPartitions = "/proc/partitions"
+
+// NextDevice returns the next available SCSI disk. The kernel assigns the
+// disk when the initiator logs into a target, so the device is a
+// prediction based on the disks present in /proc/partitions.
+func (d *driver) NextDevice(
+	ctx types.Context,
+	opts types.Store) (string, error) {
+
+	f, err := os.Open(procPartitions)
+	if err != nil {
+		return "", goof.WithError("error reading "+procPartitions, err)
+	}
+	defer f.Close()
+
+	rx := regexp.MustCompile(
+		`^` + utils.NextDeviceInfo.Prefix +
+			`(` + utils.NextDeviceInfo.Pattern + `)$`)
+
+	used := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		if res := rx.FindStringSubmatch(fields[3]); len(res) > 0 {
+			used[res[1]] = true
+		}
+	}
+
+	for c := 'a'; c <= 'z'; c++ {
+		if used[string(c)] {
+			continue
+		}
+		return fmt.Sprintf(
+			"/dev/%s%c", utils.NextDeviceInfo.Prefix, c), nil
+	}
+	return "", errNoAvaiDevice
+}
+
+// LocalDevices returns a map of the IQNs of the targets into which the host
+// is logged to the paths of the targets' SCSI disks. A deep scan rescans
+// the sessions for new LUNs first.
+func (d *driver) LocalDevices(
+	ctx types.Context,
+	opts *types.LocalDevicesOpts) (*types.LocalDevices, error) {
+
+	if opts.ScanType == types.DeviceScanDeep {
+		if err := utils.Rescan(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	devMap, err := utils.Sessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ld := &types.LocalDevices{Driver: d.Name()}
+	if len(devMap) > 0 {
+		ld.DeviceMap = devMap
+	}
+
+	return ld, nil
+}
@@ -0,0 +1,60 @@
+package iscsi
+
+import (
+	gofigCore "github.com/akutz/gofig"
+	gofig "github.com/akutz/gofig/types"
+)
+
+const (
+	// Name is the provider's name.
+	Name = "iscsi"
+
+	// DefaultPort is the default iSCSI target portal port.
+	DefaultPort = "3260"
+
+	// Portal is a key constant.
+	Portal = "portal"
+
+	// IQN is a key constant.
+	IQN = "iqn"
+
+	// ChapUsername is a key constant.
+	ChapUsername = "chapUsername"
+
+	// ChapPassword is a key constant.
+	ChapPassword = "chapPassword"
+)
+
+const (
+	// ConfigISCSI is a config key.
+	ConfigISCSI = Name
+
+	// ConfigISCSIPortal is a config key.
+	ConfigISCSIPortal = ConfigISCSI + "." + Portal
+
+	// ConfigISCSIIQN is a config key.
+	ConfigISCSIIQN = ConfigISCSI + "." + IQN
+
+	// ConfigISCSIChapUsername is a config key.
+	ConfigISCSIChapUsername = ConfigISCSI + "." + ChapUsername
+
+	// ConfigISCSIChapPassword is a config key.
+	ConfigISCSIChapPassword = ConfigISCSI + "." + ChapPassword
+)
+
+func init() {
+	r := gofigCore.NewRegistration("iSCSI")
+	r.Key(gofig.String,
+		"",
+		"",
+		"The target portal, ex. 192.168.0.10:3260",
+		ConfigISCSIPortal)
+	r.Key(gofig.String,
+		"",
+		"",
+		"The IQN, or IQN prefix, of the targets to manage as volumes",
+		ConfigISCSIIQN)
+	r.Key(gofig.String, "", "", "The CHAP user name", ConfigISCSIChapUsername)
+	r.Key(gofig.String, "", "", "The CHAP password", ConfigISCSIChapPassword)
+	gofigCore.Register(r)
+}
@@ -0,0 +1,350 @@
+package storage
+
+import (
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+	apiUtils "github.com/codedellemc/libstorage/api/utils"
+
+	"github.com/codedellemc/libstorage/drivers/storage/iscsi"
+	iscsiUtils "github.com/codedellemc/libstorage/drivers/storage/iscsi/utils"
+)
+
+const bytesPerGiB = 1024 * 1024 * 1024
+
+// driver is the storage driver for iSCSI targets. Each target discovered at
+// the configured portal is a volume. LUNs are provisioned by the SAN, so the
+// driver manages discovery, login, and logout rather than the creation and
+// removal of volumes. Because the initiator logs into targets with iscsiadm,
+// the driver must run on the host to which the volumes are attached, such
+// as with an embedded libStorage server.
+type driver struct {
+	config gofig.Config
+	portal string
+	iqn    string
+	chap   *iscsiUtils.Chap
+}
+
+func init() {
+	registry.RegisterStorageDriver(iscsi.Name, newDriver)
+}
+
+func newDriver() types.StorageDriver {
+	return &driver{}
+}
+
+func (d *driver) Name() string {
+	return iscsi.Name
+}
+
+// Init initializes the driver.
+func (d *driver) Init(ctx types.Context, config gofig.Config) error {
+	d.config = config
+
+	if err := apiUtils.RequireConfigKeys(
+		config, iscsi.ConfigISCSIPortal); err != nil {
+		return err
+	}
+
+	d.portal = d.config.GetString(iscsi.ConfigISCSIPortal)
+	if d.portal != "" && !strings.Contains(d.portal, ":") {
+		d.portal = d.portal + ":" + iscsi.DefaultPort
+	}
+	d.iqn = d.config.GetString(iscsi.ConfigISCSIIQN)
+	d.chap = &iscsiUtils.Chap{
+		Username: d.config.GetString(iscsi.ConfigISCSIChapUsername),
+		Password: d.config.GetString(iscsi.ConfigISCSIChapPassword),
+	}
+
+	fields := log.Fields{
+		iscsi.Portal:       d.portal,
+		iscsi.IQN:          d.iqn,
+		iscsi.ChapUsername: d.chap.Username,
+	}
+	if d.chap.Password != "" {
+		fields[iscsi.ChapPassword] = "******"
+	}
+
+	ctx.WithFields(fields).Info("storage driver initialized")
+	return nil
+}
+
+// NextDeviceInfo returns the information about the driver's next available
+// device workflow.
+func (d *driver) NextDeviceInfo(
+	ctx types.Context) (*types.NextDeviceInfo, error) {
+	return iscsiUtils.NextDeviceInfo, nil
+}
+
+// Type returns the type of storage the driver provides.
+func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
+	return types.Block, nil
+}
+
+// Capabilities returns the features supported by the driver.
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{}, nil
+}
+
+// Ping verifies the target portal responds to discovery.
+func (d *driver) Ping(ctx types.Context) error {
+	_, err := iscsiUtils.Discover(ctx, d.portal)
+	return err
+}
+
+// InstanceInspect returns an instance.
+func (d *driver) InstanceInspect(
+	ctx types.Context,
+	opts types.Store) (*types.Instance, error) {
+
+	iid := context.MustInstanceID(ctx)
+	return &types.Instance{
+		Name:         iid.ID,
+		InstanceID:   iid,
+		ProviderName: iid.Driver,
+	}, nil
+}
+
+// Volumes returns all volumes or a filtered list of volumes.
+func (d *driver) Volumes(
+	ctx types.Context,
+	opts *types.VolumesOpts) ([]*types.Volume, error) {
+
+	iqns, err := d.targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var vols []*types.Volume
+	for _, iqn := range iqns {
+		vols = append(vols, d.toTypeVolume(ctx, iqn, opts.Attachments))
+	}
+	return vols, nil
+}
+
+// VolumeInspect inspects a single volume.
+func (d *driver) VolumeInspect(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeInspectOpts) (*types.Volume, error) {
+
+	return d.getVolume(ctx, volumeID, opts.Attachments)
+}
+
+// VolumeCreate creates a new volume.
+func (d *driver) VolumeCreate(ctx types.Context, volumeName string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "volume creation")
+}
+
+// VolumeCreateFromSnapshot creates a new volume from an existing snapshot.
+func (d *driver) VolumeCreateFromSnapshot(
+	ctx types.Context,
+	snapshotID, volumeName string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// VolumeCopy copies an existing volume.
+func (d *driver) VolumeCopy(
+	ctx types.Context,
+	volumeID, volumeName string,
+	opts types.Store) (*types.Volume, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "volume copy")
+}
+
+// VolumeSnapshot snapshots a volume.
+func (d *driver) VolumeSnapshot(
+	ctx types.Context,
+	volumeID, snapshotName string,
+	opts types.Store) (*types.Snapshot, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// VolumeRemove removes a volume.
+func (d *driver) VolumeRemove(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeRemoveOpts) error {
+	return apiUtils.NewUnsupportedErr(d.Name(), "volume removal")
+}
+
+// VolumeAttach logs into the volume's target and returns the target's IQN
+// as the token the client uses to wait for the target's SCSI disk to
+// appear locally.
+func (d *driver) VolumeAttach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeAttachOpts) (*types.Volume, string, error) {
+
+	vol, err := d.getVolume(ctx, volumeID, types.VolAttReqTrue)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(vol.Attachments) > 0 && !opts.Force {
+		return nil, "", apiUtils.NewVolumeAlreadyAttachedErr(volumeID)
+	}
+
+	if err := iscsiUtils.Login(ctx, d.portal, volumeID, d.chap); err != nil {
+		return nil, "", err
+	}
+
+	return vol, volumeID, nil
+}
+
+// VolumeDetach logs out of the volume's target.
+func (d *driver) VolumeDetach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeDetachOpts) (*types.Volume, error) {
+
+	vol, err := d.getVolume(ctx, volumeID, types.VolAttReqTrue)
+	if err != nil {
+		return nil, err
+	}
+	if len(vol.Attachments) == 0 && !opts.Force {
+		return nil, apiUtils.NewVolumeNotAttachedErr(volumeID)
+	}
+
+	if err := iscsiUtils.Logout(ctx, d.portal, volumeID); err != nil {
+		return nil, err
+	}
+
+	return d.getVolume(ctx, volumeID, types.VolAttNone)
+}
+
+// Snapshots returns all volumes or a filtered list of snapshots.
+func (d *driver) Snapshots(
+	ctx types.Context,
+	opts types.Store) ([]*types.Snapshot, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// SnapshotInspect inspects a single snapshot.
+func (d *driver) SnapshotInspect(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) (*types.Snapshot, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// SnapshotCopy copies an existing snapshot.
+func (d *driver) SnapshotCopy(
+	ctx types.Context,
+	snapshotID, snapshotName, destinationID string,
+	opts types.Store) (*types.Snapshot, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// SnapshotRemove removes a snapshot.
+func (d *driver) SnapshotRemove(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) error {
+	return apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// targets returns the IQNs of the targets discovered at the portal that
+// match the configured IQN prefix.
+func (d *driver) targets(ctx types.Context) ([]string, error) {
+	iqns, err := iscsiUtils.Discover(ctx, d.portal)
+	if err != nil {
+		return nil, goof.WithFieldE(
+			iscsi.Portal, d.portal, "error discovering iscsi targets", err)
+	}
+	if d.iqn == "" {
+		return iqns, nil
+	}
+	var matched []string
+	for _, iqn := range iqns {
+		if strings.HasPrefix(iqn, d.iqn) {
+			matched = append(matched, iqn)
+		}
+	}
+	return matched, nil
+}
+
+func (d *driver) toTypeVolume(
+	ctx types.Context,
+	iqn string,
+	attachments types.VolumeAttachmentsTypes) *types.Volume {
+
+	vol := &types.Volume{
+		Name:   iqn[strings.LastIndex(iqn, ":")+1:],
+		ID:     iqn,
+		Type:   iscsi.Name,
+		Status: types.VolumeStatusAvailable,
+	}
+
+	if !attachments.Requested() {
+		return vol
+	}
+	vol.AttachmentState = types.VolumeAvailable
+
+	ld, ok := context.LocalDevices(ctx)
+	if !ok {
+		return vol
+	}
+	devPath, ok := ld.DeviceMap[iqn]
+	if !ok {
+		return vol
+	}
+
+	vol.Size = diskSizeGiB(devPath)
+	vol.Status = types.VolumeStatusInUse
+	vol.AttachmentState = types.VolumeAttached
+
+	iid, _ := context.InstanceID(ctx)
+	vol.Attachments = []*types.VolumeAttachment{
+		{
+			VolumeID:   iqn,
+			DeviceName: devPath,
+			InstanceID: iid,
+			Status:     types.AttachmentStatusAttached,
+		},
+	}
+
+	return vol
+}
+
+func (d *driver) getVolume(
+	ctx types.Context,
+	volumeID string,
+	attachments types.VolumeAttachmentsTypes) (*types.Volume, error) {
+
+	iqns, err := d.targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, iqn := range iqns {
+		if iqn == volumeID {
+			return d.toTypeVolume(ctx, iqn, attachments), nil
+		}
+	}
+	return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
+}
+
+// diskSizeGiB returns the size of the SCSI disk in GiB. The disk's size in
+// sysfs is its number of 512 byte sectors.
+func diskSizeGiB(devPath string) int64 {
+	buf, err := ioutil.ReadFile(
+		path.Join("/sys/block", path.Base(devPath), "size"))
+	if err != nil {
+		return 0
+	}
+	sectors, err := strconv.ParseInt(strings.TrimSpace(string(buf)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return sectors * 512 / bytesPerGiB
+}
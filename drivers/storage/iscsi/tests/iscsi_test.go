@@ -0,0 +1,15 @@
+package iscsi
+
+import (
+	"testing"
+
+	apitests "github.com/codedellemc/libstorage/api/tests"
+
+	// load the driver packages
+	"github.com/codedellemc/libstorage/drivers/storage/iscsi"
+	_ "github.com/codedellemc/libstorage/drivers/storage/iscsi/storage"
+)
+
+func TestSuite(t *testing.T) {
+	apitests.RunSuite(t, iscsi.Name)
+}
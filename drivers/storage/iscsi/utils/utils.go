@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/drivers/storage/iscsi"
+)
+
+const initiatorNameFile = "/etc/iscsi/initiatorname.iscsi"
+
+// NextDeviceInfo is the NextDeviceInfo object for iSCSI. The kernel assigns
+// a LUN's SCSI disk, ex. "/dev/sdb", when the initiator logs into the
+// target, so the next device workflow is ignored.
+var NextDeviceInfo = &types.NextDeviceInfo{
+	Prefix:  "sd",
+	Pattern: "[a-z]+",
+	Ignore:  true,
+}
+
+// InstanceID returns the instance ID for the local host. The ID is the
+// host's initiator IQN, or its host name if the initiator name file is
+// missing.
+func InstanceID(ctx types.Context) (*types.InstanceID, error) {
+	if iqn, err := initiatorName(); err == nil && iqn != "" {
+		return &types.InstanceID{ID: iqn, Driver: iscsi.Name}, nil
+	}
+	hostName, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return &types.InstanceID{ID: hostName, Driver: iscsi.Name}, nil
+}
+
+func initiatorName() (string, error) {
+	buf, err := ioutil.ReadFile(initiatorNameFile)
+	if err != nil {
+		return "", err
+	}
+	s := bufio.NewScanner(strings.NewReader(string(buf)))
+	for s.Scan() {
+		l := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(l, "InitiatorName=") {
+			return strings.TrimPrefix(l, "InitiatorName="), nil
+		}
+	}
+	return "", s.Err()
+}
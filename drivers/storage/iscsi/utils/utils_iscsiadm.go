@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os/exec"
+	"path"
+	"strings"
+	"syscall"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const iscsiadmCmd = "iscsiadm"
+
+// iscsiadm exits with this status when a query matches no records, such as
+// listing sessions when none are logged in.
+const iscsiadmErrNoObjsFound = 21
+
+var errNoObjsFound = goof.New("no iscsi records found")
+
+// Chap is the CHAP credentials used to log into a target.
+type Chap struct {
+	Username string
+	Password string
+}
+
+func iscsiadm(ctx types.Context, args ...string) ([]byte, error) {
+	ctx.WithField("args", args).Debug("executing iscsiadm")
+	out, err := exec.Command(iscsiadmCmd, args...).CombinedOutput()
+	if err != nil {
+		if err, ok := err.(*exec.ExitError); ok {
+			if ws, ok := err.Sys().(syscall.WaitStatus); ok &&
+				ws.ExitStatus() == iscsiadmErrNoObjsFound {
+				return out, errNoObjsFound
+			}
+		}
+		return out, goof.WithFieldsE(goof.Fields{
+			"args":   args,
+			"output": string(out),
+		}, "error executing iscsiadm", err)
+	}
+	return out, nil
+}
+
+// Discover performs a sendtargets discovery against the portal and returns
+// the IQNs of the targets it exposes.
+func Discover(ctx types.Context, portal string) ([]string, error) {
+	out, err := iscsiadm(
+		ctx, "-m", "discovery", "-t", "sendtargets", "-p", portal)
+	if err != nil {
+		return nil, err
+	}
+	return parseDiscovery(bytes.NewReader(out))
+}
+
+// parseDiscovery parses the output of a sendtargets discovery, ex.
+// "192.168.0.10:3260,1 iqn.2016-01.com.example:vol1".
+func parseDiscovery(r io.Reader) ([]string, error) {
+	var (
+		iqns = []string{}
+		seen = map[string]bool{}
+		s    = bufio.NewScanner(r)
+	)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 2 || seen[fields[1]] {
+			continue
+		}
+		seen[fields[1]] = true
+		iqns = append(iqns, fields[1])
+	}
+	return iqns, s.Err()
+}
+
+// Login logs into the target at the portal, configuring the node's CHAP
+// credentials first if they are provided.
+func Login(ctx types.Context, portal, iqn string, chap *Chap) error {
+	node := []string{"-m", "node", "-T", iqn, "-p", portal}
+	if chap != nil && chap.Username != "" {
+		for _, kv := range [][]string{
+			{"node.session.auth.authmethod", "CHAP"},
+			{"node.session.auth.username", chap.Username},
+			{"node.session.auth.password", chap.Password},
+		} {
+			args := append(node, "--op", "update", "-n", kv[0], "-v", kv[1])
+			if _, err := iscsiadm(ctx, args...); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := iscsiadm(ctx, append(node, "--login")...)
+	return err
+}
+
+// Logout logs out of the target at the portal.
+func Logout(ctx types.Context, portal, iqn string) error {
+	_, err := iscsiadm(
+		ctx, "-m", "node", "-T", iqn, "-p", portal, "--logout")
+	return err
+}
+
+// Rescan rescans the logged-in sessions for new LUNs.
+func Rescan(ctx types.Context) error {
+	_, err := iscsiadm(ctx, "-m", "session", "--rescan")
+	if err == errNoObjsFound {
+		return nil
+	}
+	return err
+}
+
+// Sessions returns a map of the IQNs of the targets into which the host is
+// logged to the paths of the targets' SCSI disks, ex. "/dev/sdb".
+func Sessions(ctx types.Context) (map[string]string, error) {
+	out, err := iscsiadm(ctx, "-m", "session", "-P", "3")
+	if err != nil {
+		if err == errNoObjsFound {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	return parseSessions(bytes.NewReader(out))
+}
+
+// parseSessions parses the output of "iscsiadm -m session -P 3". The first
+// SCSI disk attached to each target is returned.
+func parseSessions(r io.Reader) (map[string]string, error) {
+	var (
+		iqn string
+		m   = map[string]string{}
+		s   = bufio.NewScanner(r)
+	)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		switch {
+		case len(fields) >= 2 && fields[0] == "Target:":
+			iqn = fields[1]
+		case len(fields) >= 4 &&
+			fields[0] == "Attached" && fields[1] == "scsi" &&
+			fields[2] == "disk":
+			if _, ok := m[iqn]; iqn != "" && !ok {
+				m[iqn] = path.Join("/dev", fields[3])
+			}
+		}
+	}
+	return m, s.Err()
+}
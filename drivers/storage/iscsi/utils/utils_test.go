@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDiscovery(t *testing.T) {
+	iqns, err := parseDiscovery(strings.NewReader(`
+192.168.0.10:3260,1 iqn.2016-01.com.example:vol1
+192.168.0.10:3260,1 iqn.2016-01.com.example:vol2
+192.168.0.11:3260,1 iqn.2016-01.com.example:vol2
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"iqn.2016-01.com.example:vol1",
+		"iqn.2016-01.com.example:vol2",
+	}, iqns)
+}
+
+func TestParseSessions(t *testing.T) {
+	m, err := parseSessions(strings.NewReader(`iSCSI Transport Class version 2.0-870
+version 2.0-874
+Target: iqn.2016-01.com.example:vol1 (non-flash)
+	Current Portal: 192.168.0.10:3260,1
+	Persistent Portal: 192.168.0.10:3260,1
+		**********
+		Interface:
+		**********
+		Iface Name: default
+		************************
+		Attached SCSI devices:
+		************************
+		Host Number: 3	State: running
+		scsi3 Channel 00 Id 0 Lun: 0
+			Attached scsi disk sdb		State: running
+		scsi3 Channel 00 Id 0 Lun: 1
+			Attached scsi disk sdc		State: running
+Target: iqn.2016-01.com.example:vol2 (non-flash)
+	Current Portal: 192.168.0.10:3260,1
+		scsi4 Channel 00 Id 0 Lun: 0
+			Attached scsi disk sdd		State: running
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"iqn.2016-01.com.example:vol1": "/dev/sdb",
+		"iqn.2016-01.com.example:vol2": "/dev/sdd",
+	}, m)
+}
@@ -47,6 +47,11 @@ func (d *driver) Name() string {
 func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	d.config = config
 
+	if err := apiUtils.RequireConfigKeys(
+		config, "isilon.endpoint"); err != nil {
+		return err
+	}
+
 	fields := log.Fields{
 		"endpoint":   d.endpoint(),
 		"userName":   d.userName(),
@@ -75,7 +80,7 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 			"error creating isilon client", err)
 	}
 
-	log.WithFields(fields).Info("storage driver initialized")
+	ctx.WithFields(fields).Info("storage driver initialized")
 	return nil
 }
 
@@ -161,6 +166,14 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.NAS, nil
 }
 
+// Capabilities returns the features supported by the driver.
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsMultiAttach: true,
+	}, nil
+}
+
 // NextDeviceInfo returns the information about the driver's next available
 // device workflow.
 func (d *driver) NextDeviceInfo(
@@ -244,7 +257,7 @@ func (d *driver) VolumeInspect(
 	}
 
 	if vols == nil {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
 	return vols[0], nil
@@ -257,13 +270,13 @@ func (d *driver) VolumeCreate(ctx types.Context, volumeName string,
 	vol, err := d.VolumeInspect(ctx, volumeName,
 		&types.VolumeInspectOpts{Attachments: types.VolAttReqTrue})
 	if err != nil {
-		if _, ok := err.(*types.ErrNotFound); !ok {
+		if !apiUtils.IsNotFoundErr(err) {
 			return nil, err
 		}
 	}
 
 	if vol != nil {
-		return nil, goof.New("volume name already exists")
+		return nil, apiUtils.NewAlreadyExistsErr(volumeName)
 	}
 
 	_, err = d.client.CreateVolume(ctx, volumeName)
@@ -370,7 +383,7 @@ func (d *driver) VolumeAttach(
 			}
 		}
 
-		return nil, "", goof.New("volume already attached to another host")
+		return nil, "", apiUtils.NewVolumeAlreadyAttachedErr(volumeID)
 	}
 
 	if d.sharedMounts() {
@@ -379,25 +392,25 @@ func (d *driver) VolumeAttach(
 		clients = []string{instanceID.InstanceID.ID}
 	}
 
-	log.WithField("clients", clients).Info("setting exports")
+	ctx.WithField("clients", clients).Info("setting exports")
 	err = d.client.SetExportClientsByID(ctx, exportID, clients...)
 	if err != nil {
 		return nil, "", err
 	}
 
-	log.Info("disabling root squash for export")
+	ctx.Info("disabling root squash for export")
 	err = d.client.DisableRootMappingByID(ctx, exportID)
 	if err != nil {
 		return nil, "", err
 	}
 
-	log.Info("disabling failure mapping for export")
+	ctx.Info("disabling failure mapping for export")
 	err = d.client.DisableFailureMappingByID(ctx, exportID)
 	if err != nil {
 		return nil, "", err
 	}
 
-	log.Info("mapping non-root user to root")
+	ctx.Info("mapping non-root user to root")
 	err = d.client.EnableNonRootMappingByID(ctx, exportID, "root")
 	if err != nil {
 		return nil, "", err
@@ -450,7 +463,7 @@ func (d *driver) VolumeDetach(
 	}
 
 	if len(newClients) > 0 {
-		log.WithField("clients", clients).Info("setting exports")
+		ctx.WithField("clients", clients).Info("setting exports")
 		err = d.client.SetExportClientsByID(ctx, export.ID, newClients...)
 		if err != nil {
 			return nil, err
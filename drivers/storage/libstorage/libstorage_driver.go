@@ -40,6 +40,7 @@ func newDriver() types.StorageDriver {
 
 func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	logFields := log.Fields{}
+	utils.RegisterConfigSecrets(config)
 
 	addr := config.GetString(types.ConfigHost)
 	d.ctx = ctx.WithValue(context.HostKey, addr)
@@ -56,10 +57,12 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 				return err
 			}
 			tok = string(buf)
+			types.RegisterSecret(tok)
 		}
 		d.ctx = d.ctx.WithValue(context.EncodedAuthTokenKey, tok)
-		d.ctx.WithField("encodedToken", tok).Debug("got configured auth token")
-		logFields["encodedToken"] = tok
+		d.ctx.WithField("encodedToken", utils.Redacted).Debug(
+			"got configured auth token")
+		logFields["encodedToken"] = utils.Redacted
 	}
 
 	proto, lAddr, err := gotil.ParseAddress(addr)
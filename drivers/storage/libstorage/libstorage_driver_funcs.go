@@ -1,7 +1,9 @@
 package libstorage
 
 import (
+	log "github.com/Sirupsen/logrus"
 	"github.com/akutz/goof"
+
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
@@ -50,9 +52,12 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 
 func (d *driver) InstanceInspect(
 	ctx types.Context,
-	opts types.Store) (*types.Instance, error) {
+	opts types.Store) (_ *types.Instance, err error) {
+
+	ctx, end := context.StartSpan(ctx, "InstanceInspect", nil)
+	defer func() { end(err) }()
 
-	if d.isController() {
+	if _, remote := context.InstanceID(ctx); d.isController() && !remote {
 		return nil, utils.NewUnsupportedForClientTypeError(
 			d.clientType, "InstanceInspect")
 	}
@@ -67,7 +72,10 @@ func (d *driver) InstanceInspect(
 
 func (d *driver) Volumes(
 	ctx types.Context,
-	opts *types.VolumesOpts) ([]*types.Volume, error) {
+	opts *types.VolumesOpts) (_ []*types.Volume, err error) {
+
+	ctx, end := context.StartSpan(ctx, "Volumes", nil)
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -91,7 +99,12 @@ func (d *driver) Volumes(
 func (d *driver) VolumeInspect(
 	ctx types.Context,
 	volumeID string,
-	opts *types.VolumeInspectOpts) (*types.Volume, error) {
+	opts *types.VolumeInspectOpts) (_ *types.Volume, err error) {
+
+	ctx, end := context.StartSpan(ctx, "VolumeInspect", log.Fields{
+		"volumeID": volumeID,
+	})
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -105,7 +118,12 @@ func (d *driver) VolumeInspect(
 func (d *driver) VolumeInspectByName(
 	ctx types.Context,
 	volumeName string,
-	opts *types.VolumeInspectOpts) (*types.Volume, error) {
+	opts *types.VolumeInspectOpts) (_ *types.Volume, err error) {
+
+	ctx, end := context.StartSpan(ctx, "VolumeInspectByName", log.Fields{
+		"volumeName": volumeName,
+	})
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -120,7 +138,12 @@ func (d *driver) VolumeInspectByName(
 func (d *driver) VolumeCreate(
 	ctx types.Context,
 	name string,
-	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+	opts *types.VolumeCreateOpts) (_ *types.Volume, err error) {
+
+	ctx, end := context.StartSpan(ctx, "VolumeCreate", log.Fields{
+		"volumeName": name,
+	})
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -128,24 +151,45 @@ func (d *driver) VolumeCreate(
 		return nil, goof.New("missing service name")
 	}
 
+	if context.DryRun(ctx) {
+		return dryRunVolumeCreate(name, opts)
+	}
+
 	req := &types.VolumeCreateRequest{
 		Name:             name,
 		AvailabilityZone: opts.AvailabilityZone,
 		Encrypted:        opts.Encrypted,
 		EncryptionKey:    opts.EncryptionKey,
 		IOPS:             opts.IOPS,
+		Throughput:       opts.Throughput,
 		Size:             opts.Size,
 		Type:             opts.Type,
+		Tags:             opts.Tags,
+		IdempotentByName: opts.IdempotentByName,
 		Opts:             opts.Opts.Map(),
 	}
 
-	return d.client.VolumeCreate(ctx, serviceName, req)
+	vol, err := d.client.VolumeCreate(ctx, serviceName, req)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyVolumeObserver(ctx, func(o types.VolumeObserver) {
+		o.OnCreate(ctx, vol)
+	})
+	return vol, nil
 }
 
 func (d *driver) VolumeCreateFromSnapshot(
 	ctx types.Context,
 	snapshotID, volumeName string,
-	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+	opts *types.VolumeCreateOpts) (_ *types.Volume, err error) {
+
+	ctx, end := context.StartSpan(ctx, "VolumeCreateFromSnapshot", log.Fields{
+		"snapshotID": snapshotID,
+		"volumeName": volumeName,
+	})
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -153,6 +197,18 @@ func (d *driver) VolumeCreateFromSnapshot(
 		return nil, goof.New("missing service name")
 	}
 
+	if context.DryRun(ctx) {
+		snap, err := d.client.SnapshotInspect(ctx, serviceName, snapshotID)
+		if err != nil {
+			return nil, err
+		}
+		dryOpts := *opts
+		if dryOpts.Size == nil {
+			dryOpts.Size = &snap.VolumeSize
+		}
+		return dryRunVolumeCreate(volumeName, &dryOpts)
+	}
+
 	req := &types.VolumeCreateRequest{
 		Name:             volumeName,
 		AvailabilityZone: opts.AvailabilityZone,
@@ -162,13 +218,27 @@ func (d *driver) VolumeCreateFromSnapshot(
 		Opts:             opts.Opts.Map(),
 	}
 
-	return d.client.VolumeCreateFromSnapshot(ctx, serviceName, snapshotID, req)
+	vol, err := d.client.VolumeCreateFromSnapshot(ctx, serviceName, snapshotID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyVolumeObserver(ctx, func(o types.VolumeObserver) {
+		o.OnCreate(ctx, vol)
+	})
+	return vol, nil
 }
 
 func (d *driver) VolumeCopy(
 	ctx types.Context,
 	volumeID, volumeName string,
-	opts types.Store) (*types.Volume, error) {
+	opts types.Store) (_ *types.Volume, err error) {
+
+	ctx, end := context.StartSpan(ctx, "VolumeCopy", log.Fields{
+		"volumeID":   volumeID,
+		"volumeName": volumeName,
+	})
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -176,18 +246,36 @@ func (d *driver) VolumeCopy(
 		return nil, goof.New("missing service name")
 	}
 
+	if context.DryRun(ctx) {
+		return d.dryRunVolumeCopy(ctx, serviceName, volumeID, volumeName)
+	}
+
 	req := &types.VolumeCopyRequest{
 		VolumeName: volumeName,
 		Opts:       opts.Map(),
 	}
 
-	return d.client.VolumeCopy(ctx, serviceName, volumeID, req)
+	vol, err := d.client.VolumeCopy(ctx, serviceName, volumeID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyVolumeObserver(ctx, func(o types.VolumeObserver) {
+		o.OnCreate(ctx, vol)
+	})
+	return vol, nil
 }
 
 func (d *driver) VolumeSnapshot(
 	ctx types.Context,
 	volumeID, snapshotName string,
-	opts types.Store) (*types.Snapshot, error) {
+	opts types.Store) (_ *types.Snapshot, err error) {
+
+	ctx, end := context.StartSpan(ctx, "VolumeSnapshot", log.Fields{
+		"volumeID":     volumeID,
+		"snapshotName": snapshotName,
+	})
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -195,8 +283,13 @@ func (d *driver) VolumeSnapshot(
 		return nil, goof.New("missing service name")
 	}
 
+	if context.DryRun(ctx) {
+		return d.dryRunVolumeSnapshot(ctx, serviceName, volumeID, snapshotName)
+	}
+
 	req := &types.VolumeSnapshotRequest{
 		SnapshotName: snapshotName,
+		ExpireTime:   opts.GetInt64(types.SnapshotExpireTimeKey),
 		Opts:         opts.Map(),
 	}
 
@@ -206,7 +299,12 @@ func (d *driver) VolumeSnapshot(
 func (d *driver) VolumeRemove(
 	ctx types.Context,
 	volumeID string,
-	opts *types.VolumeRemoveOpts) error {
+	opts *types.VolumeRemoveOpts) (err error) {
+
+	ctx, end := context.StartSpan(ctx, "VolumeRemove", log.Fields{
+		"volumeID": volumeID,
+	})
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -214,15 +312,35 @@ func (d *driver) VolumeRemove(
 		return goof.New("missing service name")
 	}
 
-	return d.client.VolumeRemove(ctx, serviceName, volumeID, opts.Force)
+	if context.DryRun(ctx) {
+		return d.dryRunVolumeRemove(ctx, serviceName, volumeID, opts)
+	}
+
+	if err := d.client.VolumeRemove(
+		ctx, serviceName, volumeID, opts.Force); err != nil {
+		return err
+	}
+
+	notifyVolumeObserver(ctx, func(o types.VolumeObserver) {
+		o.OnRemove(ctx, volumeID)
+	})
+	return nil
 }
 
 func (d *driver) VolumeAttach(
 	ctx types.Context,
 	volumeID string,
-	opts *types.VolumeAttachOpts) (*types.Volume, string, error) {
+	opts *types.VolumeAttachOpts) (_ *types.Volume, _ string, err error) {
+
+	ctx, end := context.StartSpan(ctx, "VolumeAttach", log.Fields{
+		"volumeID": volumeID,
+	})
+	defer func() { end(err) }()
 
-	if d.isController() {
+	// a controller may only attach volumes to the instance specified by the
+	// context since it is not an instance itself
+	_, remote := context.InstanceID(ctx)
+	if d.isController() && !remote {
 		return nil, "", utils.NewUnsupportedForClientTypeError(
 			d.clientType, "VolumeAttach")
 	}
@@ -233,31 +351,54 @@ func (d *driver) VolumeAttach(
 		return nil, "", goof.New("missing service name")
 	}
 
-	nextDevice, err := d.NextDevice(ctx, utils.NewStore())
-	if err != nil {
-		return nil, "", err
+	if context.DryRun(ctx) {
+		vol, err := d.dryRunVolumeAttach(ctx, serviceName, volumeID, opts)
+		return vol, "", err
 	}
 
-	var nextDevicePtr *string
-	if nextDevice != "" {
-		nextDevicePtr = &nextDevice
+	// the next available device can only be determined for the local
+	// instance, and only if the caller did not request a device
+	nextDevicePtr := opts.NextDevice
+	if nextDevicePtr == nil && !d.isController() {
+		nextDevice, err := d.NextDevice(ctx, utils.NewStore())
+		if err != nil {
+			return nil, "", err
+		}
+		if nextDevice != "" {
+			nextDevicePtr = &nextDevice
+		}
 	}
 
 	req := &types.VolumeAttachRequest{
 		NextDeviceName: nextDevicePtr,
 		Force:          opts.Force,
+		MultiAttach:    opts.MultiAttach,
+		ReadOnly:       opts.ReadOnly,
 		Opts:           opts.Opts.Map(),
 	}
 
-	return d.client.VolumeAttach(ctx, serviceName, volumeID, req)
+	vol, token, err := d.client.VolumeAttach(ctx, serviceName, volumeID, req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	notifyVolumeObserver(ctx, func(o types.VolumeObserver) {
+		o.OnAttach(ctx, vol)
+	})
+	return vol, token, nil
 }
 
 func (d *driver) VolumeDetach(
 	ctx types.Context,
 	volumeID string,
-	opts *types.VolumeDetachOpts) (*types.Volume, error) {
+	opts *types.VolumeDetachOpts) (_ *types.Volume, err error) {
+
+	ctx, end := context.StartSpan(ctx, "VolumeDetach", log.Fields{
+		"volumeID": volumeID,
+	})
+	defer func() { end(err) }()
 
-	if d.isController() {
+	if _, remote := context.InstanceID(ctx); d.isController() && !remote {
 		return nil, utils.NewUnsupportedForClientTypeError(
 			d.clientType, "VolumeDetach")
 	}
@@ -268,17 +409,32 @@ func (d *driver) VolumeDetach(
 		return nil, goof.New("missing service name")
 	}
 
+	if context.DryRun(ctx) {
+		return d.dryRunVolumeDetach(ctx, serviceName, volumeID, opts)
+	}
+
 	req := &types.VolumeDetachRequest{
 		Force: opts.Force,
 		Opts:  opts.Opts.Map(),
 	}
 
-	return d.client.VolumeDetach(ctx, serviceName, volumeID, req)
+	vol, err := d.client.VolumeDetach(ctx, serviceName, volumeID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyVolumeObserver(ctx, func(o types.VolumeObserver) {
+		o.OnDetach(ctx, vol)
+	})
+	return vol, nil
 }
 
 func (d *driver) Snapshots(
 	ctx types.Context,
-	opts types.Store) ([]*types.Snapshot, error) {
+	opts types.Store) (_ []*types.Snapshot, err error) {
+
+	ctx, end := context.StartSpan(ctx, "Snapshots", nil)
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -303,7 +459,12 @@ func (d *driver) Snapshots(
 func (d *driver) SnapshotInspect(
 	ctx types.Context,
 	snapshotID string,
-	opts types.Store) (*types.Snapshot, error) {
+	opts types.Store) (_ *types.Snapshot, err error) {
+
+	ctx, end := context.StartSpan(ctx, "SnapshotInspect", log.Fields{
+		"snapshotID": snapshotID,
+	})
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -317,7 +478,13 @@ func (d *driver) SnapshotInspect(
 func (d *driver) SnapshotCopy(
 	ctx types.Context,
 	snapshotID, snapshotName, destinationID string,
-	opts types.Store) (*types.Snapshot, error) {
+	opts types.Store) (_ *types.Snapshot, err error) {
+
+	ctx, end := context.StartSpan(ctx, "SnapshotCopy", log.Fields{
+		"snapshotID":    snapshotID,
+		"destinationID": destinationID,
+	})
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -325,6 +492,20 @@ func (d *driver) SnapshotCopy(
 		return nil, goof.New("missing service name")
 	}
 
+	if context.DryRun(ctx) {
+		snap, err := d.client.SnapshotInspect(ctx, serviceName, snapshotID)
+		if err != nil {
+			return nil, err
+		}
+		return &types.Snapshot{
+			Name:       snapshotName,
+			Encrypted:  snap.Encrypted,
+			Status:     types.SnapshotStatusDryRun,
+			VolumeID:   snap.VolumeID,
+			VolumeSize: snap.VolumeSize,
+		}, nil
+	}
+
 	req := &types.SnapshotCopyRequest{
 		SnapshotName:  snapshotName,
 		DestinationID: destinationID,
@@ -337,7 +518,12 @@ func (d *driver) SnapshotCopy(
 func (d *driver) SnapshotRemove(
 	ctx types.Context,
 	snapshotID string,
-	opts types.Store) error {
+	opts types.Store) (err error) {
+
+	ctx, end := context.StartSpan(ctx, "SnapshotRemove", log.Fields{
+		"snapshotID": snapshotID,
+	})
+	defer func() { end(err) }()
 
 	ctx = d.requireCtx(ctx)
 	serviceName, ok := context.ServiceName(ctx)
@@ -345,6 +531,11 @@ func (d *driver) SnapshotRemove(
 		return goof.New("missing service name")
 	}
 
+	if context.DryRun(ctx) {
+		_, err := d.client.SnapshotInspect(ctx, serviceName, snapshotID)
+		return err
+	}
+
 	return d.client.SnapshotRemove(ctx, serviceName, snapshotID)
 }
 
@@ -3,8 +3,11 @@ package libstorage
 import (
 	"strings"
 
+	"github.com/akutz/goof"
+
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
 )
 
 func (c *client) requireCtx(ctx types.Context) types.Context {
@@ -48,6 +51,12 @@ func (c *client) withInstanceID(
 
 	ctx = ctx.WithValue(context.ServiceKey, service)
 
+	// an instance ID already in the context targets a specific, possibly
+	// remote, instance and takes precedence over the local instance's ID
+	if _, ok := context.InstanceID(ctx); ok {
+		return ctx
+	}
+
 	if c.isController() {
 		return ctx
 	}
@@ -96,3 +105,151 @@ func (c *client) withAllLocalDevices(ctx types.Context) (types.Context, error) {
 
 	return ctx.WithValue(context.AllLocalDevicesKey, ldm), nil
 }
+
+// notifyVolumeObserver asynchronously notifies the context's VolumeObserver,
+// if any, so that the observer does not delay the operation.
+func notifyVolumeObserver(
+	ctx types.Context, notify func(o types.VolumeObserver)) {
+
+	if o, ok := context.VolumeObserver(ctx); ok {
+		go notify(o)
+	}
+}
+
+// dryRunVolumeCreate returns the volume that creating a volume with the
+// provided name and options would produce.
+func dryRunVolumeCreate(
+	name string, opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	if name == "" {
+		return nil, goof.New("missing volume name")
+	}
+
+	vol := &types.Volume{
+		Name:   name,
+		Status: types.VolumeStatusDryRun,
+		Tags:   opts.Tags,
+	}
+	if opts.AvailabilityZone != nil {
+		vol.AvailabilityZone = *opts.AvailabilityZone
+	}
+	if opts.Encrypted != nil {
+		vol.Encrypted = *opts.Encrypted
+	}
+	if opts.EncryptionKey != nil {
+		vol.EncryptionKey = *opts.EncryptionKey
+	}
+	if opts.IOPS != nil {
+		vol.IOPS = *opts.IOPS
+	}
+	if opts.Throughput != nil {
+		vol.Throughput = *opts.Throughput
+	}
+	if opts.Size != nil {
+		vol.Size = *opts.Size
+	}
+	if opts.Type != nil {
+		vol.Type = *opts.Type
+	}
+	return vol, nil
+}
+
+// dryRunVolumeCopy validates that the source volume exists and returns the
+// volume that copying it would produce.
+func (d *driver) dryRunVolumeCopy(
+	ctx types.Context,
+	service, volumeID, volumeName string) (*types.Volume, error) {
+
+	src, err := d.client.VolumeInspect(ctx, service, volumeID, types.VolAttNone)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Volume{
+		Name:             volumeName,
+		AvailabilityZone: src.AvailabilityZone,
+		Encrypted:        src.Encrypted,
+		IOPS:             src.IOPS,
+		Size:             src.Size,
+		Status:           types.VolumeStatusDryRun,
+		Type:             src.Type,
+	}, nil
+}
+
+// dryRunVolumeSnapshot validates that the volume exists and returns the
+// snapshot that snapshotting it would produce.
+func (d *driver) dryRunVolumeSnapshot(
+	ctx types.Context,
+	service, volumeID, snapshotName string) (*types.Snapshot, error) {
+
+	vol, err := d.client.VolumeInspect(ctx, service, volumeID, types.VolAttNone)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Snapshot{
+		Name:       snapshotName,
+		Encrypted:  vol.Encrypted,
+		Status:     types.SnapshotStatusDryRun,
+		VolumeID:   vol.ID,
+		VolumeSize: vol.Size,
+	}, nil
+}
+
+// dryRunVolumeAttach validates that the volume exists and may be attached to
+// the instance and returns the volume as it is prior to the attachment.
+func (d *driver) dryRunVolumeAttach(
+	ctx types.Context,
+	service, volumeID string,
+	opts *types.VolumeAttachOpts) (*types.Volume, error) {
+
+	vol, err := d.client.VolumeInspect(
+		ctx, service, volumeID, types.VolAttReqForInstance)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Force {
+		return vol, nil
+	}
+	switch vol.AttachmentState {
+	case types.VolumeAttached:
+		return nil, utils.NewVolumeAlreadyAttachedErr(volumeID)
+	case types.VolumeUnavailable:
+		if !opts.MultiAttach {
+			return nil, utils.NewVolumeAlreadyAttachedErr(volumeID)
+		}
+	}
+	return vol, nil
+}
+
+// dryRunVolumeDetach validates that the volume exists and is attached to the
+// instance and returns the volume as it is prior to the detachment.
+func (d *driver) dryRunVolumeDetach(
+	ctx types.Context,
+	service, volumeID string,
+	opts *types.VolumeDetachOpts) (*types.Volume, error) {
+
+	vol, err := d.client.VolumeInspect(
+		ctx, service, volumeID, types.VolAttReqForInstance)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.Force && vol.AttachmentState == types.VolumeAvailable {
+		return nil, utils.NewVolumeNotAttachedErr(volumeID)
+	}
+	return vol, nil
+}
+
+// dryRunVolumeRemove validates that the volume exists and may be removed.
+func (d *driver) dryRunVolumeRemove(
+	ctx types.Context,
+	service, volumeID string,
+	opts *types.VolumeRemoveOpts) error {
+
+	vol, err := d.client.VolumeInspect(ctx, service, volumeID, types.VolAttReq)
+	if err != nil {
+		return err
+	}
+	if !opts.Force && len(vol.Attachments) > 0 {
+		return utils.NewVolumeAttachedErr(volumeID)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/gotil"
+
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+
+	"github.com/codedellemc/libstorage/drivers/storage/loopback"
+	"github.com/codedellemc/libstorage/drivers/storage/loopback/utils"
+)
+
+// driver is the storage executor for the loopback storage driver.
+type driver struct {
+	config gofig.Config
+}
+
+func init() {
+	registry.RegisterStorageExecutor(loopback.Name, newDriver)
+}
+
+func newDriver() types.StorageExecutor {
+	return &driver{}
+}
+
+func (d *driver) Init(ctx types.Context, config gofig.Config) error {
+	d.config = config
+	return nil
+}
+
+func (d *driver) Name() string {
+	return loopback.Name
+}
+
+// Supported returns a flag indicating whether or not the platform
+// implementing the executor is valid for the host on which the executor
+// resides.
+func (d *driver) Supported(
+	ctx types.Context,
+	opts types.Store) (bool, error) {
+
+	return gotil.FileExistsInPath("losetup"), nil
+}
+
+// InstanceID returns the local instance ID.
+func (d *driver) InstanceID(
+	ctx types.Context,
+	opts types.Store) (*types.InstanceID, error) {
+	return utils.InstanceID(ctx)
+}
+
+// NextDevice returns the next free loop device.
+func (d *driver) NextDevice(
+	ctx types.Context,
+	opts types.Store) (string, error) {
+	return utils.NextLoopDevice(ctx)
+}
+
+// LocalDevices returns a map of the host's configured loop devices.
+func (d *driver) LocalDevices(
+	ctx types.Context,
+	opts *types.LocalDevicesOpts) (*types.LocalDevices, error) {
+
+	devs, err := utils.LoopDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	devMap := map[string]string{}
+	for devName := range devs {
+		devMap[devName] = devName
+	}
+
+	ld := &types.LocalDevices{Driver: d.Name()}
+	if len(devMap) > 0 {
+		ld.DeviceMap = devMap
+	}
+
+	return ld, nil
+}
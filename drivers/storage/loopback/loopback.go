@@ -0,0 +1,48 @@
+package loopback
+
+import (
+	"path"
+
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const (
+	// Name is the name of the driver.
+	Name = "loopback"
+
+	// ConfigLoopbackRoot is a config key.
+	ConfigLoopbackRoot = Name + ".root"
+)
+
+func init() {
+	registry.RegisterConfigReg(
+		"Loopback",
+		func(ctx types.Context, r gofig.ConfigRegistration) {
+			root := path.Join(context.MustPathConfig(ctx).Lib, Name)
+			r.Key(
+				gofig.String,
+				"",
+				root,
+				"The directory in which the loopback volume files are stored",
+				ConfigLoopbackRoot)
+		})
+}
+
+// RootDir returns the path to the loopback root directory.
+func RootDir(config gofig.Config) string {
+	return config.GetString(ConfigLoopbackRoot)
+}
+
+// VolumesDirPath returns the path to the loopback volumes directory.
+func VolumesDirPath(config gofig.Config) string {
+	return path.Join(RootDir(config), "vol")
+}
+
+// SnapshotsDirPath returns the path to the loopback snapshots directory.
+func SnapshotsDirPath(config gofig.Config) string {
+	return path.Join(RootDir(config), "snap")
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	apiUtils "github.com/codedellemc/libstorage/api/utils"
+)
+
+// writeTestArtifact writes a snapshot artifact for the snapshot to a file in
+// the directory and returns the file's URI.
+func writeTestArtifact(
+	t *testing.T, dir string, snap *types.Snapshot) string {
+
+	data := []byte("snapshot data")
+	buf := &bytes.Buffer{}
+	if err := apiUtils.WriteSnapshotArtifact(
+		buf, snap, int64(len(data)), bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	p := path.Join(dir, "artifact.tar")
+	if err := ioutil.WriteFile(p, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return "file://" + p
+}
+
+func TestSnapshotImportRejectsPaths(t *testing.T) {
+	root, err := ioutil.TempDir("", "loopback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	d := &driver{
+		volPath:  path.Join(root, "vols"),
+		snapPath: path.Join(root, "snaps"),
+	}
+	os.MkdirAll(d.volPath, 0755)
+	os.MkdirAll(d.snapPath, 0755)
+	ctx := context.Background()
+
+	for _, snap := range []*types.Snapshot{
+		{Name: "snap-000", VolumeID: ".."},
+		{Name: "snap-000", VolumeID: "../../escaped"},
+		{Name: "../escaped", VolumeID: "vol-000"},
+		{Name: "..", VolumeID: "vol-000"},
+		{Name: ".", VolumeID: "vol-000"},
+		{Name: `snap\000`, VolumeID: "vol-000"},
+		{Name: "snap-000", VolumeID: `vol\000`},
+	} {
+		uri := writeTestArtifact(t, root, snap)
+		_, err := d.SnapshotImport(ctx, uri, "", nil)
+		assert.IsType(t, &types.ErrInvalidArtifact{}, err,
+			"%s %s", snap.Name, snap.VolumeID)
+	}
+
+	// the names passed to the driver are validated as well
+	uri := writeTestArtifact(t, root,
+		&types.Snapshot{Name: "snap-000", VolumeID: "vol-000"})
+	for _, name := range []string{"..", "../escaped", "a/b"} {
+		_, err := d.SnapshotImport(ctx, uri, name, nil)
+		assert.Error(t, err, name)
+	}
+
+	// nothing was written outside of the snapshots directory
+	entries, _ := ioutil.ReadDir(root)
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"artifact.tar", "snaps", "vols"}, names)
+	entries, _ = ioutil.ReadDir(d.snapPath)
+	assert.Empty(t, entries)
+
+	snap, err := d.SnapshotImport(ctx, uri, "", nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "snap-000", snap.Name)
+		assert.Equal(t, "vol-000", snap.VolumeID)
+	}
+}
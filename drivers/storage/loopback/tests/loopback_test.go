@@ -0,0 +1,15 @@
+package loopback
+
+import (
+	"testing"
+
+	apitests "github.com/codedellemc/libstorage/api/tests"
+
+	// load the driver packages
+	"github.com/codedellemc/libstorage/drivers/storage/loopback"
+	_ "github.com/codedellemc/libstorage/drivers/storage/loopback/storage"
+)
+
+func TestSuite(t *testing.T) {
+	apitests.RunSuite(t, loopback.Name)
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"io"
+	"os"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/drivers/storage/loopback"
+)
+
+// InstanceID returns the instance ID for the local host.
+func InstanceID(ctx types.Context) (*types.InstanceID, error) {
+	hostName, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return &types.InstanceID{ID: hostName, Driver: loopback.Name}, nil
+}
+
+const copyBufSize = 64 * 1024
+
+// CopyFile copies the file at src to dst. Blocks of zeroes are skipped
+// rather than written so that copies of sparse files remain sparse.
+func CopyFile(src, dst string) error {
+	s, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	fi, err := s.Stat()
+	if err != nil {
+		return err
+	}
+
+	d, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	buf := make([]byte, copyBufSize)
+	for {
+		n, err := s.Read(buf)
+		if n > 0 {
+			if isZeroes(buf[:n]) {
+				if _, err := d.Seek(int64(n), os.SEEK_CUR); err != nil {
+					return err
+				}
+			} else if _, err := d.Write(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.Truncate(fi.Size())
+}
+
+func isZeroes(buf []byte) bool {
+	for _, b := range buf {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
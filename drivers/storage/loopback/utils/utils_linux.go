@@ -0,0 +1,83 @@
+// +build linux
+
+package utils
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const sysBlockLoopGlob = "/sys/block/loop*/loop/backing_file"
+
+// LoopDevices returns a map of the host's configured loop devices, ex.
+// "/dev/loop0", to the paths of their backing files.
+func LoopDevices(ctx types.Context) (map[string]string, error) {
+	bfs, err := filepath.Glob(sysBlockLoopGlob)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]string{}
+	for _, bf := range bfs {
+		buf, err := ioutil.ReadFile(bf)
+		if err != nil {
+			continue
+		}
+		devName := path.Base(path.Dir(path.Dir(bf)))
+		filePath := strings.TrimSuffix(
+			strings.TrimSpace(string(buf)), " (deleted)")
+		m[path.Join("/dev", devName)] = filePath
+	}
+	return m, nil
+}
+
+// NextLoopDevice returns the path of the next free loop device.
+func NextLoopDevice(ctx types.Context) (string, error) {
+	out, err := exec.Command("losetup", "-f").CombinedOutput()
+	if err != nil {
+		return "", goof.WithFieldE(
+			"output", string(out), "error finding free loop device", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// LoopAttach attaches the file to a loop device and returns the device's
+// path. If deviceName is empty the next free loop device is used.
+func LoopAttach(
+	ctx types.Context, deviceName, filePath string) (string, error) {
+
+	args := []string{"-f", "--show", filePath}
+	if deviceName != "" {
+		args = []string{deviceName, filePath}
+	}
+	out, err := exec.Command("losetup", args...).CombinedOutput()
+	if err != nil {
+		return "", goof.WithFieldsE(goof.Fields{
+			"deviceName": deviceName,
+			"filePath":   filePath,
+			"output":     string(out),
+		}, "error attaching loop device", err)
+	}
+	if deviceName != "" {
+		return deviceName, nil
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// LoopDetach detaches the loop device.
+func LoopDetach(ctx types.Context, deviceName string) error {
+	out, err := exec.Command("losetup", "-d", deviceName).CombinedOutput()
+	if err != nil {
+		return goof.WithFieldsE(goof.Fields{
+			"deviceName": deviceName,
+			"output":     string(out),
+		}, "error detaching loop device", err)
+	}
+	return nil
+}
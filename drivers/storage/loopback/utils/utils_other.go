@@ -0,0 +1,30 @@
+// +build !linux
+
+package utils
+
+import (
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// LoopDevices returns a map of the host's configured loop devices to the
+// paths of their backing files.
+func LoopDevices(ctx types.Context) (map[string]string, error) {
+	return nil, types.ErrNotImplemented
+}
+
+// NextLoopDevice returns the path of the next free loop device.
+func NextLoopDevice(ctx types.Context) (string, error) {
+	return "", types.ErrNotImplemented
+}
+
+// LoopAttach attaches the file to a loop device and returns the device's
+// path.
+func LoopAttach(
+	ctx types.Context, deviceName, filePath string) (string, error) {
+	return "", types.ErrNotImplemented
+}
+
+// LoopDetach detaches the loop device.
+func LoopDetach(ctx types.Context, deviceName string) error {
+	return types.ErrNotImplemented
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loopback")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	src := path.Join(dir, "src")
+	dst := path.Join(dir, "dst")
+
+	f, err := os.Create(src)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_, err = f.WriteAt([]byte("hello"), 3*copyBufSize)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Truncate(5*copyBufSize))
+	assert.NoError(t, f.Close())
+
+	if !assert.NoError(t, CopyFile(src, dst)) {
+		t.FailNow()
+	}
+
+	srcBuf, err := ioutil.ReadFile(src)
+	assert.NoError(t, err)
+	dstBuf, err := ioutil.ReadFile(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, srcBuf, dstBuf)
+
+	assert.Error(t, CopyFile(src, dst))
+}
@@ -0,0 +1,16 @@
+// +build !windows
+
+package utils
+
+import (
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// NextDeviceInfo is the NextDeviceInfo object for loopback devices, ex.
+// "/dev/loop0". The executor asks losetup for the next free device, so the
+// pattern only describes the loop devices most systems provide by default.
+var NextDeviceInfo = &types.NextDeviceInfo{
+	Prefix:  "loop",
+	Pattern: "[0-9]",
+	Ignore:  false,
+}
@@ -0,0 +1,13 @@
+// +build windows
+
+package utils
+
+import (
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// NextDeviceInfo is the NextDeviceInfo object for loopback devices. Loopback
+// devices are not available on Windows.
+var NextDeviceInfo = &types.NextDeviceInfo{
+	Ignore: true,
+}
@@ -5,8 +5,10 @@ package mock
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
 	"github.com/akutz/goof"
 
 	"github.com/codedellemc/libstorage/api/context"
@@ -23,12 +25,31 @@ const (
 
 type driver struct {
 	executor.Executor
+	sync.RWMutex
 	nextDeviceInfo *types.NextDeviceInfo
 	volumes        []*types.Volume
 	snapshots      []*types.Snapshot
 	storageType    types.StorageType
+	volCount       int
+	snapCount      int
+
+	// failAt is a map of operation names to the invocation of the operation
+	// that should fail, and calls is the number of times each operation has
+	// been invoked.
+	failAt map[string]int
+	calls  map[string]int
 }
 
+const (
+	opVolumeCreate   = "volumeCreate"
+	opVolumeRemove   = "volumeRemove"
+	opVolumeAttach   = "volumeAttach"
+	opVolumeDetach   = "volumeDetach"
+	opVolumeSnapshot = "volumeSnapshot"
+)
+
+var errInjected = goof.New("injected failure")
+
 func init() {
 	registry.RegisterStorageDriver(Name, newDriver)
 }
@@ -88,9 +109,79 @@ func newDriver() types.StorageDriver {
 		},
 	}
 
+	d.volCount = len(d.volumes)
+	d.snapCount = len(d.snapshots)
+	d.failAt = map[string]int{}
+	d.calls = map[string]int{}
+
 	return d
 }
 
+// Init initializes the driver. Failures may be injected into the driver's
+// operations by setting mock.failures.<operation> to the invocation of the
+// operation that should fail. For example, setting
+// mock.failures.volumeAttach to 2 causes the second VolumeAttach to fail.
+func (d *driver) Init(ctx types.Context, config gofig.Config) error {
+	if err := d.Executor.Init(ctx, config); err != nil {
+		return err
+	}
+	for _, op := range []string{
+		opVolumeCreate,
+		opVolumeRemove,
+		opVolumeAttach,
+		opVolumeDetach,
+		opVolumeSnapshot,
+	} {
+		if n := config.GetInt(fmt.Sprintf("mock.failures.%s", op)); n > 0 {
+			d.failAt[op] = n
+		}
+	}
+	return nil
+}
+
+// fail records an invocation of the specified operation and returns an error
+// if the invocation is configured to fail. The caller must hold the lock.
+func (d *driver) fail(op string) error {
+	d.calls[op]++
+	if n, ok := d.failAt[op]; ok && n == d.calls[op] {
+		return goof.WithFields(goof.Fields{
+			"operation":  op,
+			"invocation": n,
+		}, errInjected.Error())
+	}
+	return nil
+}
+
+func (d *driver) nextVolumeID() string {
+	d.volCount++
+	return fmt.Sprintf("vol-%03d", d.volCount)
+}
+
+func (d *driver) nextSnapshotID() string {
+	d.snapCount++
+	return fmt.Sprintf("snap-%03d", d.snapCount)
+}
+
+// getVolume returns the volume with the specified ID. The caller must hold
+// the lock.
+func (d *driver) getVolume(volumeID string) *types.Volume {
+	for _, v := range d.volumes {
+		if strings.ToLower(v.ID) == strings.ToLower(volumeID) {
+			return v
+		}
+	}
+	return nil
+}
+
+// settle transitions a volume out of a pending state. Volumes are created in
+// the creating state and become available once they have been observed,
+// simulating the asynchronous behavior of a real storage platform.
+func settle(v *types.Volume) {
+	if v.Status == types.VolumeStatusCreating {
+		v.Status = types.VolumeStatusAvailable
+	}
+}
+
 func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Block, nil
 }
@@ -111,13 +202,16 @@ func (d *driver) Volumes(
 	ctx types.Context,
 	opts *types.VolumesOpts) ([]*types.Volume, error) {
 
+	d.Lock()
+	defer d.Unlock()
+
 	xiid := executor.GetInstanceID()
 
 	if serviceName, ok := context.ServiceName(ctx); ok && serviceName == Name {
 		if ld, ok := context.LocalDevices(ctx); ok {
 			ldm := ld.DeviceMap
 
-			if opts.Attachments {
+			if opts.Attachments.Requested() {
 
 				iid := context.MustInstanceID(ctx)
 				if iid.ID == xiid.ID {
@@ -151,6 +245,10 @@ func (d *driver) Volumes(
 		}
 	}
 
+	for _, v := range d.volumes {
+		settle(v)
+	}
+
 	return d.volumes, nil
 }
 
@@ -159,12 +257,14 @@ func (d *driver) VolumeInspect(
 	volumeID string,
 	opts *types.VolumeInspectOpts) (*types.Volume, error) {
 
-	for _, v := range d.volumes {
-		if strings.ToLower(v.ID) == strings.ToLower(volumeID) {
-			return v, nil
-		}
+	d.Lock()
+	defer d.Unlock()
+
+	if v := d.getVolume(volumeID); v != nil {
+		settle(v)
+		return v, nil
 	}
-	return nil, utils.NewNotFoundError(volumeID)
+	return nil, utils.NewVolumeNotFoundErr(volumeID, nil)
 }
 
 func (d *driver) VolumeCreate(
@@ -183,11 +283,18 @@ func (d *driver) VolumeCreate(
 			),
 		)
 	}
-	lenVols := len(d.volumes)
+
+	d.Lock()
+	defer d.Unlock()
+
+	if err := d.fail(opVolumeCreate); err != nil {
+		return nil, err
+	}
 
 	volume := &types.Volume{
 		Name:   name,
-		ID:     fmt.Sprintf("vol-%03d", lenVols+1),
+		ID:     d.nextVolumeID(),
+		Status: types.VolumeStatusCreating,
 		Fields: map[string]string{},
 	}
 
@@ -233,11 +340,13 @@ func (d *driver) VolumeCreateFromSnapshot(
 		return nil, err
 	}
 
-	lenVols := len(d.volumes)
+	d.Lock()
+	defer d.Unlock()
 
 	volume := &types.Volume{
 		Name:   volumeName,
-		ID:     fmt.Sprintf("vol-%03d", lenVols+1),
+		ID:     d.nextVolumeID(),
+		Status: types.VolumeStatusCreating,
 		Fields: map[string]string{},
 	}
 
@@ -260,6 +369,9 @@ func (d *driver) VolumeCreateFromSnapshot(
 	if opts.Opts.IsSet("priority") {
 		volume.Fields["priority"] = opts.Opts.GetString("priority")
 	}
+
+	d.volumes = append(d.volumes, volume)
+
 	return volume, nil
 }
 
@@ -273,19 +385,18 @@ func (d *driver) VolumeCopy(
 		"volumeName": volumeName,
 	}).Debug("mockDriver.VolumeCopy")
 
-	lenVols := len(d.volumes)
+	d.Lock()
+	defer d.Unlock()
 
-	var ogvol *types.Volume
-	for _, v := range d.volumes {
-		if strings.ToLower(v.ID) == strings.ToLower(volumeID) {
-			ogvol = v
-			break
-		}
+	ogvol := d.getVolume(volumeID)
+	if ogvol == nil {
+		return nil, utils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
 	volume := &types.Volume{
 		Name:             volumeName,
-		ID:               fmt.Sprintf("vol-%03d", lenVols+1),
+		ID:               d.nextVolumeID(),
+		Status:           types.VolumeStatusCreating,
 		AvailabilityZone: ogvol.AvailabilityZone,
 		Type:             ogvol.Type,
 		Size:             ogvol.Size,
@@ -312,12 +423,18 @@ func (d *driver) VolumeSnapshot(
 		"snapshotName": snapshotName,
 	}).Debug("mockDriver.VolumeSnapshot")
 
-	lenSnaps := len(d.snapshots)
+	d.Lock()
+	defer d.Unlock()
+
+	if err := d.fail(opVolumeSnapshot); err != nil {
+		return nil, err
+	}
 
 	snapshot := &types.Snapshot{
 		Name:     snapshotName,
-		ID:       fmt.Sprintf("snap-%03d", lenSnaps+1),
+		ID:       d.nextSnapshotID(),
 		VolumeID: volumeID,
+		Progress: 100,
 		Fields:   map[string]string{},
 	}
 
@@ -335,6 +452,13 @@ func (d *driver) VolumeRemove(
 		"volumeID": volumeID,
 	}).Debug("mockDriver.VolumeRemove")
 
+	d.Lock()
+	defer d.Unlock()
+
+	if err := d.fail(opVolumeRemove); err != nil {
+		return err
+	}
+
 	var xToRemove int
 	var volume *types.Volume
 	for x, v := range d.volumes {
@@ -359,14 +483,19 @@ func (d *driver) VolumeAttach(
 	volumeID string,
 	opts *types.VolumeAttachOpts) (*types.Volume, string, error) {
 
-	var modVol *types.Volume
-	for _, vol := range d.volumes {
-		if vol.ID == volumeID {
-			modVol = vol
-			break
-		}
+	d.Lock()
+	defer d.Unlock()
+
+	if err := d.fail(opVolumeAttach); err != nil {
+		return nil, "", err
 	}
 
+	modVol := d.getVolume(volumeID)
+	if modVol == nil {
+		return nil, "", utils.NewVolumeNotFoundErr(volumeID, nil)
+	}
+
+	modVol.Status = types.VolumeStatusInUse
 	modVol.Attachments = []*types.VolumeAttachment{
 		&types.VolumeAttachment{
 			DeviceName: *opts.NextDevice,
@@ -385,14 +514,19 @@ func (d *driver) VolumeDetach(
 	volumeID string,
 	opts *types.VolumeDetachOpts) (*types.Volume, error) {
 
-	var modVol *types.Volume
-	for _, vol := range d.volumes {
-		if vol.ID == volumeID {
-			modVol = vol
-			break
-		}
+	d.Lock()
+	defer d.Unlock()
+
+	if err := d.fail(opVolumeDetach); err != nil {
+		return nil, err
 	}
 
+	modVol := d.getVolume(volumeID)
+	if modVol == nil {
+		return nil, utils.NewVolumeNotFoundErr(volumeID, nil)
+	}
+
+	modVol.Status = types.VolumeStatusAvailable
 	modVol.Attachments = nil
 
 	return modVol, nil
@@ -403,7 +537,11 @@ func (d *driver) VolumeDetachAll(
 	volumeID string,
 	opts types.Store) error {
 
+	d.Lock()
+	defer d.Unlock()
+
 	for _, vol := range d.volumes {
+		vol.Status = types.VolumeStatusAvailable
 		vol.Attachments = nil
 	}
 
@@ -414,6 +552,9 @@ func (d *driver) Snapshots(
 	ctx types.Context,
 	opts types.Store) ([]*types.Snapshot, error) {
 
+	d.RLock()
+	defer d.RUnlock()
+
 	return d.snapshots, nil
 }
 
@@ -422,12 +563,15 @@ func (d *driver) SnapshotInspect(
 	snapshotID string,
 	opts types.Store) (*types.Snapshot, error) {
 
+	d.RLock()
+	defer d.RUnlock()
+
 	for _, v := range d.snapshots {
 		if strings.ToLower(v.ID) == strings.ToLower(snapshotID) {
 			return v, nil
 		}
 	}
-	return nil, goof.New("invalid snapshot id")
+	return nil, utils.NewSnapshotNotFoundErr(snapshotID, nil)
 }
 
 func (d *driver) SnapshotCopy(
@@ -441,7 +585,8 @@ func (d *driver) SnapshotCopy(
 		"destinationID": destinationID,
 	}).Debug("mockDriver.SnapshotCopy")
 
-	lenSnaps := len(d.snapshots)
+	d.Lock()
+	defer d.Unlock()
 
 	var ogsnap *types.Snapshot
 	for _, s := range d.snapshots {
@@ -450,10 +595,14 @@ func (d *driver) SnapshotCopy(
 			break
 		}
 	}
+	if ogsnap == nil {
+		return nil, utils.NewSnapshotNotFoundErr(snapshotID, nil)
+	}
 
 	snapshot := &types.Snapshot{
 		Name:     snapshotName,
-		ID:       fmt.Sprintf("snap-%03d", lenSnaps+1),
+		ID:       d.nextSnapshotID(),
+		Progress: 100,
 		VolumeID: ogsnap.VolumeID,
 		Fields:   map[string]string{},
 	}
@@ -476,6 +625,9 @@ func (d *driver) SnapshotRemove(
 		"snapshotID": snapshotID,
 	}).Debug("mockDriver.SnapshotRemove")
 
+	d.Lock()
+	defer d.Unlock()
+
 	var xToRemove int
 	var snapshot *types.Snapshot
 	for x, s := range d.snapshots {
@@ -487,7 +639,7 @@ func (d *driver) SnapshotRemove(
 	}
 
 	if snapshot == nil {
-		return utils.NewNotFoundError(snapshotID)
+		return utils.NewSnapshotNotFoundErr(snapshotID, nil)
 	}
 
 	d.snapshots = append(d.snapshots[:xToRemove], d.snapshots[xToRemove+1:]...)
@@ -493,6 +493,29 @@ func TestVolumeAttach(t *testing.T) {
 	apitests.Run(t, mock.Name, configYAML, tf)
 }
 
+func TestVolumeAttachInjectedFailure(t *testing.T) {
+	failYAML := []byte(`
+libstorage:
+  driver: mock
+mock:
+  failures:
+    volumeAttach: 2
+`)
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		nd := "/dev/xvde"
+		request := &types.VolumeAttachRequest{NextDeviceName: &nd}
+
+		_, _, err := client.API().VolumeAttach(
+			nil, mock.Name, "vol-001", request)
+		assert.NoError(t, err)
+
+		_, _, err = client.API().VolumeAttach(
+			nil, mock.Name, "vol-002", request)
+		assert.Error(t, err)
+	}
+	apitests.Run(t, mock.Name, failYAML, tf)
+}
+
 func TestVolumeDetach(t *testing.T) {
 	tf := func(config gofig.Config, client types.Client, t *testing.T) {
 		request := &types.VolumeDetachRequest{}
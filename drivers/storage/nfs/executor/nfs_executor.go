@@ -0,0 +1,178 @@
+package executor
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+	"github.com/akutz/gotil"
+
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+
+	"github.com/codedellemc/libstorage/drivers/storage/nfs"
+	"github.com/codedellemc/libstorage/drivers/storage/nfs/utils"
+)
+
+// driver is the storage executor for the nfs storage driver.
+type driver struct {
+	config gofig.Config
+	opts   string
+}
+
+func init() {
+	registry.RegisterStorageExecutor(nfs.Name, newDriver)
+}
+
+func newDriver() types.StorageExecutor {
+	return &driver{}
+}
+
+func (d *driver) Init(ctx types.Context, config gofig.Config) error {
+	d.config = config
+
+	opts := []string{}
+	if v := d.config.GetString(nfs.ConfigNFSVers); v != "" {
+		opts = append(opts, fmt.Sprintf("%s=%s", nfs.Vers, v))
+	}
+	if v := d.config.GetInt(nfs.ConfigNFSRSize); v > 0 {
+		opts = append(opts, fmt.Sprintf("%s=%d", nfs.RSize, v))
+	}
+	if v := d.config.GetInt(nfs.ConfigNFSWSize); v > 0 {
+		opts = append(opts, fmt.Sprintf("%s=%d", nfs.WSize, v))
+	}
+	d.opts = strings.Join(opts, ",")
+
+	ctx.WithFields(log.Fields{
+		"driver": nfs.Name,
+		"opts":   d.opts,
+	}).Debug("storage executor initialized")
+	return nil
+}
+
+func (d *driver) Name() string {
+	return nfs.Name
+}
+
+// Supported returns a flag indicating whether or not the platform
+// implementing the executor is valid for the host on which the executor
+// resides.
+func (d *driver) Supported(
+	ctx types.Context,
+	opts types.Store) (bool, error) {
+
+	return gotil.FileExistsInPath("mount.nfs"), nil
+}
+
+// InstanceID returns the local instance ID.
+func (d *driver) InstanceID(
+	ctx types.Context,
+	opts types.Store) (*types.InstanceID, error) {
+	return utils.InstanceID(ctx, d.config)
+}
+
+// NextDevice returns the next available device.
+func (d *driver) NextDevice(
+	ctx types.Context,
+	opts types.Store) (string, error) {
+	return "", types.ErrNotImplemented
+}
+
+// LocalDevices returns a map of the host's NFS mounts, keyed by the mounts'
+// sources, "host:/export/volumeID".
+func (d *driver) LocalDevices(
+	ctx types.Context,
+	opts *types.LocalDevicesOpts) (*types.LocalDevices, error) {
+
+	devs, err := getNFSMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &types.LocalDevices{Driver: nfs.Name, DeviceMap: devs}, nil
+}
+
+// Mount mounts the NFS volume to the specified path using the configured
+// vers, rsize, and wsize options.
+func (d *driver) Mount(
+	ctx types.Context,
+	deviceName, mountPoint string,
+	opts *types.DeviceMountOpts) error {
+
+	if devs, err := getNFSMounts(ctx); err == nil {
+		if mp, ok := devs[deviceName]; ok {
+			if mp == mountPoint {
+				return nil
+			}
+			return goof.WithFields(goof.Fields{
+				"deviceName": deviceName,
+				"mountPoint": mp,
+			}, "nfs volume is already mounted")
+		}
+	}
+
+	args := []string{"-t", nfs.Name}
+	if d.opts != "" {
+		args = append(args, "-o", d.opts)
+	}
+	args = append(args, deviceName, mountPoint)
+
+	fields := log.Fields{
+		"deviceName": deviceName,
+		"mountPoint": mountPoint,
+		"args":       args,
+	}
+	ctx.WithFields(fields).Debug("attempting nfs mount")
+
+	out, err := exec.Command("mount", args...).CombinedOutput()
+	if err != nil {
+		fields["output"] = string(out)
+		return goof.WithFieldsE(fields, "error mounting nfs volume", err)
+	}
+
+	return nil
+}
+
+// Unmount unmounts the NFS volume from the specified path.
+func (d *driver) Unmount(
+	ctx types.Context,
+	mountPoint string,
+	opts types.Store) error {
+
+	out, err := exec.Command("umount", mountPoint).CombinedOutput()
+	if err != nil {
+		return goof.WithFieldsE(goof.Fields{
+			"mountPoint": mountPoint,
+			"output":     string(out),
+		}, "error unmounting nfs volume", err)
+	}
+	return nil
+}
+
+// Mounts get a list of mount points.
+func (d *driver) Mounts(
+	ctx types.Context,
+	opts types.Store) ([]*types.MountInfo, error) {
+
+	devs, err := getNFSMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(devs) == 0 {
+		return nil, nil
+	}
+
+	mounts := []*types.MountInfo{}
+	for k, v := range devs {
+		mounts = append(mounts, &types.MountInfo{
+			Source:     k,
+			MountPoint: v,
+			FSType:     nfs.Name,
+		})
+	}
+
+	return mounts, nil
+}
@@ -0,0 +1,65 @@
+// +build linux
+
+package executor
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func getNFSMounts(ctx types.Context) (map[string]string, error) {
+	f, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseNFSMounts(f)
+}
+
+// parseNFSMounts parses a mount table in the format of /proc/self/mounts
+// and returns a map of the NFS sources to their mount points. Only nfs and
+// nfs4 mounts are returned, so the nfsd and rpc_pipefs pseudo file systems
+// are ignored.
+func parseNFSMounts(r io.Reader) (map[string]string, error) {
+	m := map[string]string{}
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		if fields[2] != "nfs" && fields[2] != "nfs4" {
+			continue
+		}
+		m[unescapeMountField(fields[0])] = unescapeMountField(fields[1])
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// unescapeMountField replaces the octal escapes that the kernel writes in
+// place of spaces, tabs, newlines, and backslashes in a mount table field.
+func unescapeMountField(f string) string {
+	if !strings.Contains(f, `\`) {
+		return f
+	}
+	var b []byte
+	for i := 0; i < len(f); i++ {
+		if f[i] == '\\' && i+3 < len(f) {
+			if n, err := strconv.ParseUint(f[i+1:i+4], 8, 8); err == nil {
+				b = append(b, byte(n))
+				i += 3
+				continue
+			}
+		}
+		b = append(b, f[i])
+	}
+	return string(b)
+}
@@ -0,0 +1,51 @@
+// +build linux
+
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNFSMounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		table  string
+		mounts map[string]string
+	}{
+		{"empty", "", map[string]string{}},
+		{"no nfs mounts",
+			"/dev/xvda1 / ext4 rw,relatime 0 0\n" +
+				"proc /proc proc rw 0 0\n",
+			map[string]string{}},
+		{"nfs and nfs4",
+			"/dev/xvda1 / ext4 rw,relatime 0 0\n" +
+				"nfs.example.com:/exports/vol1 /mnt/nfs/vol1 nfs rw,vers=3 0 0\n" +
+				"nfs.example.com:/exports/vol2 /mnt/nfs/vol2 nfs4 rw,vers=4.1 0 0\n",
+			map[string]string{
+				"nfs.example.com:/exports/vol1": "/mnt/nfs/vol1",
+				"nfs.example.com:/exports/vol2": "/mnt/nfs/vol2",
+			}},
+		{"nfs pseudo file systems",
+			"nfsd /proc/fs/nfsd nfsd rw 0 0\n" +
+				"sunrpc /run/rpc_pipefs rpc_pipefs rw 0 0\n",
+			map[string]string{}},
+		{"escaped mount point",
+			`nfs.example.com:/exports/my\040vol /mnt/my\040vol nfs4 rw 0 0`,
+			map[string]string{
+				"nfs.example.com:/exports/my vol": "/mnt/my vol",
+			}},
+		{"short lines",
+			"\nnfs.example.com:/exports/vol1 /mnt/nfs/vol1\n",
+			map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		m, err := parseNFSMounts(strings.NewReader(tt.table))
+		if assert.NoError(t, err, tt.name) {
+			assert.Equal(t, tt.mounts, m, tt.name)
+		}
+	}
+}
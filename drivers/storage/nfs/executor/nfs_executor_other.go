@@ -0,0 +1,9 @@
+// +build !linux
+
+package executor
+
+import "github.com/codedellemc/libstorage/api/types"
+
+func getNFSMounts(ctx types.Context) (map[string]string, error) {
+	return nil, types.ErrNotImplemented
+}
@@ -0,0 +1,87 @@
+package nfs
+
+import (
+	"os"
+
+	gofigCore "github.com/akutz/gofig"
+	gofig "github.com/akutz/gofig/types"
+)
+
+const (
+	// Name is the provider's name.
+	Name = "nfs"
+
+	// DefaultVers is the default NFS protocol version.
+	DefaultVers = "4"
+
+	// Host is a key constant.
+	Host = "host"
+
+	// Export is a key constant.
+	Export = "export"
+
+	// LocalPath is a key constant.
+	LocalPath = "localPath"
+
+	// HostName is a key constant.
+	HostName = "hostName"
+
+	// Vers is a key constant.
+	Vers = "vers"
+
+	// RSize is a key constant.
+	RSize = "rsize"
+
+	// WSize is a key constant.
+	WSize = "wsize"
+)
+
+const (
+	// ConfigNFS is a config key.
+	ConfigNFS = Name
+
+	// ConfigNFSHost is a config key.
+	ConfigNFSHost = ConfigNFS + "." + Host
+
+	// ConfigNFSExport is a config key.
+	ConfigNFSExport = ConfigNFS + "." + Export
+
+	// ConfigNFSLocalPath is a config key.
+	ConfigNFSLocalPath = ConfigNFS + "." + LocalPath
+
+	// ConfigNFSHostName is a config key.
+	ConfigNFSHostName = ConfigNFS + "." + HostName
+
+	// ConfigNFSVers is a config key.
+	ConfigNFSVers = ConfigNFS + "." + Vers
+
+	// ConfigNFSRSize is a config key.
+	ConfigNFSRSize = ConfigNFS + "." + RSize
+
+	// ConfigNFSWSize is a config key.
+	ConfigNFSWSize = ConfigNFS + "." + WSize
+)
+
+func init() {
+	hostName, _ := os.Hostname()
+	r := gofigCore.NewRegistration("NFS")
+	r.Key(gofig.String, "", "", "The NFS server's host name or IP",
+		ConfigNFSHost)
+	r.Key(gofig.String, "", "", "The exported path on the NFS server",
+		ConfigNFSExport)
+	r.Key(gofig.String,
+		"",
+		"",
+		"The path at which the export is mounted on the libStorage server",
+		ConfigNFSLocalPath)
+	r.Key(gofig.String,
+		"",
+		hostName,
+		"The host name used as part of the instance ID.",
+		ConfigNFSHostName)
+	r.Key(gofig.String, "", DefaultVers, "The NFS protocol version",
+		ConfigNFSVers)
+	r.Key(gofig.Int, "", 0, "The NFS read buffer size", ConfigNFSRSize)
+	r.Key(gofig.Int, "", 0, "The NFS write buffer size", ConfigNFSWSize)
+	gofigCore.Register(r)
+}
@@ -0,0 +1,349 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+	apiUtils "github.com/codedellemc/libstorage/api/utils"
+
+	"github.com/codedellemc/libstorage/drivers/storage/nfs"
+	nfsUtils "github.com/codedellemc/libstorage/drivers/storage/nfs/utils"
+)
+
+// driver is the storage driver for NFS exports. Each volume is a directory
+// beneath the configured export. The libStorage server creates and removes
+// the directories through a local mount of the export while the clients'
+// storage executors mount the volumes' directories directly.
+type driver struct {
+	config    gofig.Config
+	host      string
+	export    string
+	localPath string
+}
+
+func init() {
+	registry.RegisterStorageDriver(nfs.Name, newDriver)
+}
+
+func newDriver() types.StorageDriver {
+	return &driver{}
+}
+
+func (d *driver) Name() string {
+	return nfs.Name
+}
+
+// Init initializes the driver.
+func (d *driver) Init(ctx types.Context, config gofig.Config) error {
+	d.config = config
+
+	if err := apiUtils.RequireConfigKeys(
+		config,
+		nfs.ConfigNFSHost,
+		nfs.ConfigNFSExport,
+		nfs.ConfigNFSLocalPath); err != nil {
+		return err
+	}
+
+	d.host = d.config.GetString(nfs.ConfigNFSHost)
+	d.export = d.config.GetString(nfs.ConfigNFSExport)
+	d.localPath = d.config.GetString(nfs.ConfigNFSLocalPath)
+
+	fields := log.Fields{
+		nfs.Host:      d.host,
+		nfs.Export:    d.export,
+		nfs.LocalPath: d.localPath,
+	}
+
+	if fi, err := os.Stat(d.localPath); err != nil {
+		return goof.WithFieldsE(fields, "error accessing nfs localPath", err)
+	} else if !fi.IsDir() {
+		return goof.WithFields(fields, "nfs localPath is not a directory")
+	}
+
+	ctx.WithFields(fields).Info("storage driver initialized")
+	return nil
+}
+
+// NextDeviceInfo returns the information about the driver's next available
+// device workflow.
+func (d *driver) NextDeviceInfo(
+	ctx types.Context) (*types.NextDeviceInfo, error) {
+	return nfsUtils.NextDeviceInfo, nil
+}
+
+// Type returns the type of storage the driver provides.
+func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
+	return types.NAS, nil
+}
+
+// Capabilities returns the features supported by the driver.
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsMultiAttach: true,
+	}, nil
+}
+
+// Ping verifies the server's local mount of the export is accessible.
+func (d *driver) Ping(ctx types.Context) error {
+	if _, err := os.Stat(d.localPath); err != nil {
+		return goof.WithFieldE(
+			nfs.LocalPath, d.localPath, "error accessing nfs localPath", err)
+	}
+	return nil
+}
+
+// InstanceInspect returns an instance.
+func (d *driver) InstanceInspect(
+	ctx types.Context,
+	opts types.Store) (*types.Instance, error) {
+
+	iid := context.MustInstanceID(ctx)
+	return &types.Instance{
+		Name:         iid.ID,
+		InstanceID:   iid,
+		ProviderName: iid.Driver,
+	}, nil
+}
+
+// Volumes returns all volumes or a filtered list of volumes.
+func (d *driver) Volumes(
+	ctx types.Context,
+	opts *types.VolumesOpts) ([]*types.Volume, error) {
+
+	fis, err := ioutil.ReadDir(d.localPath)
+	if err != nil {
+		return nil, goof.WithError("error listing nfs volumes", err)
+	}
+
+	var vols []*types.Volume
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			continue
+		}
+		vols = append(vols, d.toTypeVolume(ctx, fi.Name(), opts.Attachments))
+	}
+	return vols, nil
+}
+
+// VolumeInspect inspects a single volume.
+func (d *driver) VolumeInspect(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeInspectOpts) (*types.Volume, error) {
+
+	return d.getVolume(ctx, volumeID, opts.Attachments)
+}
+
+// VolumeCreate creates a new volume.
+func (d *driver) VolumeCreate(ctx types.Context, volumeName string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	if opts.Encrypted != nil && *opts.Encrypted {
+		return nil, apiUtils.NewUnsupportedErr(d.Name(), "encryption")
+	}
+
+	if !isFileName(volumeName) {
+		return nil, goof.WithField(
+			"volumeName", volumeName, "invalid volume name")
+	}
+
+	volPath := d.volumePath(volumeName)
+	if _, err := os.Stat(volPath); err == nil {
+		return nil, apiUtils.NewAlreadyExistsErr(volumeName)
+	}
+
+	if err := os.Mkdir(volPath, 0755); err != nil {
+		return nil, goof.WithFieldE(
+			"volumeName", volumeName, "error creating nfs volume", err)
+	}
+
+	return d.toTypeVolume(ctx, volumeName, types.VolAttNone), nil
+}
+
+// VolumeCreateFromSnapshot creates a new volume from an existing snapshot.
+func (d *driver) VolumeCreateFromSnapshot(
+	ctx types.Context,
+	snapshotID, volumeName string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// VolumeCopy copies an existing volume.
+func (d *driver) VolumeCopy(
+	ctx types.Context,
+	volumeID, volumeName string,
+	opts types.Store) (*types.Volume, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "copy")
+}
+
+// VolumeSnapshot snapshots a volume.
+func (d *driver) VolumeSnapshot(
+	ctx types.Context,
+	volumeID, snapshotName string,
+	opts types.Store) (*types.Snapshot, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// VolumeRemove removes a volume.
+func (d *driver) VolumeRemove(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeRemoveOpts) error {
+
+	// getVolume rejects IDs that are not a volume's directory, such as ".."
+	if _, err := d.getVolume(ctx, volumeID, types.VolAttNone); err != nil {
+		return err
+	}
+
+	volPath := d.volumePath(volumeID)
+
+	var err error
+	if opts.Force {
+		err = os.RemoveAll(volPath)
+	} else {
+		err = os.Remove(volPath)
+	}
+	if err != nil {
+		return goof.WithFieldE(
+			"volumeID", volumeID, "error removing nfs volume", err)
+	}
+
+	return nil
+}
+
+// VolumeAttach attaches a volume and provides a token clients can use
+// to validate that device has appeared locally. An NFS volume requires no
+// server-side attachment; the client's storage executor mounts the
+// volume's device name, "host:/export/volumeID", as part of the mount
+// workflow.
+func (d *driver) VolumeAttach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeAttachOpts) (*types.Volume, string, error) {
+
+	vol, err := d.getVolume(ctx, volumeID, types.VolAttReq)
+	if err != nil {
+		return nil, "", err
+	}
+	return vol, "", nil
+}
+
+// VolumeDetach detaches a volume. The client's storage executor unmounts
+// the volume as part of the unmount workflow.
+func (d *driver) VolumeDetach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeDetachOpts) (*types.Volume, error) {
+
+	vol, err := d.getVolume(ctx, volumeID, types.VolAttReq)
+	if err != nil {
+		return nil, err
+	}
+	return vol, nil
+}
+
+// Snapshots returns all volumes or a filtered list of snapshots.
+func (d *driver) Snapshots(
+	ctx types.Context,
+	opts types.Store) ([]*types.Snapshot, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// SnapshotInspect inspects a single snapshot.
+func (d *driver) SnapshotInspect(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) (*types.Snapshot, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// SnapshotCopy copies an existing snapshot.
+func (d *driver) SnapshotCopy(
+	ctx types.Context,
+	snapshotID, snapshotName, destinationID string,
+	opts types.Store) (*types.Snapshot, error) {
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// SnapshotRemove removes a snapshot.
+func (d *driver) SnapshotRemove(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) error {
+	return apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
+}
+
+// isFileName returns a flag indicating whether or not the name is a single,
+// regular path element that may be used as the name of a volume's directory.
+func isFileName(name string) bool {
+	return name != "" && name != "." && name != ".." &&
+		!strings.ContainsAny(name, `/\`)
+}
+
+func (d *driver) volumePath(volumeID string) string {
+	return path.Join(d.localPath, volumeID)
+}
+
+func (d *driver) toTypeVolume(
+	ctx types.Context,
+	volumeID string,
+	attachments types.VolumeAttachmentsTypes) *types.Volume {
+
+	vol := &types.Volume{
+		Name:   volumeID,
+		ID:     volumeID,
+		Type:   nfs.Name,
+		Status: types.VolumeStatusAvailable,
+	}
+
+	iid, iidOK := context.InstanceID(ctx)
+	if iidOK && attachments.Requested() {
+		devName := nfsUtils.DeviceName(d.host, d.export, volumeID)
+		vatt := &types.VolumeAttachment{
+			VolumeID:   volumeID,
+			DeviceName: devName,
+			InstanceID: iid,
+		}
+		if attachments.Devices() {
+			if ld, ldOK := context.LocalDevices(ctx); ldOK {
+				if mp, mpOK := ld.DeviceMap[devName]; mpOK {
+					vatt.MountPoint = mp
+					vol.Status = types.VolumeStatusInUse
+				}
+			}
+		}
+		vol.Attachments = []*types.VolumeAttachment{vatt}
+	}
+
+	return vol
+}
+
+func (d *driver) getVolume(
+	ctx types.Context,
+	volumeID string,
+	attachments types.VolumeAttachmentsTypes) (*types.Volume, error) {
+
+	if !isFileName(volumeID) {
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
+	}
+
+	fi, err := os.Stat(d.volumePath(volumeID))
+	if err != nil {
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, err)
+	}
+	if !fi.IsDir() {
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
+	}
+	return d.toTypeVolume(ctx, volumeID, attachments), nil
+}
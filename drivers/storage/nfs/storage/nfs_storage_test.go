@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestVolumeNamesArePathElements(t *testing.T) {
+	root, err := ioutil.TempDir("", "nfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	d := &driver{localPath: path.Join(root, "export")}
+	os.MkdirAll(path.Join(d.localPath, "vol-000"), 0755)
+	os.MkdirAll(path.Join(root, "other"), 0755)
+	ctx := context.Background()
+
+	for _, name := range []string{"", ".", "..", "/", "a/b", `a\b`} {
+		_, err := d.VolumeCreate(ctx, name, &types.VolumeCreateOpts{})
+		assert.Error(t, err, name)
+
+		_, err = d.VolumeInspect(ctx, name, &types.VolumeInspectOpts{})
+		assert.IsType(t, &types.ErrVolumeNotFound{}, err, name)
+
+		err = d.VolumeRemove(ctx, name, &types.VolumeRemoveOpts{Force: true})
+		assert.IsType(t, &types.ErrVolumeNotFound{}, err, name)
+	}
+
+	// nothing was created or removed
+	_, err = os.Stat(path.Join(root, "other"))
+	assert.NoError(t, err)
+	_, err = os.Stat(path.Join(root, "a"))
+	assert.True(t, os.IsNotExist(err))
+	fis, _ := ioutil.ReadDir(d.localPath)
+	if assert.Len(t, fis, 1) {
+		assert.Equal(t, "vol-000", fis[0].Name())
+	}
+
+	vol, err := d.VolumeCreate(ctx, "vol-001", &types.VolumeCreateOpts{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "vol-001", vol.ID)
+	}
+	assert.NoError(t, d.VolumeRemove(
+		ctx, "vol-001", &types.VolumeRemoveOpts{}))
+}
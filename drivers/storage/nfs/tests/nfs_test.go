@@ -0,0 +1,15 @@
+package nfs
+
+import (
+	"testing"
+
+	apitests "github.com/codedellemc/libstorage/api/tests"
+
+	// load the driver packages
+	"github.com/codedellemc/libstorage/drivers/storage/nfs"
+	_ "github.com/codedellemc/libstorage/drivers/storage/nfs/storage"
+)
+
+func TestSuite(t *testing.T) {
+	apitests.RunSuite(t, nfs.Name)
+}
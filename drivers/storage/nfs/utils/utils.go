@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"os"
+	"path"
+
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/drivers/storage/nfs"
+)
+
+// InstanceID returns the instance ID for the local host.
+func InstanceID(
+	ctx types.Context, config gofig.Config) (*types.InstanceID, error) {
+
+	var hostName string
+	if config != nil {
+		hostName = config.GetString(nfs.ConfigNFSHostName)
+	}
+	if hostName == "" {
+		hostName, _ = os.Hostname()
+	}
+	return &types.InstanceID{ID: hostName, Driver: nfs.Name}, nil
+}
+
+// DeviceName returns the NFS source, "host:/export/volumeID", for a volume.
+func DeviceName(host, export, volumeID string) string {
+	return host + ":" + path.Join(export, volumeID)
+}
+
+// NextDeviceInfo is the NextDeviceInfo object for NFS. NFS exports are not
+// block devices, so the next device workflow is ignored.
+var NextDeviceInfo = &types.NextDeviceInfo{
+	Prefix:  "",
+	Pattern: "",
+	Ignore:  true,
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"regexp"
+	"strings"
 
 	gofig "github.com/akutz/gofig/types"
 	"github.com/akutz/goof"
@@ -22,6 +23,8 @@ const (
 )
 
 var (
+	validNameRE = regexp.MustCompile(`^` + validNameRX + `$`)
+
 	featureLayering   = "layering"
 	defaultObjectSize = "4M"
 )
@@ -53,6 +56,13 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Block, nil
 }
 
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsSnapshots: true,
+	}, nil
+}
+
 func (d *driver) NextDeviceInfo(
 	ctx types.Context) (*types.NextDeviceInfo, error) {
 	return nil, nil
@@ -115,7 +125,7 @@ func (d *driver) VolumeInspect(
 
 	// no volume returned
 	if info == nil {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
 	/* GetRBDInfo returns more details about an image than what we get back
@@ -174,7 +184,7 @@ func (d *driver) VolumeCreate(ctx types.Context, volumeName string,
 
 	// volume already exists
 	if info != nil {
-		return nil, goof.New("Volume already exists")
+		return nil, apiUtils.NewAlreadyExistsErr(volumeName)
 	}
 
 	//TODO: config options for order and features?
@@ -217,7 +227,53 @@ func (d *driver) VolumeCreateFromSnapshot(
 	ctx types.Context,
 	snapshotID, volumeName string,
 	opts *types.VolumeCreateOpts) (*types.Volume, error) {
-	return nil, types.ErrNotImplemented
+
+	fields := map[string]interface{}{
+		"driverName": d.Name(),
+		"snapshotID": snapshotID,
+		"volumeName": volumeName,
+	}
+
+	ctx.WithFields(fields).Debug("creating volume from snapshot")
+
+	snap, err := d.SnapshotInspect(ctx, snapshotID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, imageName, snapName, err := d.parseSnapshotID(&snap.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	destPool, destImageName, err := d.parseVolumeID(&volumeName)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := utils.GetRBDInfo(ctx, destPool, destImageName)
+	if err != nil {
+		return nil, err
+	}
+
+	// volume already exists
+	if info != nil {
+		return nil, apiUtils.NewAlreadyExistsErr(volumeName)
+	}
+
+	err = utils.RBDClone(
+		ctx, pool, imageName, snapName, destPool, destImageName)
+	if err != nil {
+		return nil, goof.WithFieldsE(fields,
+			"Failed to create volume from snapshot", err)
+	}
+
+	volumeID := utils.GetVolumeID(destPool, destImageName)
+	return d.VolumeInspect(ctx, *volumeID,
+		&types.VolumeInspectOpts{
+			Attachments: types.VolAttNone,
+		},
+	)
 }
 
 func (d *driver) VolumeCopy(
@@ -231,7 +287,49 @@ func (d *driver) VolumeSnapshot(
 	ctx types.Context,
 	volumeID, snapshotName string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, types.ErrNotImplemented
+
+	fields := map[string]interface{}{
+		"driverName":   d.Name(),
+		"volumeID":     volumeID,
+		"snapshotName": snapshotName,
+	}
+
+	ctx.WithFields(fields).Debug("creating snapshot")
+
+	pool, imageName, err := d.parseVolumeID(&volumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !validNameRE.MatchString(snapshotName) {
+		return nil, goof.New(
+			"Invalid character(s) found in snapshot name")
+	}
+
+	snaps, err := utils.GetRBDSnaps(ctx, pool, imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	// no volume returned
+	if snaps == nil {
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
+	}
+
+	for _, snap := range snaps {
+		if snap.Name == snapshotName {
+			return nil, apiUtils.NewAlreadyExistsErr(snapshotName)
+		}
+	}
+
+	err = utils.RBDSnapCreate(ctx, pool, imageName, &snapshotName)
+	if err != nil {
+		return nil, goof.WithFieldsE(fields,
+			"Failed to create snapshot", err)
+	}
+
+	snapshotID := utils.GetSnapshotID(pool, imageName, &snapshotName)
+	return d.SnapshotInspect(ctx, *snapshotID, opts)
 }
 
 func (d *driver) VolumeRemove(
@@ -282,7 +380,7 @@ func (d *driver) VolumeAttach(
 			Attachments: types.VolAttReq,
 		})
 	if err != nil {
-		if _, ok := err.(*types.ErrNotFound); ok {
+		if apiUtils.IsNotFoundErr(err) {
 			return nil, "", err
 		}
 		return nil, "", goof.WithError("error getting volume", err)
@@ -357,28 +455,95 @@ func (d *driver) VolumeDetachAll(
 func (d *driver) Snapshots(
 	ctx types.Context,
 	opts types.Store) ([]*types.Snapshot, error) {
-	return nil, types.ErrNotImplemented
+
+	// Get all snapshots of all images in all pools
+	pools, err := utils.GetRadosPools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*types.Snapshot
+
+	for _, pool := range pools {
+		images, err := utils.GetRBDImages(ctx, pool)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, image := range images {
+			snaps, err := utils.GetRBDSnaps(ctx, &image.Pool, &image.Name)
+			if err != nil {
+				return nil, err
+			}
+			for _, snap := range snaps {
+				snapshots = append(snapshots, toTypeSnapshot(snap))
+			}
+		}
+	}
+
+	return snapshots, nil
 }
 
 func (d *driver) SnapshotInspect(
 	ctx types.Context,
 	snapshotID string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, types.ErrNotImplemented
+
+	pool, imageName, snapName, err := d.parseSnapshotID(&snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	snaps, err := utils.GetRBDSnaps(ctx, pool, imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snap := range snaps {
+		if snap.Name == *snapName {
+			return toTypeSnapshot(snap), nil
+		}
+	}
+
+	return nil, apiUtils.NewSnapshotNotFoundErr(snapshotID, nil)
 }
 
 func (d *driver) SnapshotCopy(
 	ctx types.Context,
 	snapshotID, snapshotName, destinationID string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, types.ErrNotImplemented
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshot copy")
 }
 
 func (d *driver) SnapshotRemove(
 	ctx types.Context,
 	snapshotID string,
 	opts types.Store) error {
-	return types.ErrNotImplemented
+
+	fields := map[string]interface{}{
+		"driverName": d.Name(),
+		"snapshotID": snapshotID,
+	}
+
+	ctx.WithFields(fields).Debug("deleting snapshot")
+
+	snap, err := d.SnapshotInspect(ctx, snapshotID, opts)
+	if err != nil {
+		return err
+	}
+
+	pool, imageName, snapName, err := d.parseSnapshotID(&snap.ID)
+	if err != nil {
+		return err
+	}
+
+	err = utils.RBDSnapRemove(ctx, pool, imageName, snapName)
+	if err != nil {
+		return goof.WithError("Error while deleting RBD snapshot", err)
+	}
+	ctx.WithFields(fields).Debug("removed snapshot")
+
+	return nil
 }
 
 func (d *driver) defaultPool() string {
@@ -424,6 +589,7 @@ func (d *driver) toTypeVolumes(
 				attachment := &types.VolumeAttachment{
 					VolumeID:   *rbdID,
 					InstanceID: context.MustInstanceID(ctx),
+					Status:     types.AttachmentStatusAttached,
 				}
 				if getAttachments.Devices() {
 					ld, ok := context.LocalDevices(ctx)
@@ -458,6 +624,17 @@ func (d *driver) toTypeVolumes(
 	return lsVolumes, nil
 }
 
+func toTypeSnapshot(snap *utils.RBDSnap) *types.Snapshot {
+	return &types.Snapshot{
+		Name:       snap.Name,
+		ID:         *utils.GetSnapshotID(&snap.Pool, &snap.Image, &snap.Name),
+		VolumeID:   *utils.GetVolumeID(&snap.Pool, &snap.Image),
+		VolumeSize: int64(snap.Size / bytesPerGiB),
+		Status:     types.SnapshotStatusCompleted,
+		Progress:   100,
+	}
+}
+
 func (d *driver) parseVolumeID(name *string) (*string, *string, error) {
 
 	// Look for <pool>.<name>
@@ -479,3 +656,27 @@ func (d *driver) parseVolumeID(name *string) (*string, *string, error) {
 	pool := d.defaultPool()
 	return &pool, name, nil
 }
+
+// parseSnapshotID parses a snapshot ID formatted as <pool>.<image>@<snap>,
+// or <image>@<snap> for images in the default pool
+func (d *driver) parseSnapshotID(
+	id *string) (*string, *string, *string, error) {
+
+	i := strings.LastIndex(*id, "@")
+	if i < 0 {
+		return nil, nil, nil, apiUtils.NewSnapshotNotFoundErr(*id, nil)
+	}
+
+	volumeID, snapName := (*id)[:i], (*id)[i+1:]
+	if !validNameRE.MatchString(snapName) {
+		return nil, nil, nil, goof.New(
+			"Invalid character(s) found in snapshot name")
+	}
+
+	pool, imageName, err := d.parseVolumeID(&volumeID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return pool, imageName, &snapName, nil
+}
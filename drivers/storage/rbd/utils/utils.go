@@ -55,6 +55,15 @@ type RBDInfo struct {
 	Pool            string
 }
 
+//RBDSnap holds details about a snapshot of an RBD image
+type RBDSnap struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Size  int64  `json:"size"`
+	Pool  string
+	Image string
+}
+
 //GetRadosPools returns a slice containing all the pool names
 func GetRadosPools(ctx types.Context) ([]*string, error) {
 
@@ -141,6 +150,13 @@ func GetVolumeID(pool, image *string) *string {
 	return &volumeID
 }
 
+//GetSnapshotID returns an RBD snapshot formatted as <pool>.<image>@<snap>
+func GetSnapshotID(pool, image, snap *string) *string {
+
+	snapshotID := fmt.Sprintf("%s@%s", *GetVolumeID(pool, image), *snap)
+	return &snapshotID
+}
+
 //GetMappedRBDs returns a map of RBDs currently mapped to the *local* host
 func GetMappedRBDs(ctx types.Context) (map[string]string, error) {
 
@@ -239,6 +255,111 @@ func RBDUnmap(ctx types.Context, device *string) error {
 	return nil
 }
 
+//GetRBDSnaps returns a slice of the snapshots of an RBD image. A nil slice
+//is returned if the image does not exist.
+func GetRBDSnaps(
+	ctx types.Context,
+	pool, image *string) ([]*RBDSnap, error) {
+
+	ignoreCode := 2
+
+	cmd := exec.Command(
+		rbdCmd, "snap", "ls", poolOpt, *pool, *image, formatOpt, jsonArg)
+	out, status, err := RunCommand(ctx, cmd, ignoreCode)
+	if err != nil {
+		if status == ignoreCode {
+			// image does not exist
+			return nil, nil
+		}
+		return nil, goof.WithError("unable to get rbd snapshots", err)
+	}
+
+	snaps := []*RBDSnap{}
+
+	err = json.Unmarshal(out, &snaps)
+	if err != nil {
+		return nil, goof.WithError(
+			"unable to parse rbd snap ls", err)
+	}
+
+	for _, snap := range snaps {
+		snap.Pool = *pool
+		snap.Image = *image
+	}
+
+	return snaps, nil
+}
+
+//RBDSnapCreate creates a snapshot of an RBD image
+func RBDSnapCreate(
+	ctx types.Context,
+	pool, image, snap *string) error {
+
+	cmd := exec.Command(
+		rbdCmd, "snap", "create", poolOpt, *pool, "--snap", *snap, *image)
+	_, _, err := RunCommand(ctx, cmd)
+	if err != nil {
+		return goof.WithError("unable to create rbd snapshot", err)
+	}
+	return nil
+}
+
+//RBDSnapRemove deletes a snapshot of an RBD image
+func RBDSnapRemove(
+	ctx types.Context,
+	pool, image, snap *string) error {
+
+	cmd := exec.Command(
+		rbdCmd, "snap", "rm", poolOpt, *pool, "--snap", *snap,
+		"--no-progress", *image)
+	_, _, err := RunCommand(ctx, cmd)
+	if err != nil {
+		return goof.WithError("unable to delete rbd snapshot", err)
+	}
+	return nil
+}
+
+//RBDClone creates a new RBD image from a snapshot. The snapshot is
+//protected for the clone and the clone is then flattened so that it no
+//longer depends on the snapshot.
+func RBDClone(
+	ctx types.Context,
+	pool, image, snap, destPool, destImage *string) error {
+
+	// EBUSY is returned when the snapshot is already (un)protected, or
+	// when the snapshot cannot be unprotected due to other clones
+	ignoreCode := 16
+
+	cmd := exec.Command(
+		rbdCmd, "snap", "protect", poolOpt, *pool, "--snap", *snap, *image)
+	if _, status, err := RunCommand(
+		ctx, cmd, ignoreCode); err != nil && status != ignoreCode {
+		return goof.WithError("unable to protect rbd snapshot", err)
+	}
+
+	cmd = exec.Command(
+		rbdCmd, "clone", poolOpt, *pool, "--image", *image, "--snap", *snap,
+		"--dest-pool", *destPool, "--dest", *destImage)
+	if _, _, err := RunCommand(ctx, cmd); err != nil {
+		return goof.WithError("unable to clone rbd snapshot", err)
+	}
+
+	cmd = exec.Command(
+		rbdCmd, "flatten", poolOpt, *destPool, "--no-progress", *destImage)
+	if _, _, err := RunCommand(ctx, cmd); err != nil {
+		return goof.WithError("unable to flatten rbd clone", err)
+	}
+
+	cmd = exec.Command(
+		rbdCmd, "snap", "unprotect", poolOpt, *pool, "--snap", *snap, *image)
+	if _, status, err := RunCommand(
+		ctx, cmd, ignoreCode); err != nil && status != ignoreCode {
+		return goof.WithError("unable to unprotect rbd snapshot", err)
+	}
+
+	return nil
+}
+
 //GetRBDStatus returns a map of RBD status info
 func GetRBDStatus(
 	ctx types.Context,
@@ -3,8 +3,8 @@ package executor
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path"
+	"strings"
 
 	log "github.com/Sirupsen/logrus"
 	gofig "github.com/akutz/gofig/types"
@@ -13,6 +13,7 @@ import (
 
 	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/exec"
 
 	"github.com/codedellemc/libstorage/drivers/storage/s3fs"
 	"github.com/codedellemc/libstorage/drivers/storage/s3fs/utils"
@@ -20,10 +21,10 @@ import (
 
 // driver is the storage executor for the s3fs storage driver.
 type driver struct {
-	config gofig.Config
-	cmd    string
-	opts   []string
-	szOpts string
+	config    gofig.Config
+	cmd       string
+	mountOpts *utils.MountOptions
+	executor  exec.Executor
 }
 
 func init() {
@@ -31,22 +32,48 @@ func init() {
 }
 
 func newDriver() types.StorageExecutor {
-	return &driver{}
+	return &driver{executor: exec.RealExecutor{}}
 }
 
 func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	d.config = config
 
+	cfg, err := utils.LoadConfig(config)
+	if err != nil {
+		return err
+	}
+
 	fields := log.Fields{"driver": s3fs.Name}
-	d.cmd = d.config.GetString(s3fs.ConfigS3FSCmd)
+	d.cmd = cfg.Cmd
 	fields["cmd"] = d.cmd
 
-	if v := d.config.GetStringSlice(s3fs.ConfigS3FSOptions); len(v) > 0 {
-		d.opts = v
-		fields["opts"] = d.opts
+	d.mountOpts = cfg.Mount
+	fields["opts"] = d.mountOpts.Options
+	fields["useCache"] = d.mountOpts.UseCache
+	fields["ensureDiskFree"] = d.mountOpts.EnsureDiskFree
+	fields["parallelCount"] = d.mountOpts.ParallelCount
+	fields["multipartSize"] = d.mountOpts.MultipartSize
+	fields["endpoint"] = d.mountOpts.Endpoint
+
+	// static keys are supplied to the s3fs command with environment
+	// variables. otherwise the command obtains temporary credentials from
+	// the instance's IAM role and refreshes them before they expire, so
+	// long-lived mounts survive the rotation of the role's credentials.
+	if d.getAccessKey() != "" && d.getSecretKey() != "" {
+		fields["credentials"] = "static"
+		d.mountOpts.IAMRole = ""
 	} else {
-		d.szOpts = d.config.GetString(s3fs.ConfigS3FSOptions)
-		fields["opts"] = d.szOpts
+		if d.mountOpts.IAMRole == "" {
+			role, err := utils.IAMRole(ctx)
+			if err != nil {
+				ctx.WithError(err).Warn("error getting iam role")
+			}
+			d.mountOpts.IAMRole = role
+		}
+		if d.mountOpts.IAMRole != "" {
+			fields["credentials"] = "iamRole"
+			fields["iamRole"] = d.mountOpts.IAMRole
+		}
 	}
 
 	ctx.WithFields(fields).Debug("storage executor initialized")
@@ -143,24 +170,21 @@ func (d *driver) s3fsMount(
 	bucket, mountPoint string,
 	opts *types.DeviceMountOpts) error {
 
-	args := []string{bucket, mountPoint}
-	if len(d.opts) > 0 {
-		for _, o := range d.opts {
-			args = append(args, fmt.Sprintf("-o%s", o))
-		}
-	} else if d.szOpts != "" {
-		args = append(args, d.szOpts)
-	}
-
 	fields := map[string]interface{}{
 		"bucket":           bucket,
 		"mountPoint":       mountPoint,
 		"cmd":              d.cmd,
-		"args":             args,
 		"isAWSAuthEnvVars": false,
 	}
 
-	cmd := exec.Command(d.cmd, args...)
+	optArgs, err := d.mountOpts.Args()
+	if err != nil {
+		return goof.WithFieldsE(fields, "invalid s3fs mount options", err)
+	}
+	args := append([]string{bucket, mountPoint}, optArgs...)
+	fields["args"] = args
+
+	cmd := &exec.Cmd{Name: d.cmd, Args: args}
 	if ak := d.getAccessKey(); ak != "" {
 		if sk := d.getSecretKey(); sk != "" {
 			cmd.Env = os.Environ()
@@ -172,15 +196,36 @@ func (d *driver) s3fsMount(
 
 	ctx.WithFields(fields).Debug("attempting s3fs mount")
 
-	out, err := cmd.CombinedOutput()
+	res, err := d.executor.Run(ctx, cmd)
 	if err != nil {
+		var out []byte
+		if res != nil {
+			out = res.Output()
+			fields["exitCode"] = res.ExitCode
+		}
 		fields["output"] = string(out)
-		return goof.WithFieldsE(fields, "error mounting s3fs bucket", err)
+		msg := "error mounting s3fs bucket"
+		if reason := mountErrReason(out); reason != "" {
+			msg = fmt.Sprintf("%s: %s", msg, reason)
+		}
+		return goof.WithFieldsE(fields, msg, err)
 	}
 
 	return nil
 }
 
+// mountErrReason returns the reasons the s3fs command reported for failing
+// to mount a bucket. The command prefixes the reasons with "s3fs:".
+func mountErrReason(out []byte) string {
+	var reasons []string
+	for _, l := range strings.Split(string(out), "\n") {
+		if l = strings.TrimSpace(l); strings.HasPrefix(l, "s3fs:") {
+			reasons = append(reasons, strings.TrimSpace(l[5:]))
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
 func (d *driver) findMountPoint(
 	ctx types.Context,
 	bucket string) (string, bool) {
@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"testing"
+
+	gofigCore "github.com/akutz/gofig"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/exec"
+
+	"github.com/codedellemc/libstorage/drivers/storage/s3fs"
+	"github.com/codedellemc/libstorage/drivers/storage/s3fs/utils"
+)
+
+func newTestDriver(e *exec.FakeExecutor) *driver {
+	config := gofigCore.New()
+	config.Set(s3fs.ConfigS3FSCmd, "/usr/bin/s3fs")
+	config.Set(s3fs.ConfigS3FSOptions, "allow_other,umask=0022")
+	config.Set(s3fs.ConfigS3FSUseCache, "/tmp/s3fs")
+	config.Set(s3fs.ConfigS3FSAccessKey, "AKIAEXAMPLE")
+	config.Set(s3fs.ConfigS3FSSecretKey, "secretexample")
+	return &driver{
+		config:    config,
+		cmd:       config.GetString(s3fs.ConfigS3FSCmd),
+		mountOpts: utils.NewMountOptions(config),
+		executor:  e,
+	}
+}
+
+func TestS3FSMountArgs(t *testing.T) {
+	e := &exec.FakeExecutor{}
+	d := newTestDriver(e)
+
+	err := d.s3fsMount(
+		context.Background(), "bucket", "/mnt/bucket",
+		&types.DeviceMountOpts{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, [][]string{{
+		"/usr/bin/s3fs",
+		"bucket",
+		"/mnt/bucket",
+		"-oallow_other",
+		"-oumask=0022",
+		"-ouse_cache=/tmp/s3fs",
+	}}, e.Argvs())
+	assert.Contains(t, e.Cmds[0].Env, "AWSACCESSKEYID=AKIAEXAMPLE")
+	assert.Contains(t, e.Cmds[0].Env, "AWSSECRETACCESSKEY=secretexample")
+}
+
+func TestS3FSMountErr(t *testing.T) {
+	e := &exec.FakeExecutor{
+		RunFunc: func(ctx types.Context, cmd *exec.Cmd) (*exec.Result, error) {
+			return &exec.Result{
+				Stderr:   []byte("s3fs: bucket not found\n"),
+				ExitCode: 1,
+			}, assert.AnError
+		},
+	}
+	d := newTestDriver(e)
+
+	err := d.s3fsMount(
+		context.Background(), "bucket", "/mnt/bucket",
+		&types.DeviceMountOpts{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "bucket not found")
+	}
+}
@@ -8,10 +8,19 @@ import (
 )
 
 const (
-	defaultRegion   = "us-east-1"
 	defaultEndpoint = "s3.amazonaws.com"
 )
 
+const (
+	// MinMultipartSize is the minimum size, in MB, of the parts of a
+	// multipart upload.
+	MinMultipartSize = 5
+
+	// MaxMultipartSize is the maximum size, in MB, of the parts of a
+	// multipart upload.
+	MaxMultipartSize = 5 * 1024
+)
+
 const (
 	// Name is the provider's name.
 	Name = "s3fs"
@@ -22,12 +31,33 @@ const (
 	// DefaultMaxRetries is the max number of times to retry failed operations
 	DefaultMaxRetries = 10
 
+	// DefaultRegion is the AWS region used if none is configured.
+	DefaultRegion = "us-east-1"
+
+	// DefaultCmd is the s3fs command used if none is configured.
+	DefaultCmd = "s3fs"
+
 	// Cmd is a key constant.
 	Cmd = "cmd"
 
 	// Options is a key constant.
 	Options = "options"
 
+	// UseCache is a key constant.
+	UseCache = "useCache"
+
+	// EnsureDiskFree is a key constant.
+	EnsureDiskFree = "ensureDiskFree"
+
+	// ParallelCount is a key constant.
+	ParallelCount = "parallelCount"
+
+	// IAMRole is a key constant.
+	IAMRole = "iamRole"
+
+	// MultipartSize is a key constant.
+	MultipartSize = "multipartSize"
+
 	// HostName is a key constant.
 	HostName = "hostName"
 
@@ -50,6 +80,12 @@ const (
 
 	// Tag is a key constant.
 	Tag = "tag"
+
+	// Endpoint is a key constant.
+	Endpoint = "endpoint"
+
+	// Bucket is a key constant.
+	Bucket = "bucket"
 )
 
 const (
@@ -74,6 +110,21 @@ const (
 	// ConfigS3FSOptions is a config key
 	ConfigS3FSOptions = ConfigS3FS + "." + Options
 
+	// ConfigS3FSUseCache is a config key.
+	ConfigS3FSUseCache = ConfigS3FS + "." + UseCache
+
+	// ConfigS3FSEnsureDiskFree is a config key.
+	ConfigS3FSEnsureDiskFree = ConfigS3FS + "." + EnsureDiskFree
+
+	// ConfigS3FSParallelCount is a config key.
+	ConfigS3FSParallelCount = ConfigS3FS + "." + ParallelCount
+
+	// ConfigS3FSIAMRole is a config key.
+	ConfigS3FSIAMRole = ConfigS3FS + "." + IAMRole
+
+	// ConfigS3FSMultipartSize is a config key.
+	ConfigS3FSMultipartSize = ConfigS3FS + "." + MultipartSize
+
 	// ConfigS3FSHostName is a config key
 	ConfigS3FSHostName = ConfigS3FS + "." + HostName
 
@@ -82,6 +133,12 @@ const (
 
 	// ConfigS3FSDisablePathStyle is a config key.
 	ConfigS3FSDisablePathStyle = ConfigS3FS + "." + DisablePathStyle
+
+	// ConfigS3FSBucket is a config key.
+	ConfigS3FSBucket = ConfigS3FS + "." + Bucket
+
+	// ConfigS3FSEndpoint is a config key.
+	ConfigS3FSEndpoint = ConfigS3FS + "." + Endpoint
 )
 
 func init() {
@@ -91,12 +148,12 @@ func init() {
 	r.Key(gofig.String, "", "", "AWS secret key", ConfigS3FSSecretKey)
 	r.Key(gofig.String,
 		"",
-		defaultRegion,
+		DefaultRegion,
 		"AWS region",
 		ConfigS3FSRegion)
 	r.Key(gofig.String,
 		"",
-		"s3fs",
+		DefaultCmd,
 		`The absolute path to the "s3fs" binary.`,
 		ConfigS3FSCmd)
 	r.Key(gofig.String,
@@ -104,6 +161,31 @@ func init() {
 		"",
 		`The options to use with the "s3fs" command.`,
 		ConfigS3FSOptions)
+	r.Key(gofig.String,
+		"",
+		"",
+		`The directory in which the "s3fs" command caches objects.`,
+		ConfigS3FSUseCache)
+	r.Key(gofig.Int,
+		"",
+		0,
+		`The MB of disk space the "s3fs" command keeps free.`,
+		ConfigS3FSEnsureDiskFree)
+	r.Key(gofig.Int,
+		"",
+		0,
+		`The number of parallel requests the "s3fs" command makes.`,
+		ConfigS3FSParallelCount)
+	r.Key(gofig.Int,
+		"",
+		0,
+		`The size, in MB, of the parts the "s3fs" command uploads.`,
+		ConfigS3FSMultipartSize)
+	r.Key(gofig.String,
+		"",
+		"",
+		`The IAM role from which the "s3fs" command obtains credentials.`,
+		ConfigS3FSIAMRole)
 	r.Key(gofig.String,
 		"",
 		hostName,
@@ -124,5 +206,15 @@ func init() {
 		false,
 		"A flag that disables the use of S3's path style for bucket endpoints",
 		ConfigS3FSDisablePathStyle)
+	r.Key(gofig.String,
+		"",
+		"",
+		"The bucket in which volumes are prefixes instead of buckets",
+		ConfigS3FSBucket)
+	r.Key(gofig.String,
+		"",
+		"",
+		"The URL of an S3-compatible endpoint to use instead of AWS",
+		ConfigS3FSEndpoint)
 	gofigCore.Register(r)
 }
@@ -31,6 +31,8 @@ type driver struct {
 	secretKey        string
 	maxRetries       int
 	disablePathStyle bool
+	bucket           string
+	endpoint         string
 	svcs             map[string]*awss3.S3
 	svcsRWL          *sync.RWMutex
 }
@@ -53,28 +55,32 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	d.svcs = map[string]*awss3.S3{}
 	d.svcsRWL = &sync.RWMutex{}
 
-	fields := log.Fields{}
-
-	d.tag = d.config.GetString(s3fs.ConfigS3FSTag)
-	fields[s3fs.Tag] = d.tag
-
-	d.accessKey = d.config.GetString(s3fs.ConfigS3FSAccessKey)
-	fields[s3fs.AccessKey] = d.accessKey
-
-	d.secretKey = d.config.GetString(s3fs.ConfigS3FSSecretKey)
+	cfg, err := s3fsUtils.LoadConfig(config)
+	if err != nil {
+		return err
+	}
+	d.tag = cfg.Tag
+	d.accessKey = cfg.AccessKey
+	d.secretKey = cfg.SecretKey
+	d.region = cfg.Region
+	d.maxRetries = cfg.MaxRetries
+	d.disablePathStyle = cfg.DisablePathStyle
+	d.bucket = cfg.Bucket
+	d.endpoint = cfg.Endpoint
+
+	fields := log.Fields{
+		s3fs.Tag:              d.tag,
+		s3fs.AccessKey:        d.accessKey,
+		s3fs.Region:           d.region,
+		s3fs.MaxRetries:       d.maxRetries,
+		s3fs.DisablePathStyle: d.disablePathStyle,
+		s3fs.Bucket:           d.bucket,
+		s3fs.Endpoint:         d.endpoint,
+	}
 	if d.secretKey != "" {
 		fields[s3fs.SecretKey] = "******"
 	}
 
-	d.region = d.config.GetString(s3fs.ConfigS3FSRegion)
-	fields[s3fs.Region] = d.region
-
-	d.maxRetries = d.config.GetInt(s3fs.ConfigS3FSMaxRetries)
-	fields[s3fs.MaxRetries] = d.maxRetries
-
-	d.disablePathStyle = d.config.GetBool(s3fs.ConfigS3FSDisablePathStyle)
-	fields[s3fs.DisablePathStyle] = d.disablePathStyle
-
 	if _, err := d.getService(ctx, d.region); err != nil {
 		return err
 	}
@@ -83,6 +89,14 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	return nil
 }
 
+// Close releases the driver's s3 service connections.
+func (d *driver) Close() error {
+	d.svcsRWL.Lock()
+	defer d.svcsRWL.Unlock()
+	d.svcs = nil
+	return nil
+}
+
 func (d *driver) getService(
 	ctx types.Context,
 	region string) (*awss3.S3, error) {
@@ -105,6 +119,16 @@ func (d *driver) getService(
 	d.svcsRWL.Lock()
 	defer d.svcsRWL.Unlock()
 
+	if d.svcs == nil {
+		return nil, apiUtils.NewClosedErr(d.Name())
+	}
+
+	// another request may have created the connection while this one waited
+	// for the lock
+	if svc, ok := d.svcs[region]; ok {
+		return svc, nil
+	}
+
 	sess := session.New()
 
 	var (
@@ -144,6 +168,9 @@ func (d *driver) getService(
 		Logger:   awsLogger,
 		LogLevel: aws.LogLevel(awsLogLevel),
 	}
+	if d.endpoint != "" {
+		config.Endpoint = &d.endpoint
+	}
 
 	svc = awss3.New(sess, config)
 	ctx.WithField("region", region).Debug("s3 connection created")
@@ -198,6 +225,29 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Object, nil
 }
 
+// Capabilities returns the features supported by the driver.
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsMultiAttach: true,
+	}, nil
+}
+
+// Ping verifies the S3 service is reachable and the configured credentials
+// are able to access its buckets.
+func (d *driver) Ping(ctx types.Context) error {
+	svc, err := d.getService(ctx, "")
+	if err != nil {
+		return err
+	}
+	req, _ := svc.ListBucketsRequest(&awss3.ListBucketsInput{})
+	req.HTTPRequest.Cancel = ctx.Done()
+	if err := req.Send(); err != nil {
+		return goof.WithError("error listing s3 buckets", err)
+	}
+	return nil
+}
+
 // InstanceInspect returns an instance.
 func (d *driver) InstanceInspect(
 	ctx types.Context,
@@ -216,7 +266,15 @@ func (d *driver) Volumes(
 	ctx types.Context,
 	opts *types.VolumesOpts) ([]*types.Volume, error) {
 
-	vols, err := d.toTypeVolumes(ctx, opts.Attachments)
+	var (
+		vols []*types.Volume
+		err  error
+	)
+	if d.bucket != "" {
+		vols, err = d.toTypePrefixVolumes(ctx, opts.Attachments)
+	} else {
+		vols, err = d.toTypeVolumes(ctx, opts.Attachments)
+	}
 	if err != nil {
 		return nil, goof.WithError("error getting s3fs volumes", err)
 	}
@@ -240,14 +298,21 @@ func (d *driver) VolumeCreate(ctx types.Context, volumeName string,
 		return nil, types.ErrNotImplemented
 	}
 
+	if d.bucket != "" {
+		return d.createPrefix(ctx, volumeName)
+	}
+
 	var cbc *awss3.CreateBucketConfiguration
 	if d.region != "us-east-1" {
 		cbc = &awss3.CreateBucketConfiguration{LocationConstraint: &d.region}
 	}
 
-	svc, _ := d.getService(ctx, "")
+	svc, err := d.getService(ctx, "")
+	if err != nil {
+		return nil, err
+	}
 
-	_, err := svc.CreateBucket(
+	_, err = svc.CreateBucket(
 		&awss3.CreateBucketInput{
 			Bucket: &volumeName,
 			CreateBucketConfiguration: cbc,
@@ -268,8 +333,8 @@ func (d *driver) VolumeCreateFromSnapshot(
 	ctx types.Context,
 	snapshotID, volumeName string,
 	opts *types.VolumeCreateOpts) (*types.Volume, error) {
-	// TODO Snapshots are not implemented yet
-	return nil, types.ErrNotImplemented
+	return nil, apiUtils.NewUnsupportedErr(
+		d.Name(), "VolumeCreateFromSnapshot")
 }
 
 // VolumeCopy copies an existing volume.
@@ -277,8 +342,7 @@ func (d *driver) VolumeCopy(
 	ctx types.Context,
 	volumeID, volumeName string,
 	opts types.Store) (*types.Volume, error) {
-	// TODO Snapshots are not implemented yet
-	return nil, types.ErrNotImplemented
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "VolumeCopy")
 }
 
 // VolumeSnapshot snapshots a volume.
@@ -286,8 +350,7 @@ func (d *driver) VolumeSnapshot(
 	ctx types.Context,
 	volumeID, snapshotName string,
 	opts types.Store) (*types.Snapshot, error) {
-	// TODO Snapshots are not implemented yet
-	return nil, types.ErrNotImplemented
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "VolumeSnapshot")
 }
 
 // VolumeRemove removes a volume.
@@ -296,6 +359,10 @@ func (d *driver) VolumeRemove(
 	volumeID string,
 	opts *types.VolumeRemoveOpts) error {
 
+	if d.bucket != "" {
+		return d.removePrefix(ctx, volumeID, opts.Force)
+	}
+
 	var svc *awss3.S3
 
 	{
@@ -483,6 +550,10 @@ func (d *driver) VolumeDetach(
 	volumeID string,
 	opts *types.VolumeDetachOpts) (*types.Volume, error) {
 
+	if opts.Force {
+		return nil, apiUtils.NewUnsupportedErr(d.Name(), "force detach")
+	}
+
 	vol, err := d.getVolume(ctx, volumeID, types.VolAttReq)
 	if err != nil {
 		return nil, err
@@ -494,8 +565,7 @@ func (d *driver) VolumeDetach(
 func (d *driver) Snapshots(
 	ctx types.Context,
 	opts types.Store) ([]*types.Snapshot, error) {
-	// TODO Snapshots are not implemented yet
-	return nil, types.ErrNotImplemented
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "Snapshots")
 }
 
 // SnapshotInspect inspects a single snapshot.
@@ -503,8 +573,7 @@ func (d *driver) SnapshotInspect(
 	ctx types.Context,
 	snapshotID string,
 	opts types.Store) (*types.Snapshot, error) {
-	// TODO Snapshots are not implemented yet
-	return nil, types.ErrNotImplemented
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "SnapshotInspect")
 }
 
 // SnapshotCopy copies an existing snapshot.
@@ -512,8 +581,7 @@ func (d *driver) SnapshotCopy(
 	ctx types.Context,
 	snapshotID, snapshotName, destinationID string,
 	opts types.Store) (*types.Snapshot, error) {
-	// TODO Snapshots are not implemented yet
-	return nil, types.ErrNotImplemented
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "SnapshotCopy")
 }
 
 // SnapshotRemove removes a snapshot.
@@ -521,8 +589,7 @@ func (d *driver) SnapshotRemove(
 	ctx types.Context,
 	snapshotID string,
 	opts types.Store) error {
-	// TODO Snapshots are not implemented yet
-	return types.ErrNotImplemented
+	return apiUtils.NewUnsupportedErr(d.Name(), "SnapshotRemove")
 }
 
 var errGetLocDevs = goof.New("error getting local devices from context")
@@ -531,7 +598,10 @@ func (d *driver) toTypeVolumes(
 	ctx types.Context,
 	attachments types.VolumeAttachmentsTypes) ([]*types.Volume, error) {
 
-	svc, _ := d.getService(ctx, "")
+	svc, err := d.getService(ctx, "")
+	if err != nil {
+		return nil, err
+	}
 
 	res, err := svc.ListBuckets(&awss3.ListBucketsInput{})
 	if err != nil {
@@ -559,14 +629,15 @@ func (d *driver) toTypeVolume(
 
 	iid, iidOK := context.InstanceID(ctx)
 	if iidOK && attachments.Requested() {
+		devName := d.deviceName(bucket)
 		vatt := &types.VolumeAttachment{
 			VolumeID:   bucket,
-			DeviceName: bucket,
+			DeviceName: devName,
 			InstanceID: iid,
 		}
 		if attachments.Devices() {
 			if ld, ldOK := context.LocalDevices(ctx); ldOK {
-				if mp, mpOK := ld.DeviceMap[bucket]; mpOK {
+				if mp, mpOK := ld.DeviceMap[devName]; mpOK {
 					vatt.MountPoint = mp
 				}
 			}
@@ -582,10 +653,17 @@ func (d *driver) getVolume(
 	volumeID string,
 	attachments types.VolumeAttachmentsTypes) (*types.Volume, error) {
 
-	svc, _ := d.getService(ctx, "")
+	if d.bucket != "" {
+		return d.getPrefixVolume(ctx, volumeID, attachments)
+	}
+
+	svc, err := d.getService(ctx, "")
+	if err != nil {
+		return nil, err
+	}
 	req, _ := svc.HeadBucketRequest(&awss3.HeadBucketInput{Bucket: &volumeID})
 	if err := req.Send(); err != nil && req.HTTPResponse.StatusCode != 301 {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, err)
 	}
 	return d.toTypeVolume(ctx, volumeID, attachments), nil
 }
@@ -594,11 +672,14 @@ func (d *driver) getServiceForBucket(
 	ctx types.Context,
 	bucket string) (*awss3.S3, error) {
 
-	svc, _ := d.getService(ctx, "")
+	svc, err := d.getService(ctx, "")
+	if err != nil {
+		return nil, err
+	}
 	res, err := svc.GetBucketLocation(
 		&awss3.GetBucketLocationInput{Bucket: &bucket})
 	if err != nil {
-		return nil, apiUtils.NewNotFoundError(bucket)
+		return nil, apiUtils.NewVolumeNotFoundErr(bucket, err)
 	}
 	var region string
 	if res.LocationConstraint != nil {
@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/akutz/goof"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awss3 "github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/codedellemc/libstorage/api/types"
+	apiUtils "github.com/codedellemc/libstorage/api/utils"
+)
+
+// When the s3fs.bucket property is set, each volume is a prefix within the
+// configured bucket rather than a bucket of its own. A volume's prefix is
+// marked by an empty object whose key is the volume's name followed by a
+// slash, the same object s3fs creates for a directory. Clients mount only
+// the volume's prefix using the "bucket:/prefix" device name.
+
+// deviceName returns the name with which the executor mounts the volume.
+func (d *driver) deviceName(volumeID string) string {
+	if d.bucket == "" {
+		return volumeID
+	}
+	return d.bucket + ":/" + volumeID
+}
+
+func prefixKey(volumeID string) string {
+	return volumeID + "/"
+}
+
+func (d *driver) toTypePrefixVolumes(
+	ctx types.Context,
+	attachments types.VolumeAttachmentsTypes) ([]*types.Volume, error) {
+
+	svc, err := d.getServiceForBucket(ctx, d.bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		vols   []*types.Volume
+		marker *string
+	)
+	for {
+		res, err := svc.ListObjects(&awss3.ListObjectsInput{
+			Bucket:    &d.bucket,
+			Delimiter: aws.String("/"),
+			Marker:    marker,
+		})
+		if err != nil {
+			ctx.WithField("bucket", d.bucket).WithError(err).Error(
+				"error listing s3 prefixes")
+			return nil, goof.WithFieldE(
+				"bucket", d.bucket, "error listing s3 prefixes", err)
+		}
+		for _, p := range res.CommonPrefixes {
+			if p.Prefix == nil {
+				continue
+			}
+			vols = append(vols, d.toTypeVolume(
+				ctx, strings.TrimSuffix(*p.Prefix, "/"), attachments))
+		}
+		if res.IsTruncated == nil || !*res.IsTruncated ||
+			res.NextMarker == nil {
+			break
+		}
+		marker = res.NextMarker
+	}
+	return vols, nil
+}
+
+// prefixExists returns a flag indicating whether or not any object exists
+// beneath the volume's prefix.
+func (d *driver) prefixExists(
+	ctx types.Context,
+	svc *awss3.S3,
+	volumeID string) (bool, error) {
+
+	res, err := svc.ListObjects(&awss3.ListObjectsInput{
+		Bucket:  &d.bucket,
+		Prefix:  aws.String(prefixKey(volumeID)),
+		MaxKeys: aws.Int64(1),
+	})
+	if err != nil {
+		return false, goof.WithFieldsE(map[string]interface{}{
+			"bucket":   d.bucket,
+			"volumeID": volumeID,
+		}, "error listing s3 prefix", err)
+	}
+	return len(res.Contents) > 0, nil
+}
+
+func (d *driver) getPrefixVolume(
+	ctx types.Context,
+	volumeID string,
+	attachments types.VolumeAttachmentsTypes) (*types.Volume, error) {
+
+	svc, err := d.getServiceForBucket(ctx, d.bucket)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := d.prefixExists(ctx, svc, volumeID)
+	if err != nil {
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, err)
+	}
+	if !ok {
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
+	}
+	return d.toTypeVolume(ctx, volumeID, attachments), nil
+}
+
+func (d *driver) createPrefix(
+	ctx types.Context,
+	volumeName string) (*types.Volume, error) {
+
+	fields := map[string]interface{}{
+		"bucket":     d.bucket,
+		"volumeName": volumeName,
+	}
+
+	// a name with a slash would nest one volume's prefix inside another's
+	if volumeName == "" || strings.Contains(volumeName, "/") {
+		return nil, goof.WithFields(fields, "invalid s3fs volume name")
+	}
+
+	svc, err := d.getServiceForBucket(ctx, d.bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := d.prefixExists(ctx, svc, volumeName)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, apiUtils.NewAlreadyExistsErr(volumeName)
+	}
+
+	if _, err := svc.PutObject(&awss3.PutObjectInput{
+		Bucket: &d.bucket,
+		Key:    aws.String(prefixKey(volumeName)),
+		Body:   bytes.NewReader(nil),
+	}); err != nil {
+		ctx.WithFields(fields).WithError(err).Error(
+			"error creating s3 prefix")
+		return nil, goof.WithFieldsE(fields, "error creating s3 prefix", err)
+	}
+
+	return d.toTypeVolume(ctx, volumeName, types.VolAttNone), nil
+}
+
+// removePrefix removes the volume's prefix. Unless forced, the volume must
+// not contain any objects other than its prefix's marker, just as a bucket
+// must be empty to be removed.
+func (d *driver) removePrefix(
+	ctx types.Context,
+	volumeID string,
+	force bool) error {
+
+	fields := map[string]interface{}{
+		"bucket":   d.bucket,
+		"volumeID": volumeID,
+	}
+
+	svc, err := d.getServiceForBucket(ctx, d.bucket)
+	if err != nil {
+		return err
+	}
+
+	var (
+		keys   []*string
+		marker *string
+	)
+	for {
+		res, err := svc.ListObjects(&awss3.ListObjectsInput{
+			Bucket: &d.bucket,
+			Prefix: aws.String(prefixKey(volumeID)),
+			Marker: marker,
+		})
+		if err != nil {
+			ctx.WithFields(fields).WithError(err).Error(
+				"error listing objects")
+			return goof.WithFieldsE(fields, "error listing objects", err)
+		}
+		for _, obj := range res.Contents {
+			if obj.Key != nil {
+				keys = append(keys, obj.Key)
+			}
+		}
+		if res.IsTruncated == nil || !*res.IsTruncated || len(keys) == 0 {
+			break
+		}
+		marker = keys[len(keys)-1]
+		if res.NextMarker != nil {
+			marker = res.NextMarker
+		}
+	}
+
+	if len(keys) == 0 {
+		return apiUtils.NewVolumeNotFoundErr(volumeID, nil)
+	}
+	if !force && (len(keys) > 1 || *keys[0] != prefixKey(volumeID)) {
+		return goof.WithFields(fields, "s3fs volume is not empty")
+	}
+
+	for _, key := range keys {
+		if _, err := svc.DeleteObject(&awss3.DeleteObjectInput{
+			Bucket: &d.bucket,
+			Key:    key,
+		}); err != nil {
+			fields["key"] = *key
+			ctx.WithFields(fields).WithError(err).Error(
+				"error deleting object")
+			return goof.WithFieldsE(fields, "error deleting object", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package storage
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	awss3 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// testS3Server is an S3 endpoint that stores the keys of a single bucket's
+// objects in memory. It implements only the requests the driver sends when
+// volumes are prefixes within a bucket.
+type testS3Server struct {
+	sync.Mutex
+	bucket string
+	keys   map[string]bool
+}
+
+func (s *testS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if path == "" {
+		fmt.Fprintf(w, `<ListAllMyBucketsResult><Buckets><Bucket>`+
+			`<Name>%s</Name></Bucket></Buckets></ListAllMyBucketsResult>`,
+			s.bucket)
+		return
+	}
+
+	parts := strings.SplitN(path, "/", 2)
+	if parts[0] != s.bucket {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `<Error><Code>NoSuchBucket</Code></Error>`)
+		return
+	}
+	var key string
+	if len(parts) == 2 {
+		key = parts[1]
+	}
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		if _, ok := r.URL.Query()["location"]; ok {
+			fmt.Fprint(w, `<LocationConstraint></LocationConstraint>`)
+			return
+		}
+		s.list(w, r)
+	case r.Method == http.MethodPut:
+		s.keys[key] = true
+	case r.Method == http.MethodDelete:
+		delete(s.keys, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *testS3Server) list(w http.ResponseWriter, r *http.Request) {
+	var (
+		q         = r.URL.Query()
+		prefix    = q.Get("prefix")
+		delimiter = q.Get("delimiter")
+		marker    = q.Get("marker")
+		maxKeys   = 1000
+		keys      []string
+		contents  []string
+		prefixes  []string
+		truncated bool
+	)
+	if v := q.Get("max-keys"); v != "" {
+		maxKeys, _ = strconv.Atoi(v)
+	}
+	for k := range s.keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if !strings.HasPrefix(k, prefix) || (marker != "" && k <= marker) {
+			continue
+		}
+		if len(contents)+len(prefixes) >= maxKeys {
+			truncated = true
+			break
+		}
+		if delimiter != "" {
+			rest := k[len(prefix):]
+			if i := strings.Index(rest, delimiter); i >= 0 {
+				p := prefix + rest[:i+len(delimiter)]
+				if !seen[p] {
+					seen[p] = true
+					prefixes = append(prefixes, p)
+				}
+				continue
+			}
+		}
+		contents = append(contents, k)
+	}
+
+	fmt.Fprintf(w, `<ListBucketResult><Name>%s</Name>`+
+		`<IsTruncated>%v</IsTruncated>`, s.bucket, truncated)
+	for _, k := range contents {
+		fmt.Fprintf(w, `<Contents><Key>%s</Key><Size>0</Size></Contents>`, k)
+	}
+	for _, p := range prefixes {
+		fmt.Fprintf(w, `<CommonPrefixes><Prefix>%s</Prefix></CommonPrefixes>`, p)
+	}
+	fmt.Fprint(w, `</ListBucketResult>`)
+}
+
+func newTestPrefixDriver(t *testing.T) (*driver, *testS3Server, func()) {
+	s3 := &testS3Server{bucket: "shared", keys: map[string]bool{}}
+	srv := httptest.NewServer(s3)
+	d := &driver{
+		region:     "us-east-1",
+		accessKey:  "AKIAEXAMPLE",
+		secretKey:  "secretexample",
+		maxRetries: 0,
+		bucket:     s3.bucket,
+		endpoint:   srv.URL,
+		svcs:       map[string]*awss3.S3{},
+		svcsRWL:    &sync.RWMutex{},
+	}
+	return d, s3, srv.Close
+}
+
+func TestPrefixVolumes(t *testing.T) {
+	d, s3, closeSrv := newTestPrefixDriver(t)
+	defer closeSrv()
+
+	iid := &types.InstanceID{ID: "i-000", Driver: "s3fs"}
+	ctx := context.WithInstanceID(context.Background(), iid)
+
+	vol, err := d.VolumeCreate(ctx, "team-a", &types.VolumeCreateOpts{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "team-a", vol.ID)
+	}
+	assert.True(t, s3.keys["team-a/"], "prefix marker created")
+
+	_, err = d.VolumeCreate(ctx, "team-b", &types.VolumeCreateOpts{})
+	assert.NoError(t, err)
+	s3.keys["team-b/data/file.txt"] = true
+	s3.keys["readme.txt"] = true
+
+	// a volume's prefix that already holds objects
+	_, err = d.VolumeCreate(ctx, "team-b", &types.VolumeCreateOpts{})
+	assert.IsType(t, &types.ErrAlreadyExists{}, err)
+
+	// names that would nest one volume's prefix inside another's
+	_, err = d.VolumeCreate(ctx, "team-a/nested", &types.VolumeCreateOpts{})
+	assert.Error(t, err)
+	_, err = d.VolumeCreate(ctx, "", &types.VolumeCreateOpts{})
+	assert.Error(t, err)
+
+	vols, err := d.Volumes(ctx, &types.VolumesOpts{Attachments: types.VolAttReq})
+	if assert.NoError(t, err) && assert.Len(t, vols, 2) {
+		assert.Equal(t, "team-a", vols[0].ID)
+		assert.Equal(t, "team-b", vols[1].ID)
+		if assert.Len(t, vols[0].Attachments, 1) {
+			assert.Equal(t,
+				"shared:/team-a", vols[0].Attachments[0].DeviceName)
+		}
+	}
+
+	vol, err = d.VolumeInspect(
+		ctx, "team-b", &types.VolumeInspectOpts{Attachments: types.VolAttReq})
+	if assert.NoError(t, err) && assert.Len(t, vol.Attachments, 1) {
+		assert.Equal(t, "shared:/team-b", vol.Attachments[0].DeviceName)
+	}
+	_, err = d.VolumeInspect(ctx, "team-c", &types.VolumeInspectOpts{})
+	assert.IsType(t, &types.ErrVolumeNotFound{}, err)
+}
+
+func TestPrefixVolumeRemove(t *testing.T) {
+	d, s3, closeSrv := newTestPrefixDriver(t)
+	defer closeSrv()
+	ctx := context.Background()
+
+	s3.keys["team-a/"] = true
+	s3.keys["team-b/"] = true
+	s3.keys["team-b/data/file.txt"] = true
+	s3.keys["team-bb/"] = true
+
+	// an empty volume
+	assert.NoError(t, d.VolumeRemove(ctx, "team-a", &types.VolumeRemoveOpts{}))
+	assert.False(t, s3.keys["team-a/"])
+
+	// a volume that is not empty is only removed when forced
+	err := d.VolumeRemove(ctx, "team-b", &types.VolumeRemoveOpts{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "not empty")
+	}
+	assert.Len(t, s3.keys, 3)
+	assert.NoError(t, d.VolumeRemove(
+		ctx, "team-b", &types.VolumeRemoveOpts{Force: true}))
+	assert.Equal(t, map[string]bool{"team-bb/": true}, s3.keys,
+		"only the removed volume's objects are deleted")
+
+	err = d.VolumeRemove(ctx, "team-c", &types.VolumeRemoveOpts{})
+	assert.IsType(t, &types.ErrVolumeNotFound{}, err)
+}
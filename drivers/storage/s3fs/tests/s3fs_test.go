@@ -3,7 +3,12 @@ package s3fs
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
 	apitests "github.com/codedellemc/libstorage/api/tests"
+	"github.com/codedellemc/libstorage/api/types"
 
 	// load the driver packages
 	"github.com/codedellemc/libstorage/drivers/storage/s3fs"
@@ -13,3 +18,30 @@ import (
 func TestSuite(t *testing.T) {
 	apitests.RunSuite(t, s3fs.Name)
 }
+
+func TestUnsupported(t *testing.T) {
+	d, err := registry.NewStorageDriver(s3fs.Name)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	ctx := context.Background()
+
+	assertUnsupported := func(err error) {
+		assert.IsType(t, &types.ErrUnsupported{}, err)
+	}
+
+	_, err = d.VolumeCreateFromSnapshot(
+		ctx, "snap-1", "vol-1", &types.VolumeCreateOpts{})
+	assertUnsupported(err)
+	_, err = d.VolumeCopy(ctx, "vol-1", "vol-2", nil)
+	assertUnsupported(err)
+	_, err = d.VolumeSnapshot(ctx, "vol-1", "snap-1", nil)
+	assertUnsupported(err)
+	_, err = d.Snapshots(ctx, nil)
+	assertUnsupported(err)
+	_, err = d.SnapshotInspect(ctx, "snap-1", nil)
+	assertUnsupported(err)
+	_, err = d.SnapshotCopy(ctx, "snap-1", "snap-2", "us-west-2", nil)
+	assertUnsupported(err)
+	assertUnsupported(d.SnapshotRemove(ctx, "snap-1", nil))
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"net/url"
+	"os"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/drivers/storage/s3fs"
+)
+
+// Config is the configuration of the s3fs storage driver and executor. New
+// code should read the configuration with LoadConfig rather than reading the
+// s3fs properties from a gofig.Config directly.
+type Config struct {
+	// AccessKey is the AWS access key. Static credentials are used only if
+	// both AccessKey and SecretKey are set.
+	AccessKey string
+
+	// SecretKey is the AWS secret key.
+	SecretKey string
+
+	// Region is the AWS region. It defaults to us-east-1.
+	Region string
+
+	// MaxRetries is the number of times a failed S3 request is retried. It
+	// defaults to DefaultMaxRetries.
+	MaxRetries int
+
+	// DisablePathStyle is a flag that indicates whether or not buckets are
+	// named in the endpoint's host name rather than in the request path.
+	DisablePathStyle bool
+
+	// Bucket is the bucket in which volumes are prefixes. If it is empty
+	// then each volume is a bucket.
+	Bucket string
+
+	// Endpoint is the URL of an S3-compatible endpoint to use instead of
+	// AWS.
+	Endpoint string
+
+	// Tag is the prefix of the names of the buckets the driver manages.
+	Tag string
+
+	// Cmd is the path of the s3fs command. It defaults to "s3fs".
+	Cmd string
+
+	// HostName is the host name used as the instance ID. It defaults to the
+	// host's name.
+	HostName string
+
+	// Mount are the options passed to the s3fs command when mounting a
+	// bucket.
+	Mount *MountOptions
+}
+
+// LoadConfig reads the s3fs properties from the configuration, applies the
+// defaults of unset properties, and validates the result. An error that
+// names the offending property is returned if a value is invalid.
+func LoadConfig(config gofig.Config) (*Config, error) {
+	c := &Config{
+		AccessKey:        config.GetString(s3fs.ConfigS3FSAccessKey),
+		SecretKey:        config.GetString(s3fs.ConfigS3FSSecretKey),
+		Region:           config.GetString(s3fs.ConfigS3FSRegion),
+		MaxRetries:       s3fs.DefaultMaxRetries,
+		DisablePathStyle: config.GetBool(s3fs.ConfigS3FSDisablePathStyle),
+		Bucket:           config.GetString(s3fs.ConfigS3FSBucket),
+		Endpoint:         config.GetString(s3fs.ConfigS3FSEndpoint),
+		Tag:              config.GetString(s3fs.ConfigS3FSTag),
+		Cmd:              config.GetString(s3fs.ConfigS3FSCmd),
+		HostName:         config.GetString(s3fs.ConfigS3FSHostName),
+		Mount:            NewMountOptions(config),
+	}
+
+	if config.IsSet(s3fs.ConfigS3FSMaxRetries) {
+		c.MaxRetries = config.GetInt(s3fs.ConfigS3FSMaxRetries)
+	}
+	if c.Region == "" {
+		c.Region = s3fs.DefaultRegion
+	}
+	if c.Cmd == "" {
+		c.Cmd = s3fs.DefaultCmd
+	}
+	if c.HostName == "" {
+		c.HostName, _ = os.Hostname()
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate returns an error if a value of the configuration is invalid.
+func (c *Config) Validate() error {
+	if c.MaxRetries < 0 {
+		return goof.WithField(
+			s3fs.ConfigS3FSMaxRetries, c.MaxRetries,
+			"s3fs max retries must not be negative")
+	}
+	if (c.AccessKey == "") != (c.SecretKey == "") {
+		return goof.WithFields(goof.Fields{
+			"accessKeySet": c.AccessKey != "",
+			"secretKeySet": c.SecretKey != "",
+		}, "s3fs access key and secret key must be set together")
+	}
+	if c.Endpoint != "" {
+		if _, err := url.Parse(c.Endpoint); err != nil {
+			return goof.WithFieldE(
+				s3fs.ConfigS3FSEndpoint, c.Endpoint,
+				"invalid s3fs endpoint", err)
+		}
+	}
+	if c.Mount != nil {
+		if _, err := c.Mount.Args(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/akutz/goof"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awss3 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/codedellemc/libstorage/api/types"
+	apiUtils "github.com/codedellemc/libstorage/api/utils"
+	"github.com/codedellemc/libstorage/drivers/storage/s3fs"
+)
+
+func init() {
+	apiUtils.RegisterExportWriter("s3", newS3ExportWriter)
+	apiUtils.RegisterImportReader("s3", newS3ImportReader)
+}
+
+// parseS3URL returns the bucket, key, and region of a URL such as
+// s3://bucket/path/to/key?region=us-west-2. The region defaults to
+// us-east-1. The objects are accessed with the credentials read from the
+// environment, the shared credentials file, or the EC2 instance's role.
+func parseS3URL(u *url.URL) (bucket, key, region string, err error) {
+	bucket = u.Host
+	key = strings.TrimPrefix(u.Path, "/")
+	if bucket == "" || key == "" {
+		return "", "", "", goof.WithField(
+			"url", u.String(), "s3 url must include a bucket and key")
+	}
+	region = u.Query().Get("region")
+	if region == "" {
+		region = s3fs.DefaultRegion
+	}
+	return bucket, key, region, nil
+}
+
+var errExportAborted = goof.New("export aborted")
+
+// s3ExportWriter streams an export to an S3 object with a multipart upload.
+// The upload reads from a pipe, so the size of the export need not be known
+// in advance.
+type s3ExportWriter struct {
+	*io.PipeWriter
+	done chan error
+}
+
+// newS3ExportWriter returns a writer for a destination such as
+// s3://bucket/path/to/key?region=us-west-2.
+func newS3ExportWriter(
+	ctx types.Context, destination *url.URL) (apiUtils.ExportWriter, error) {
+
+	bucket, key, region, err := parseS3URL(destination)
+	if err != nil {
+		return nil, err
+	}
+	uploader := s3manager.NewUploader(
+		session.New(&aws.Config{Region: aws.String(region)}))
+
+	pr, pw := io.Pipe()
+	w := &s3ExportWriter{PipeWriter: pw, done: make(chan error, 1)}
+	go func() {
+		_, err := uploader.Upload(&s3manager.UploadInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+			Body:   pr,
+		})
+		pr.CloseWithError(err)
+		w.done <- err
+	}()
+
+	ctx.WithFields(map[string]interface{}{
+		"bucket": bucket,
+		"key":    key,
+		"region": region,
+	}).Debug("started s3 export")
+	return w, nil
+}
+
+func (w *s3ExportWriter) Close() error {
+	w.PipeWriter.Close()
+	return <-w.done
+}
+
+func (w *s3ExportWriter) Abort() error {
+	w.PipeWriter.CloseWithError(errExportAborted)
+	<-w.done
+	return nil
+}
+
+// newS3ImportReader returns a reader for a source such as
+// s3://bucket/path/to/key?region=us-west-2.
+func newS3ImportReader(
+	ctx types.Context, source *url.URL) (io.ReadCloser, error) {
+
+	bucket, key, region, err := parseS3URL(source)
+	if err != nil {
+		return nil, err
+	}
+	svc := awss3.New(session.New(&aws.Config{Region: aws.String(region)}))
+	res, err := svc.GetObject(&awss3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, goof.WithFieldsE(map[string]interface{}{
+			"bucket": bucket,
+			"key":    key,
+		}, "error reading s3 object", err)
+	}
+	return res.Body, nil
+}
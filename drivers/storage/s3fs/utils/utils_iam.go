@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// MetadataURL is the URL of the EC2 instance metadata service.
+var MetadataURL = "http://169.254.169.254/latest/meta-data/"
+
+// IAMRole returns the name of the IAM role of the EC2 instance's profile.
+// An empty string is returned if the host is not an EC2 instance or if the
+// instance does not have a role.
+func IAMRole(ctx types.Context) (string, error) {
+
+	req, err := http.NewRequest(
+		http.MethodGet, MetadataURL+"iam/security-credentials/", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Cancel = ctx.Done()
+
+	client := &http.Client{Timeout: time.Duration(1 * time.Second)}
+	res, err := client.Do(req)
+	if err != nil {
+		if _, ok := err.(net.Error); ok {
+			return "", nil
+		}
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", goof.WithField(
+			"status", res.StatusCode, "error getting iam role")
+	}
+
+	// the response lists one role per line, and an instance profile has
+	// at most one role
+	scanner := bufio.NewScanner(res.Body)
+	if scanner.Scan() {
+		return strings.TrimSpace(scanner.Text()), nil
+	}
+	return "", scanner.Err()
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/drivers/storage/s3fs"
+)
+
+// MountOptions are the options the executor passes to the s3fs command when
+// mounting a bucket. A zero value is not passed to the command, so the
+// command's own default is used.
+type MountOptions struct {
+	// Options are the s3fs options, such as "allow_other" or "umask=0022".
+	Options []string
+
+	// UseCache is the directory in which objects are cached.
+	UseCache string
+
+	// EnsureDiskFree is the MB of disk space to keep free.
+	EnsureDiskFree int
+
+	// ParallelCount is the number of parallel requests used for large
+	// objects.
+	ParallelCount int
+
+	// MultipartSize is the size, in MB, of the parts in which large objects
+	// are uploaded.
+	MultipartSize int
+
+	// IAMRole is the IAM role from which the s3fs command obtains temporary
+	// credentials. The command refreshes the credentials before they expire.
+	IAMRole string
+
+	// Endpoint is the URL of an S3-compatible endpoint.
+	Endpoint string
+
+	// PathStyle is a flag that indicates whether or not the bucket is named
+	// in the request path rather than in the endpoint's host name.
+	PathStyle bool
+}
+
+// NewMountOptions returns the mount options read from the configuration.
+// The s3fs.options property may be either a list of options or a string of
+// comma-separated options.
+func NewMountOptions(config gofig.Config) *MountOptions {
+	opts := &MountOptions{
+		UseCache:       config.GetString(s3fs.ConfigS3FSUseCache),
+		EnsureDiskFree: config.GetInt(s3fs.ConfigS3FSEnsureDiskFree),
+		ParallelCount:  config.GetInt(s3fs.ConfigS3FSParallelCount),
+		IAMRole:        config.GetString(s3fs.ConfigS3FSIAMRole),
+		MultipartSize:  config.GetInt(s3fs.ConfigS3FSMultipartSize),
+		Endpoint:       config.GetString(s3fs.ConfigS3FSEndpoint),
+	}
+
+	// the s3fs command uses virtual host style requests by default, which
+	// most S3-compatible endpoints do not support, so the driver's path
+	// style is used only with a custom endpoint
+	opts.PathStyle = opts.Endpoint != "" &&
+		!config.GetBool(s3fs.ConfigS3FSDisablePathStyle)
+
+	v := config.GetStringSlice(s3fs.ConfigS3FSOptions)
+	if len(v) == 0 {
+		v = []string{config.GetString(s3fs.ConfigS3FSOptions)}
+	}
+	for _, o := range v {
+		for _, so := range strings.Split(o, ",") {
+			so = strings.TrimPrefix(strings.TrimSpace(so), "-o")
+			if so = strings.TrimSpace(so); so != "" {
+				opts.Options = append(opts.Options, so)
+			}
+		}
+	}
+	return opts
+}
+
+// Args returns the command line arguments for the mount options. An error
+// is returned if an option's value is invalid or if the options conflict
+// with one another.
+func (o *MountOptions) Args() ([]string, error) {
+
+	named := map[string]string{}
+	var order []string
+	set := func(k, v string) error {
+		if ov, ok := named[k]; ok && ov != v {
+			return goof.WithFields(goof.Fields{
+				"option": k,
+				"value1": ov,
+				"value2": v,
+			}, "s3fs option specified with conflicting values")
+		} else if ok {
+			return nil
+		}
+		named[k] = v
+		order = append(order, k)
+		return nil
+	}
+
+	for _, opt := range o.Options {
+		k, v := opt, ""
+		if i := strings.Index(opt, "="); i > -1 {
+			k, v = opt[:i], opt[i+1:]
+		}
+		if err := set(k, v); err != nil {
+			return nil, err
+		}
+	}
+	if o.UseCache != "" {
+		if err := set("use_cache", o.UseCache); err != nil {
+			return nil, err
+		}
+	}
+	if o.IAMRole != "" {
+		if err := set("iam_role", o.IAMRole); err != nil {
+			return nil, err
+		}
+	}
+	if o.Endpoint != "" {
+		if err := set("url", o.Endpoint); err != nil {
+			return nil, err
+		}
+	}
+	if o.PathStyle {
+		if err := set("use_path_request_style", ""); err != nil {
+			return nil, err
+		}
+	}
+	if o.EnsureDiskFree != 0 {
+		if err := set(
+			"ensure_diskfree", strconv.Itoa(o.EnsureDiskFree)); err != nil {
+			return nil, err
+		}
+	}
+	if o.ParallelCount != 0 {
+		if err := set(
+			"parallel_count", strconv.Itoa(o.ParallelCount)); err != nil {
+			return nil, err
+		}
+	}
+	if o.MultipartSize != 0 {
+		if err := set(
+			"multipart_size", strconv.Itoa(o.MultipartSize)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, k := range []string{"ensure_diskfree", "parallel_count"} {
+		v, ok := named[k]
+		if !ok {
+			continue
+		}
+		if i, err := strconv.Atoi(v); err != nil || i < 0 ||
+			(k == "parallel_count" && i == 0) {
+			return nil, goof.WithFields(goof.Fields{
+				"option": k,
+				"value":  v,
+			}, "invalid s3fs option value")
+		}
+	}
+
+	if v, ok := named["multipart_size"]; ok {
+		if i, err := strconv.Atoi(v); err != nil ||
+			i < s3fs.MinMultipartSize || i > s3fs.MaxMultipartSize {
+			return nil, goof.WithField("value", v, fmt.Sprintf(
+				"s3fs multipart_size must be between %d and %d MB",
+				s3fs.MinMultipartSize, s3fs.MaxMultipartSize))
+		}
+	}
+
+	if _, ok := named["check_cache_dir_exist"]; ok {
+		if named["use_cache"] == "" {
+			return nil, goof.WithField(
+				"option", "check_cache_dir_exist",
+				"s3fs option requires use_cache")
+		}
+	}
+
+	var args []string
+	for _, k := range order {
+		if v := named[k]; v != "" {
+			args = append(args, fmt.Sprintf("-o%s=%s", k, v))
+		} else {
+			args = append(args, fmt.Sprintf("-o%s", k))
+		}
+	}
+	return args, nil
+}
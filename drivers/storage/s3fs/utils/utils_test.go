@@ -1,13 +1,19 @@
 package utils
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"strconv"
 	"testing"
 
+	gofigCore "github.com/akutz/gofig"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/drivers/storage/s3fs"
 )
 
 func skipTest(t *testing.T) {
@@ -24,3 +30,169 @@ func TestInstanceID(t *testing.T) {
 	}
 	t.Logf("instanceID=%s", iid.String())
 }
+
+func TestMountOptionsArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *MountOptions
+		args []string
+		err  string
+	}{
+		{"none", &MountOptions{}, nil, ""},
+		{"options",
+			&MountOptions{Options: []string{"allow_other", "umask=0022"}},
+			[]string{"-oallow_other", "-oumask=0022"}, ""},
+		{"specific",
+			&MountOptions{
+				UseCache:       "/tmp/s3fs",
+				EnsureDiskFree: 1024,
+				ParallelCount:  10,
+			},
+			[]string{
+				"-ouse_cache=/tmp/s3fs",
+				"-oensure_diskfree=1024",
+				"-oparallel_count=10",
+			}, ""},
+		{"duplicate",
+			&MountOptions{
+				Options:       []string{"parallel_count=10"},
+				ParallelCount: 10,
+			},
+			[]string{"-oparallel_count=10"}, ""},
+		{"conflict",
+			&MountOptions{
+				Options:       []string{"parallel_count=5"},
+				ParallelCount: 10,
+			},
+			nil, "conflicting values"},
+		{"invalid value",
+			&MountOptions{Options: []string{"parallel_count=many"}},
+			nil, "invalid s3fs option value"},
+		{"multipart size",
+			&MountOptions{MultipartSize: 64, ParallelCount: 20},
+			[]string{"-oparallel_count=20", "-omultipart_size=64"}, ""},
+		{"multipart size too small",
+			&MountOptions{MultipartSize: 4},
+			nil, "multipart_size must be between"},
+		{"multipart size too large",
+			&MountOptions{Options: []string{"multipart_size=6000"}},
+			nil, "multipart_size must be between"},
+		{"iam role",
+			&MountOptions{IAMRole: "role-a"},
+			[]string{"-oiam_role=role-a"}, ""},
+		{"endpoint",
+			&MountOptions{Endpoint: "http://minio:9000", PathStyle: true},
+			[]string{"-ourl=http://minio:9000", "-ouse_path_request_style"},
+			""},
+		{"requires cache",
+			&MountOptions{Options: []string{"check_cache_dir_exist"}},
+			nil, "requires use_cache"},
+	}
+
+	for _, tt := range tests {
+		args, err := tt.opts.Args()
+		if tt.err != "" {
+			if assert.Error(t, err, tt.name) {
+				assert.Contains(t, err.Error(), tt.err, tt.name)
+			}
+			continue
+		}
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.args, args, tt.name)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	config := gofigCore.New()
+	cfg, err := LoadConfig(config)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, s3fs.DefaultRegion, cfg.Region)
+	assert.Equal(t, s3fs.DefaultMaxRetries, cfg.MaxRetries)
+	assert.Equal(t, s3fs.DefaultCmd, cfg.Cmd)
+	assert.NotEmpty(t, cfg.HostName)
+
+	config.Set(s3fs.ConfigS3FSRegion, "eu-west-1")
+	config.Set(s3fs.ConfigS3FSMaxRetries, 0)
+	config.Set(s3fs.ConfigS3FSAccessKey, "access")
+	config.Set(s3fs.ConfigS3FSSecretKey, "secret")
+	cfg, err = LoadConfig(config)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "eu-west-1", cfg.Region)
+	assert.Equal(t, 0, cfg.MaxRetries)
+	assert.Equal(t, "access", cfg.AccessKey)
+
+	tests := []struct {
+		name string
+		key  string
+		val  interface{}
+		err  string
+	}{
+		{"negative max retries",
+			s3fs.ConfigS3FSMaxRetries, -1, "must not be negative"},
+		{"access key only",
+			s3fs.ConfigS3FSSecretKey, "", "must be set together"},
+		{"invalid endpoint",
+			s3fs.ConfigS3FSEndpoint, "http://[::1", "invalid s3fs endpoint"},
+		{"invalid mount option",
+			s3fs.ConfigS3FSOptions, "parallel_count=many",
+			"invalid s3fs option value"},
+	}
+
+	for _, tt := range tests {
+		config := gofigCore.New()
+		config.Set(tt.key, tt.val)
+		if tt.key == s3fs.ConfigS3FSSecretKey {
+			config.Set(s3fs.ConfigS3FSAccessKey, "access")
+		}
+		_, err := LoadConfig(config)
+		if assert.Error(t, err, tt.name) {
+			assert.Contains(t, err.Error(), tt.err, tt.name)
+		}
+	}
+}
+
+func TestParseS3URL(t *testing.T) {
+	u, _ := url.Parse("s3://backups/snaps/snap-000.tar?region=us-west-2")
+	bucket, key, region, err := parseS3URL(u)
+	assert.NoError(t, err)
+	assert.Equal(t, "backups", bucket)
+	assert.Equal(t, "snaps/snap-000.tar", key)
+	assert.Equal(t, "us-west-2", region)
+
+	u, _ = url.Parse("s3://backups/snap-000.tar")
+	_, _, region, err = parseS3URL(u)
+	assert.NoError(t, err)
+	assert.Equal(t, s3fs.DefaultRegion, region)
+
+	u, _ = url.Parse("s3://backups/")
+	_, _, _, err = parseS3URL(u)
+	assert.Error(t, err)
+}
+
+func TestIAMRole(t *testing.T) {
+	var roles = []string{"role-a\n", "role-b"}
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/iam/security-credentials/" || len(roles) == 0 {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, roles[0])
+			roles = roles[1:]
+		}))
+	defer srv.Close()
+
+	defer func(u string) { MetadataURL = u }(MetadataURL)
+	MetadataURL = srv.URL + "/"
+
+	ctx := context.Background()
+	for _, expected := range []string{"role-a", "role-b", ""} {
+		role, err := IAMRole(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, role)
+	}
+}
@@ -13,3 +13,9 @@ var NextDeviceInfo = &types.NextDeviceInfo{
 	Pattern: "",
 	Ignore:  true,
 }
+
+// NextDevice returns the next available device. S3FS buckets are mounted
+// with FUSE and so do not use devices on this platform.
+func NextDevice(ctx types.Context) (string, error) {
+	return "", types.ErrNotImplemented
+}
@@ -0,0 +1,38 @@
+// +build windows
+
+package utils
+
+import (
+	"syscall"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// NextDeviceInfo is the NextDeviceInfo object for S3FS. Windows devices
+// are drive letters, ex. "E:".
+var NextDeviceInfo = &types.NextDeviceInfo{
+	Prefix:  "",
+	Pattern: `[A-Z]:`,
+	Ignore:  true,
+}
+
+var getLogicalDrives = syscall.NewLazyDLL(
+	"kernel32.dll").NewProc("GetLogicalDrives")
+
+// NextDevice returns the first drive letter not in use by the local host.
+// The drive letters A: through C: are skipped as they are reserved for the
+// floppy and system drives.
+func NextDevice(ctx types.Context) (string, error) {
+	mask, _, err := getLogicalDrives.Call()
+	if mask == 0 {
+		return "", goof.WithError("error getting logical drives", err)
+	}
+	for i := uint('D' - 'A'); i < 26; i++ {
+		if mask&(1<<i) == 0 {
+			return string(rune('A'+i)) + ":", nil
+		}
+	}
+	return "", goof.New("no available drive letters")
+}
@@ -43,7 +43,7 @@ func (d *driver) Name() string {
 	return scaleio.Name
 }
 
-func (d *driver) Init(context types.Context, config gofig.Config) error {
+func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	d.config = config
 	fields := eff(map[string]interface{}{
 		"endpoint": d.endpoint(),
@@ -51,7 +51,7 @@ func (d *driver) Init(context types.Context, config gofig.Config) error {
 		"useCerts": d.useCerts(),
 	})
 
-	log.WithFields(fields).Debug("starting scaleio driver")
+	ctx.WithFields(fields).Debug("starting scaleio driver")
 
 	var err error
 
@@ -73,7 +73,7 @@ func (d *driver) Init(context types.Context, config gofig.Config) error {
 		if d.password() != "" {
 			fields["password"] = "******"
 		}
-		log.WithFields(fields).Debug(err.Error())
+		ctx.WithFields(fields).Debug(err.Error())
 		return goof.WithFieldsE(fields, "error authenticating", err)
 	}
 
@@ -82,7 +82,7 @@ func (d *driver) Init(context types.Context, config gofig.Config) error {
 		d.systemName(), ""); err != nil {
 		fields["systemId"] = d.systemID()
 		fields["systemName"] = d.systemName()
-		log.WithFields(fields).Debug(err.Error())
+		ctx.WithFields(fields).Debug(err.Error())
 		return goof.WithFieldsE(fields, "error finding system", err)
 	}
 
@@ -92,7 +92,7 @@ func (d *driver) Init(context types.Context, config gofig.Config) error {
 		d.protectionDomainName(), ""); err != nil {
 		fields["domainId"] = d.protectionDomainID()
 		fields["domainName"] = d.protectionDomainName()
-		log.WithFields(fields).Debug(err.Error())
+		ctx.WithFields(fields).Debug(err.Error())
 		return goof.WithFieldsE(fields,
 			"error finding protection domain", err)
 	}
@@ -105,13 +105,13 @@ func (d *driver) Init(context types.Context, config gofig.Config) error {
 		d.storagePoolName(), ""); err != nil {
 		fields["storagePoolId"] = d.storagePoolID()
 		fields["storagePoolName"] = d.storagePoolName()
-		log.WithFields(fields).Debug(err.Error())
+		ctx.WithFields(fields).Debug(err.Error())
 		return goof.WithFieldsE(fields, "error finding storage pool", err)
 	}
 	d.storagePool = sio.NewStoragePool(d.client)
 	d.storagePool.StoragePool = sp
 
-	log.WithFields(fields).Info("storage driver initialized")
+	ctx.WithFields(fields).Info("storage driver initialized")
 
 	return nil
 }
@@ -120,6 +120,14 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Block, nil
 }
 
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsSnapshots: true,
+		SupportsClone:     true,
+	}, nil
+}
+
 func (d *driver) NextDeviceInfo(
 	ctx types.Context) (*types.NextDeviceInfo, error) {
 	return nil, nil
@@ -220,7 +228,7 @@ func (d *driver) Volumes(
 				attachmentSD := &types.VolumeAttachment{
 					VolumeID:   volume.ID,
 					InstanceID: instanceID,
-					Status:     "",
+					Status:     types.AttachmentStatusAttached,
 				}
 				if devName, ok := sdcMappedVolumes[volume.ID]; ok {
 					attachmentSD.DeviceName = devName
@@ -271,7 +279,7 @@ func (d *driver) VolumeInspect(
 	}
 
 	if len(volumes) == 0 {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
 	mapStoragePoolName, err := d.getStoragePoolIDs()
@@ -321,7 +329,7 @@ func (d *driver) VolumeInspect(
 				VolumeID:   volume.ID,
 				InstanceID: instanceID,
 				DeviceName: deviceName,
-				Status:     "",
+				Status:     types.AttachmentStatusAttached,
 			}
 			attachmentsSD = append(attachmentsSD, attachmentSD)
 		}
@@ -354,7 +362,7 @@ func (d *driver) VolumeCreate(ctx types.Context, volumeName string,
 		"opts":       opts,
 	})
 
-	log.WithFields(fields).Debug("creating volume")
+	ctx.WithFields(fields).Debug("creating volume")
 
 	volume := &types.Volume{}
 
@@ -408,9 +416,7 @@ func (d *driver) VolumeCreateFromSnapshot(
 	}
 
 	if len(volumes) > 0 {
-		return nil, goof.WithFields(eff(map[string]interface{}{
-			"volumeName": volumeName}),
-			"volume name already exists")
+		return nil, apiUtils.NewAlreadyExistsErr(volumeName)
 	}
 
 	resp, err := d.VolumeCreate(ctx, volumeName, opts)
@@ -428,7 +434,7 @@ func (d *driver) VolumeCreateFromSnapshot(
 		return nil, err
 	}
 
-	log.WithFields(log.Fields{
+	ctx.WithFields(log.Fields{
 		"provider": "scaleIO",
 		"volume":   createdVolume,
 	}).Debug("created volume")
@@ -472,7 +478,7 @@ func (d *driver) VolumeRemove(
 		return goof.WithFieldsE(fields, "error removing volume", err)
 	}
 
-	log.WithFields(fields).Debug("removed volume")
+	ctx.WithFields(fields).Debug("removed volume")
 	return nil
 }
 
@@ -541,7 +547,7 @@ func (d *driver) VolumeDetach(
 	}
 
 	if len(volumes) == 0 {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
 	targetVolume := sio.NewVolume(d.client)
@@ -54,10 +54,17 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 		"userName":        d.username(),
 		"tls":             d.tls(),
 		"volumePath":      d.volumePath(),
+		"volumeFormat":    d.volumeFormat(),
 		"controllerName":  d.controllerName(),
 		"machineNameOrId": d.machineNameID(""),
 	}
 
+	switch d.volumeFormat() {
+	case "vmdk", "vdi":
+	default:
+		return goof.WithFields(fields, "invalid volume format")
+	}
+
 	ctx.Info("initializing driver: ", fields)
 	d.vbox = vboxc.New(d.username(), d.password(),
 		d.endpoint(), d.tls(), d.controllerName())
@@ -93,6 +100,12 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Block, nil
 }
 
+// Capabilities returns the features supported by the driver.
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{}, nil
+}
+
 // NextDeviceInfo returns the information about the driver's next available
 // device workflow.
 func (d *driver) NextDeviceInfo(
@@ -252,7 +265,7 @@ func (d *driver) VolumeCreate(ctx types.Context, volumeName string,
 	}
 
 	if vol != nil {
-		return nil, goof.New("volume already exists")
+		return nil, apiUtils.NewAlreadyExistsErr(volumeName)
 	}
 
 	med, err := d.createVolume(ctx, volumeName, size)
@@ -346,11 +359,11 @@ func (d *driver) VolumeAttach(
 	}
 
 	if len(volumes) == 0 {
-		return nil, "", apiUtils.NewNotFoundError(volumeID)
+		return nil, "", apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
 	if len(volumes[0].Attachments) > 0 && !opts.Force {
-		return nil, "", goof.New("volume already attached to a host")
+		return nil, "", apiUtils.NewVolumeAlreadyAttachedErr(volumeID)
 	}
 	if opts.Force {
 		if _, err = d.VolumeDetach(ctx, volumeID, nil); err != nil {
@@ -403,7 +416,7 @@ func (d *driver) VolumeDetach(
 	}
 
 	if len(volumes) == 0 {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
 	// TODO: Check if volumes[[0].Attachments > 0?
@@ -431,28 +444,28 @@ func (d *driver) VolumeDetachAll(
 func (d *driver) Snapshots(
 	ctx types.Context,
 	opts types.Store) ([]*types.Snapshot, error) {
-	return nil, nil
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
 }
 
 func (d *driver) SnapshotInspect(
 	ctx types.Context,
 	snapshotID string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, nil
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
 }
 
 func (d *driver) SnapshotCopy(
 	ctx types.Context,
 	snapshotID, snapshotName, destinationID string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, nil
+	return nil, apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
 }
 
 func (d *driver) SnapshotRemove(
 	ctx types.Context,
 	snapshotID string,
 	opts types.Store) error {
-	return nil
+	return apiUtils.NewUnsupportedErr(d.Name(), "snapshots")
 }
 
 func (d *driver) Volumes(
@@ -484,7 +497,7 @@ func (d *driver) VolumeInspect(
 		return nil, err
 	}
 	if len(vols) == 0 {
-		return nil, apiUtils.NewNotFoundError(volumeID)
+		return nil, apiUtils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 	return vols[0], nil
 }
@@ -541,6 +554,7 @@ func (d *driver) getVolume(
 						ID:     mid,
 						Driver: vbox.Name,
 					},
+					Status: types.AttachmentStatusAttached,
 				}
 				if attachments.Devices() && mapDN != nil {
 					dn, _ := mapDN[v.ID]
@@ -670,8 +684,9 @@ func (d *driver) createVolume(
 		return nil, goof.New("name is empty")
 	}
 	path := filepath.Join(d.volumePath(), name)
-	ctx.WithField("path", path).Debug("creating vmdk")
-	return d.vbox.CreateMedium("vmdk", path, size)
+	format := d.volumeFormat()
+	ctx.WithField("path", path).Debug("creating " + format)
+	return d.vbox.CreateMedium(format, path, size)
 }
 
 func (d *driver) attachVolume(
@@ -758,6 +773,10 @@ func (d *driver) volumePath() string {
 	return d.config.GetString("virtualbox.volumePath")
 }
 
+func (d *driver) volumeFormat() string {
+	return strings.ToLower(d.config.GetString("virtualbox.volumeFormat"))
+}
+
 func (d *driver) controllerName() string {
 	return d.config.GetString("virtualbox.controllerName")
 }
@@ -16,6 +16,7 @@ func init() {
 	r.Key(gofig.String, "", "", "", "virtualbox.password")
 	r.Key(gofig.String, "", "http://10.0.2.2:18083", "", "virtualbox.endpoint")
 	r.Key(gofig.String, "", "", "", "virtualbox.volumePath")
+	r.Key(gofig.String, "", "vmdk", "", "virtualbox.volumeFormat")
 	r.Key(gofig.String, "", "", "", "virtualbox.localMachineNameOrId")
 	r.Key(gofig.Bool, "", false, "", "virtualbox.tls")
 	r.Key(gofig.String, "", "SATA", "", "virtualbox.controllerName")
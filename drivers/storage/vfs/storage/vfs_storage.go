@@ -94,6 +94,27 @@ func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Object, nil
 }
 
+func (d *driver) Capabilities(
+	ctx types.Context) (*types.DriverCapabilities, error) {
+	return &types.DriverCapabilities{
+		SupportsSnapshots:    true,
+		SupportsResize:       true,
+		SupportsClone:        true,
+		SupportsMultiAttach:  true,
+		SupportsEncryption:   true,
+		SupportsCopySnapshot: true,
+		SupportsThroughput:   true,
+	}, nil
+}
+
+func (d *driver) Ping(ctx types.Context) error {
+	if _, err := os.Stat(d.volPath); err != nil {
+		return err
+	}
+	_, err := os.Stat(d.snapPath)
+	return err
+}
+
 func (d *driver) NextDeviceInfo(
 	ctx types.Context) (*types.NextDeviceInfo, error) {
 	return &types.NextDeviceInfo{
@@ -220,7 +241,7 @@ func (d *driver) VolumeCreate(
 			fields["encrypted"] = *opts.Encrypted
 		}
 		if opts.EncryptionKey != nil {
-			fields["encryptionKey"] = *opts.EncryptionKey
+			fields["encryptionKey"] = utils.Redacted
 		}
 		if opts.IOPS != nil {
 			fields["iops"] = *opts.IOPS
@@ -242,9 +263,10 @@ func (d *driver) VolumeCreate(
 	context.MustSession(ctx)
 
 	v := &types.Volume{
-		ID:     d.newVolumeID(),
-		Name:   name,
-		Fields: map[string]string{},
+		ID:         d.newVolumeID(),
+		Name:       name,
+		CreateTime: time.Now().Unix(),
+		Fields:     map[string]string{},
 	}
 
 	if opts.AvailabilityZone != nil {
@@ -253,6 +275,9 @@ func (d *driver) VolumeCreate(
 	if opts.IOPS != nil {
 		v.IOPS = *opts.IOPS
 	}
+	if opts.Throughput != nil {
+		v.Throughput = *opts.Throughput
+	}
 	v.Size = *opts.Size
 
 	if opts.Type != nil {
@@ -261,6 +286,12 @@ func (d *driver) VolumeCreate(
 	if opts.Encrypted != nil {
 		v.Encrypted = *opts.Encrypted
 	}
+	if v.Encrypted && opts.EncryptionKey != nil {
+		v.EncryptionKey = *opts.EncryptionKey
+	}
+	if len(opts.Tags) > 0 {
+		v.Tags = opts.Tags
+	}
 	if customFields := opts.Opts.GetStore("opts"); customFields != nil {
 		for _, k := range customFields.Keys() {
 			v.Fields[k] = customFields.GetString(k)
@@ -297,6 +328,7 @@ func (d *driver) VolumeCreateFromSnapshot(
 	v := &types.Volume{
 		ID:               d.newVolumeID(),
 		Name:             volumeName,
+		CreateTime:       time.Now().Unix(),
 		Fields:           ogVol.Fields,
 		AvailabilityZone: ogVol.AvailabilityZone,
 		IOPS:             ogVol.IOPS,
@@ -347,6 +379,7 @@ func (d *driver) VolumeCopy(
 	newVol := &types.Volume{
 		ID:               d.newVolumeID(),
 		Name:             volumeName,
+		CreateTime:       time.Now().Unix(),
 		AvailabilityZone: ogVol.AvailabilityZone,
 		IOPS:             ogVol.IOPS,
 		Size:             ogVol.Size,
@@ -387,8 +420,10 @@ func (d *driver) VolumeSnapshot(
 		VolumeID:   v.ID,
 		VolumeSize: v.Size,
 		Name:       snapshotName,
-		Status:     "online",
+		Status:     types.SnapshotStatusCompleted,
+		Progress:   100,
 		StartTime:  time.Now().Unix(),
+		ExpireTime: opts.GetInt64(types.SnapshotExpireTimeKey),
 		Fields:     v.Fields,
 	}
 
@@ -414,7 +449,7 @@ func (d *driver) VolumeRemove(
 
 	volJSONPath := d.getVolPath(volumeID)
 	if !gotil.FileExists(volJSONPath) {
-		return utils.NewNotFoundError(volumeID)
+		return utils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 	os.Remove(volJSONPath)
 
@@ -423,6 +458,69 @@ func (d *driver) VolumeRemove(
 	return nil
 }
 
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	size int64,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	context.MustSession(ctx)
+
+	vol, err := d.getVolumeByID(volumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	vol.Size = size
+	if err := d.writeVolume(vol); err != nil {
+		return nil, err
+	}
+
+	return vol, nil
+}
+
+func (d *driver) VolumeSetTags(
+	ctx types.Context,
+	volumeID string,
+	tags map[string]string,
+	opts types.Store) error {
+
+	context.MustSession(ctx)
+
+	vol, err := d.getVolumeByID(volumeID)
+	if err != nil {
+		return err
+	}
+
+	if vol.Tags == nil {
+		vol.Tags = map[string]string{}
+	}
+	for k, v := range tags {
+		vol.Tags[k] = v
+	}
+
+	return d.writeVolume(vol)
+}
+
+func (d *driver) VolumeRename(
+	ctx types.Context,
+	volumeID, newName string,
+	opts types.Store) (*types.Volume, error) {
+
+	context.MustSession(ctx)
+
+	vol, err := d.getVolumeByID(volumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	vol.Name = newName
+	if err := d.writeVolume(vol); err != nil {
+		return nil, err
+	}
+	return vol, nil
+}
+
 func (d *driver) VolumeAttach(
 	ctx types.Context,
 	volumeID string,
@@ -446,6 +544,14 @@ func (d *driver) VolumeAttach(
 		return nil, "", err
 	}
 
+	iid := context.MustInstanceID(ctx)
+	for _, a := range vol.Attachments {
+		if a.InstanceID != nil && a.InstanceID.ID != iid.ID &&
+			!opts.MultiAttach && !opts.Force {
+			return nil, "", utils.NewVolumeAlreadyAttachedErr(volumeID)
+		}
+	}
+
 	nextDevice := ""
 	if opts.NextDevice != nil {
 		nextDevice = *opts.NextDevice
@@ -455,7 +561,7 @@ func (d *driver) VolumeAttach(
 		VolumeID:   vol.ID,
 		InstanceID: context.MustInstanceID(ctx),
 		DeviceName: nextDevice,
-		Status:     "attached",
+		Status:     types.AttachmentStatusAttached,
 	}
 
 	vol.Attachments = append(vol.Attachments, att)
@@ -539,6 +645,25 @@ func (d *driver) SnapshotInspect(
 	return snap, nil
 }
 
+func (d *driver) SnapshotRename(
+	ctx types.Context,
+	snapshotID, newName string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	context.MustSession(ctx)
+
+	snap, err := d.getSnapshotByID(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	snap.Name = newName
+	if err := d.writeSnapshot(snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
 func (d *driver) SnapshotCopy(
 	ctx types.Context,
 	snapshotID, snapshotName, destinationID string,
@@ -555,8 +680,10 @@ func (d *driver) SnapshotCopy(
 		ID:         d.newSnapshotID(ogSnap.VolumeID),
 		VolumeID:   ogSnap.VolumeID,
 		VolumeSize: ogSnap.VolumeSize,
+		Size:       ogSnap.Size,
 		Name:       snapshotName,
-		Status:     "online",
+		Status:     types.SnapshotStatusCompleted,
+		Progress:   100,
 		StartTime:  time.Now().Unix(),
 		Fields:     ogSnap.Fields,
 	}
@@ -583,7 +710,7 @@ func (d *driver) SnapshotRemove(
 
 	snapJSONPath := d.getSnapPath(snapshotID)
 	if !gotil.FileExists(snapJSONPath) {
-		return utils.NewNotFoundError(snapshotID)
+		return utils.NewSnapshotNotFoundErr(snapshotID, nil)
 	}
 	os.Remove(snapJSONPath)
 	return nil
@@ -0,0 +1,30 @@
+// +build !windows
+
+package storage
+
+import (
+	"syscall"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func (d *driver) StorageCapacity(
+	ctx types.Context,
+	availabilityZone string,
+	opts types.Store) (*types.StorageCapacity, error) {
+
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(d.volPath, &st); err != nil {
+		return nil, err
+	}
+
+	bsize := int64(st.Bsize)
+	total := int64(st.Blocks) * bsize
+	avail := int64(st.Bavail) * bsize
+
+	return &types.StorageCapacity{
+		TotalBytes:     total,
+		UsedBytes:      total - int64(st.Bfree)*bsize,
+		AvailableBytes: avail,
+	}, nil
+}
@@ -0,0 +1,16 @@
+// +build windows
+
+package storage
+
+import (
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+func (d *driver) StorageCapacity(
+	ctx types.Context,
+	availabilityZone string,
+	opts types.Store) (*types.StorageCapacity, error) {
+
+	return nil, utils.NewUnsupportedErr(d.Name(), "storage capacity")
+}
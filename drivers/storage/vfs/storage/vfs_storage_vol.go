@@ -22,7 +22,7 @@ func (d *driver) getVolumeByID(volumeID string) (*types.Volume, error) {
 	volJSONPath := d.getVolPath(volumeID)
 
 	if !gotil.FileExists(volJSONPath) {
-		return nil, utils.NewNotFoundError(volumeID)
+		return nil, utils.NewVolumeNotFoundErr(volumeID, nil)
 	}
 
 	return readVolume(volJSONPath)
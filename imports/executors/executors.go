@@ -5,7 +5,10 @@
 // +build !efs
 // +build !fittedcloud
 // +build !gcepd
+// +build !iscsi
 // +build !isilon
+// +build !loopback
+// +build !nfs
 // +build !rbd
 // +build !s3fs
 // +build !scaleio
@@ -23,7 +26,10 @@ import (
 	_ "github.com/codedellemc/libstorage/drivers/storage/efs/executor"
 	_ "github.com/codedellemc/libstorage/drivers/storage/fittedcloud/executor"
 	_ "github.com/codedellemc/libstorage/drivers/storage/gcepd/executor"
+	_ "github.com/codedellemc/libstorage/drivers/storage/iscsi/executor"
 	_ "github.com/codedellemc/libstorage/drivers/storage/isilon/executor"
+	_ "github.com/codedellemc/libstorage/drivers/storage/loopback/executor"
+	_ "github.com/codedellemc/libstorage/drivers/storage/nfs/executor"
 	_ "github.com/codedellemc/libstorage/drivers/storage/rbd/executor"
 	_ "github.com/codedellemc/libstorage/drivers/storage/s3fs/executor"
 	_ "github.com/codedellemc/libstorage/drivers/storage/scaleio/executor"
@@ -0,0 +1,8 @@
+// +build iscsi
+
+package executors
+
+import (
+	// load the packages
+	_ "github.com/codedellemc/libstorage/drivers/storage/iscsi/executor"
+)
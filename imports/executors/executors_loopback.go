@@ -0,0 +1,8 @@
+// +build loopback
+
+package executors
+
+import (
+	// load the packages
+	_ "github.com/codedellemc/libstorage/drivers/storage/loopback/executor"
+)
@@ -0,0 +1,8 @@
+// +build nfs
+
+package executors
+
+import (
+	// load the packages
+	_ "github.com/codedellemc/libstorage/drivers/storage/nfs/executor"
+)
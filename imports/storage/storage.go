@@ -5,7 +5,10 @@
 // +build !efs
 // +build !fittedcloud
 // +build !gcepd
+// +build !iscsi
 // +build !isilon
+// +build !loopback
+// +build !nfs
 // +build !rbd
 // +build !s3fs
 // +build !scaleio
@@ -23,7 +26,10 @@ import (
 	_ "github.com/codedellemc/libstorage/drivers/storage/efs/storage"
 	_ "github.com/codedellemc/libstorage/drivers/storage/fittedcloud/storage"
 	_ "github.com/codedellemc/libstorage/drivers/storage/gcepd/storage"
+	_ "github.com/codedellemc/libstorage/drivers/storage/iscsi/storage"
 	_ "github.com/codedellemc/libstorage/drivers/storage/isilon/storage"
+	_ "github.com/codedellemc/libstorage/drivers/storage/loopback/storage"
+	_ "github.com/codedellemc/libstorage/drivers/storage/nfs/storage"
 	_ "github.com/codedellemc/libstorage/drivers/storage/rbd/storage"
 	_ "github.com/codedellemc/libstorage/drivers/storage/s3fs/storage"
 	_ "github.com/codedellemc/libstorage/drivers/storage/scaleio/storage"
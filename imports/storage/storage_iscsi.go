@@ -0,0 +1,8 @@
+// +build iscsi
+
+package storage
+
+import (
+	// load the packages
+	_ "github.com/codedellemc/libstorage/drivers/storage/iscsi/storage"
+)
@@ -28,6 +28,7 @@ TEST_DRIVERS_COVR := ./drivers/storage/vfs/tests/vfs.test.out
 TEST_FRAMEWORK_PKGS :=  ./api/context \
   ./api/server/auth \
   ./api/types \
+  ./api/utils/exec \
   ./api/utils/filters \
   ./api/utils/schema \
   ./api/utils